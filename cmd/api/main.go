@@ -2,8 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"os/signal"
@@ -12,51 +13,88 @@ import (
 
 	"github.com/Alfian57/belajar-golang/internal/config"
 	"github.com/Alfian57/belajar-golang/internal/database"
+	"github.com/Alfian57/belajar-golang/internal/health"
 	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/mailer"
 	"github.com/Alfian57/belajar-golang/internal/middleware"
+	"github.com/Alfian57/belajar-golang/internal/migrate"
 	"github.com/Alfian57/belajar-golang/internal/router"
+	"github.com/Alfian57/belajar-golang/internal/tracing"
+	"github.com/Alfian57/belajar-golang/internal/utils/password"
 	"github.com/Alfian57/belajar-golang/internal/validation"
-	"github.com/gin-contrib/cors"
+	"github.com/Alfian57/belajar-golang/migrations"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	runMigrations := flag.Bool("migrate", false, "Apply pending database migrations before starting the server")
+	sources := config.BindFlags(flag.CommandLine)
+	flag.Parse()
+
 	config.LoadEnv()
 
-	cfg, err := config.Load()
+	// Gin reads GIN_MODE before .env is loaded
+	if mode := os.Getenv(gin.EnvGinMode); mode != "" {
+		gin.SetMode(mode)
+	}
+
+	cfg, err := config.LoadFrom(*sources)
 	if err != nil {
 		panic(fmt.Sprintf("Failed to load config: %v", err))
 	}
 
-	logger.Init()
-	database.Init(cfg.Database)
-	validation.Init()
+	logger.Init(cfg.Log)
 
-	if err := os.MkdirAll("logs", 0755); err != nil {
-		panic(fmt.Sprintf("Failed to create logs directory: %v", err))
+	if err := cfg.CheckSecrets(); err != nil {
+		if gin.Mode() == gin.ReleaseMode {
+			logger.Log.Fatalf("Refusing to start in release mode with insecure secrets: %v", err)
+		}
+		logger.Log.Warnf("Insecure secrets, the server will refuse to start in release mode: %v", err)
 	}
-	f, err := os.Create("logs/gin.log")
+
+	// Hot reload safe settings from the config files or on SIGHUP
+	watcherCtx, stopWatcher := context.WithCancel(context.Background())
+	defer stopWatcher()
+	watcher := config.NewWatcher(*sources, cfg)
+	updates := watcher.Subscribe()
+	go watcher.Run(watcherCtx)
+	go applyConfigUpdates(updates)
+
+	shutdownTracing, err := tracing.Init(cfg.Tracing)
 	if err != nil {
-		panic(fmt.Sprintf("Failed to create log file: %v", err))
+		logger.Log.Fatalf("Failed to initialize tracing: %v", err)
 	}
-	gin.DefaultWriter = io.MultiWriter(f)
-	gin.DisableConsoleColor()
 
-	router := router.NewRouter()
-	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
-	router.Use(middleware.ErrorMiddleware())
+	database.Init(cfg.Database)
+
+	if *runMigrations {
+		migrateDatabase()
+	}
+
+	if err := validation.Init(); err != nil {
+		panic(fmt.Sprintf("Failed to initialize validator: %v", err))
+	}
+	mailer.Init(cfg.Mail)
+
+	if err := password.Init(cfg.Password); err != nil {
+		panic(fmt.Sprintf("Failed to initialize password policy: %v", err))
+	}
+
+	router := router.NewRouter(
+		middleware.TracingMiddleware(),
+		middleware.RequestIDMiddleware(),
+		middleware.AccessLogMiddleware(),
+		middleware.MetricsMiddleware(),
+		gin.Recovery(),
+		middleware.ErrorMiddleware(),
+		middleware.CorsMiddleware(cfg.Cors),
+		middleware.LocaleMiddleware(),
+	)
 
 	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
 		panic(fmt.Sprintf("Failed to set trusted proxies: %v", err))
 	}
 
-	router.Use(cors.New(cors.Config{
-		AllowOrigins:     cfg.Cors.AllowOrigins,
-		AllowMethods:     cfg.Cors.AllowMethods,
-		AllowCredentials: cfg.Cors.AllowCredentials,
-	}))
-
 	server := &http.Server{
 		Addr:    cfg.Server.Url,
 		Handler: router,
@@ -82,6 +120,11 @@ func main() {
 	sig := <-quit
 	logger.Log.Infof("Received signal: %v. Shutting down server...", sig)
 
+	// Fail readiness first and give load balancers time to notice before
+	// the listener closes
+	health.SetShuttingDown()
+	time.Sleep(cfg.Server.ShutdownDelay)
+
 	// Create a context with timeout for shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -95,6 +138,55 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Flush spans of the last requests
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Log.Errorf("Failed to flush traces: %v", err)
+	}
+
 	logger.Log.Info("Server gracefully stopped")
 	os.Exit(0)
 }
+
+// migrateDatabase applies pending migrations. The runner holds an advisory
+// lock, so several instances started with -migrate do not race each other.
+func migrateDatabase() {
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		logger.Log.Fatalf("Failed to get database connection: %v", err)
+	}
+
+	runner, err := migrate.NewRunner(sqlDB, migrations.FS)
+	if err != nil {
+		logger.Log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := runner.Up(ctx); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		logger.Log.Fatalf("Failed to apply migrations: %v", err)
+	}
+
+	logger.Log.Info("Database migrations are up to date")
+}
+
+// applyConfigUpdates pushes hot reloaded settings that are not read from
+// config.Current on every use.
+func applyConfigUpdates(updates <-chan config.Update) {
+	for update := range updates {
+		if logLevelsChanged(update.Previous.Log, update.Current.Log) {
+			if err := logger.ApplyLevels(update.Current.Log); err != nil {
+				logger.Log.Errorw("failed to apply log levels", "error", err)
+				continue
+			}
+			logger.Log.Infow("log levels changed", "levels", logger.CurrentLevels())
+		}
+	}
+}
+
+func logLevelsChanged(previous, current config.LogConfig) bool {
+	return previous.Level != current.Level ||
+		previous.DBLevel != current.DBLevel ||
+		previous.HTTPLevel != current.HTTPLevel ||
+		previous.AuthLevel != current.AuthLevel
+}
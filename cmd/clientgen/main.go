@@ -0,0 +1,404 @@
+// Command clientgen writes the operations of pkg/client from the route
+// descriptions of the router, see router.Routes.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/listing"
+	"github.com/Alfian57/belajar-golang/internal/openapi"
+	"github.com/Alfian57/belajar-golang/internal/router"
+	"github.com/google/uuid"
+)
+
+var uuidType = reflect.TypeOf(uuid.UUID{})
+
+// handWritten are the types of the API that pkg/client declares by hand,
+// since it adds methods to them or uses them outside the operations.
+var handWritten = map[reflect.Type]string{
+	reflect.TypeOf(errs.Code("")):            "Code",
+	reflect.TypeOf(errs.FieldError{}):        "FieldError",
+	reflect.TypeOf(listing.Filter{}):         "Filter",
+	reflect.TypeOf(dto.PaginationResponse{}): "Pagination",
+}
+
+func main() {
+	output := flag.String("o", "", "Write the generated code to this file instead of stdout")
+	flag.Parse()
+
+	source, err := generate(router.Routes())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clientgen: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(source)
+		return
+	}
+
+	if err := os.WriteFile(*output, source, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "clientgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// generator collects the types and imports the operations need. The
+// types are declared again in the client, so it does not import the
+// internal packages of the API.
+type generator struct {
+	types       map[string]reflect.Type
+	definitions map[string]string
+	imports     map[string]bool
+}
+
+func generate(routes []openapi.Route) ([]byte, error) {
+	g := &generator{
+		types:       make(map[string]reflect.Type),
+		definitions: make(map[string]string),
+		imports:     map[string]bool{"context": true, "net/http": true},
+	}
+
+	var operations bytes.Buffer
+	for _, route := range routes {
+		// Probes, metrics and docs are not part of the API, downloads and
+		// uploads are written by hand but use the generated query types
+		if route.Raw != nil || route.Upload {
+			if route.ID != "" && route.Query != nil {
+				if _, err := g.typeExpression(reflect.TypeOf(route.Query)); err != nil {
+					return nil, fmt.Errorf("%s: %w", route.Key(), err)
+				}
+			}
+			continue
+		}
+
+		if err := g.operation(&operations, route); err != nil {
+			return nil, fmt.Errorf("%s: %w", route.Key(), err)
+		}
+	}
+
+	var source bytes.Buffer
+	source.WriteString("// Code generated by clientgen from router.Routes. DO NOT EDIT.\n\npackage client\n\n")
+
+	// Standard library first, like goimports
+	source.WriteString("import (\n")
+	for _, standard := range []bool{true, false} {
+		for _, path := range sortedKeys(g.imports) {
+			if standardLibrary(path) == standard {
+				fmt.Fprintf(&source, "\t%q\n", path)
+			}
+		}
+		if standard {
+			source.WriteString("\n")
+		}
+	}
+	source.WriteString(")\n\n")
+
+	source.WriteString("// Error codes of the API, see IsCode.\nconst (\n")
+	for _, info := range errs.Codes() {
+		fmt.Fprintf(&source, "\t%s Code = %q\n", codeConstant(info.Code), info.Code)
+	}
+	source.WriteString(")\n")
+
+	for _, name := range sortedKeys(g.types) {
+		fmt.Fprintf(&source, "\n// %s mirrors %s.\ntype %s %s\n", name, g.types[name], name, g.definitions[name])
+	}
+
+	source.Write(operations.Bytes())
+
+	return format.Source(source.Bytes())
+}
+
+func (g *generator) operation(w *bytes.Buffer, route openapi.Route) error {
+	name := exported(route.ID)
+	path, pathParams := g.pathExpression(route.Path)
+
+	params := []string{"ctx context.Context"}
+	params = append(params, pathParams...)
+
+	fields := []string{"method: " + methodConstant(route.Method), "path: " + path}
+
+	if route.Query != nil {
+		queryType, err := g.typeExpression(reflect.TypeOf(route.Query))
+		if err != nil {
+			return err
+		}
+		params = append(params, "query "+queryType)
+		fields = append(fields, "query: query")
+	}
+	if route.Body != nil {
+		bodyType, err := g.typeExpression(reflect.TypeOf(route.Body))
+		if err != nil {
+			return err
+		}
+		params = append(params, "request "+bodyType)
+		fields = append(fields, "body: request")
+	}
+	if route.Security != nil && reflect.DeepEqual(route.Security, openapi.RefreshCookie) {
+		fields = append(fields, "refreshCookie: true", "noRefresh: true")
+	}
+
+	fmt.Fprintf(w, "\n// %s sends %s %s.\n// %s.\n", name, route.Method, route.Path, route.Summary)
+
+	switch {
+	case route.Data == nil:
+		fmt.Fprintf(w, "func (c *Client) %s(%s) error {\n", name, strings.Join(params, ", "))
+		fmt.Fprintf(w, "\treturn c.do(ctx, call{%s})\n}\n", strings.Join(fields, ", "))
+
+	case route.Paginated:
+		itemType, err := g.typeExpression(reflect.TypeOf(route.Data))
+		if err != nil {
+			return err
+		}
+		if route.Query == nil || !hasField(reflect.TypeOf(route.Query), "Cursor") {
+			return fmt.Errorf("paginated route needs a query with a Cursor field")
+		}
+		g.imports["iter"] = true
+
+		fields = append(fields, "data: &page.Data", "pagination: &page.Pagination")
+		fmt.Fprintf(w, "func (c *Client) %s(%s) (Page[%s], error) {\n", name, strings.Join(params, ", "), itemType)
+		fmt.Fprintf(w, "\tvar page Page[%s]\n", itemType)
+		fmt.Fprintf(w, "\terr := c.do(ctx, call{%s})\n", strings.Join(fields, ", "))
+		fmt.Fprintf(w, "\treturn page, err\n}\n")
+
+		arguments := []string{"ctx"}
+		for _, param := range params[1:] {
+			argument, _, _ := strings.Cut(param, " ")
+			arguments = append(arguments, argument)
+		}
+		fmt.Fprintf(w, "\n// %sAll iterates over the items of every page of %s, from the page\n// selected by query on.\n", name, name)
+		fmt.Fprintf(w, "func (c *Client) %sAll(%s) iter.Seq2[%s, error] {\n", name, strings.Join(params, ", "), itemType)
+		fmt.Fprintf(w, "\treturn paginate(func(cursor string) (Page[%s], error) {\n", itemType)
+		fmt.Fprintf(w, "\t\tif cursor != \"\" {\n\t\t\tquery.Cursor = cursor\n\t\t}\n")
+		fmt.Fprintf(w, "\t\treturn c.%s(%s)\n\t})\n}\n", name, strings.Join(arguments, ", "))
+
+	default:
+		dataType, err := g.typeExpression(reflect.TypeOf(route.Data))
+		if err != nil {
+			return err
+		}
+		fields = append(fields, "data: &data")
+		fmt.Fprintf(w, "func (c *Client) %s(%s) (%s, error) {\n", name, strings.Join(params, ", "), dataType)
+		fmt.Fprintf(w, "\tvar data %s\n", dataType)
+		fmt.Fprintf(w, "\terr := c.do(ctx, call{%s})\n", strings.Join(fields, ", "))
+		fmt.Fprintf(w, "\treturn data, err\n}\n")
+	}
+
+	return nil
+}
+
+// pathExpression returns a Go expression building the path, and the
+// parameters it needs. IDs are UUIDs, other parameters strings.
+func (g *generator) pathExpression(path string) (string, []string) {
+	var parts, params []string
+	literal := ""
+
+	for _, segment := range strings.SplitAfter(path, "/") {
+		if !strings.HasPrefix(segment, ":") {
+			literal += segment
+			continue
+		}
+
+		name, slash := strings.CutSuffix(segment[1:], "/")
+		param := goName(name)
+
+		value := param
+		if name == "id" || strings.HasSuffix(name, "_id") {
+			g.imports["github.com/google/uuid"] = true
+			params = append(params, param+" uuid.UUID")
+			value = param + ".String()"
+		} else {
+			params = append(params, param+" string")
+		}
+
+		g.imports["net/url"] = true
+		parts = append(parts, fmt.Sprintf("%q", literal), "url.PathEscape("+value+")")
+		literal = ""
+		if slash {
+			literal = "/"
+		}
+	}
+	if literal != "" {
+		parts = append(parts, fmt.Sprintf("%q", literal))
+	}
+
+	return strings.Join(parts, " + "), params
+}
+
+// typeExpression names t in the client package. Named structs of the API
+// are declared again with their json and form tags, e.g. User for
+// model.User.
+func (g *generator) typeExpression(t reflect.Type) (string, error) {
+	if name, ok := handWritten[t]; ok {
+		return name, nil
+	}
+
+	switch {
+	case t == uuidType:
+		g.imports["github.com/google/uuid"] = true
+		return "uuid.UUID", nil
+	case t.Kind() == reflect.Pointer:
+		elem, err := g.typeExpression(t.Elem())
+		return "*" + elem, err
+	case t.Kind() == reflect.Slice:
+		elem, err := g.typeExpression(t.Elem())
+		return "[]" + elem, err
+	case t.Kind() == reflect.Map:
+		key, err := g.typeExpression(t.Key())
+		if err != nil {
+			return "", err
+		}
+		elem, err := g.typeExpression(t.Elem())
+		return "map[" + key + "]" + elem, err
+	case t.Kind() == reflect.Interface && t.NumMethod() == 0:
+		return "any", nil
+	case t.PkgPath() == "":
+		return t.String(), nil
+	case standardLibrary(t.PkgPath()):
+		g.imports[t.PkgPath()] = true
+		return t.String(), nil
+	case t.Kind() != reflect.Struct:
+		return "", fmt.Errorf("%s is not a struct, declare it in pkg/client and add it to handWritten", t)
+	}
+
+	name := t.Name()
+	if other, ok := g.types[name]; ok {
+		if other != t {
+			return "", fmt.Errorf("%s and %s would share the name %s", other, t, name)
+		}
+		return name, nil
+	}
+	// Registered before the fields, which may refer back to it
+	g.types[name] = t
+
+	definition, err := g.structDefinition(t)
+	if err != nil {
+		return "", err
+	}
+	g.definitions[name] = definition
+
+	return name, nil
+}
+
+// structDefinition declares the fields of t the API sends or binds, with
+// their json and form tags. Embedded structs stay embedded, so their fields
+// are flattened the same way.
+func (g *generator) structDefinition(t reflect.Type) (string, error) {
+	var definition strings.Builder
+	definition.WriteString("struct {\n")
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonTag, hasJSON := field.Tag.Lookup("json")
+		formTag, hasForm := field.Tag.Lookup("form")
+		jsonName, _, _ := strings.Cut(jsonTag, ",")
+		formName, _, _ := strings.Cut(formTag, ",")
+		if !field.IsExported() || (jsonName == "-" && (formName == "" || formName == "-")) {
+			continue
+		}
+
+		fieldType, err := g.typeExpression(field.Type)
+		if err != nil {
+			return "", fmt.Errorf("%s.%s: %w", t, field.Name, err)
+		}
+		if field.Anonymous {
+			fmt.Fprintf(&definition, "\t%s\n", fieldType)
+			continue
+		}
+
+		var tags []string
+		if hasJSON {
+			tags = append(tags, fmt.Sprintf("json:%q", jsonTag))
+		}
+		if hasForm {
+			tags = append(tags, fmt.Sprintf("form:%q", formTag))
+		}
+		fmt.Fprintf(&definition, "\t%s %s", field.Name, fieldType)
+		if len(tags) > 0 {
+			fmt.Fprintf(&definition, " `%s`", strings.Join(tags, " "))
+		}
+		definition.WriteString("\n")
+	}
+
+	definition.WriteString("}")
+	return definition.String(), nil
+}
+
+// standardLibrary reports whether path is a package of the standard
+// library, whose types the client can use as they are.
+func standardLibrary(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+func hasField(t reflect.Type, name string) bool {
+	_, ok := t.FieldByName(name)
+	return ok
+}
+
+func methodConstant(method string) string {
+	switch method {
+	case http.MethodGet:
+		return "http.MethodGet"
+	case http.MethodPost:
+		return "http.MethodPost"
+	case http.MethodPut:
+		return "http.MethodPut"
+	case http.MethodPatch:
+		return "http.MethodPatch"
+	case http.MethodDelete:
+		return "http.MethodDelete"
+	}
+
+	return fmt.Sprintf("%q", method)
+}
+
+// codeConstant turns an error code such as user_not_found into
+// CodeUserNotFound.
+func codeConstant(code errs.Code) string {
+	parts := strings.Split(string(code), "_")
+	for i, part := range parts {
+		parts[i] = strings.ToUpper(part[:1]) + part[1:]
+	}
+
+	return "Code" + strings.Join(parts, "")
+}
+
+// exported turns an operation ID such as listUsers into ListUsers.
+func exported(id string) string {
+	return strings.ToUpper(id[:1]) + id[1:]
+}
+
+// goName turns a path parameter such as user_id into userID.
+func goName(name string) string {
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] == "id" {
+			parts[i] = "ID"
+			continue
+		}
+		parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+	}
+
+	return strings.Join(parts, "")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/Alfian57/belajar-golang/internal/config"
+	"github.com/Alfian57/belajar-golang/internal/database"
+	"github.com/Alfian57/belajar-golang/internal/drift"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/migrate"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/migrations"
+)
+
+const usage = `Usage: migrate <command> [arguments]
+
+Commands:
+  up               Apply all pending migrations
+  down [N]         Roll back the last N migrations (default 1)
+  goto VERSION     Migrate up or down to VERSION
+  force VERSION    Set VERSION without running migrations and clear the dirty flag
+  status           List migrations and whether they are applied
+  create NAME      Create a new empty migration pair in -dir
+  drift            Compare the models with the database schema, exits 1 on drift
+`
+
+func main() {
+	dir := flag.String("dir", "migrations", "Directory new migrations are created in")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Creating files only touches the disk, no database needed
+	if args[0] == "create" {
+		if len(args) != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		paths, err := migrate.Create(*dir, args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to create migration: %v\n", err)
+			os.Exit(1)
+		}
+		for _, path := range paths {
+			fmt.Println(path)
+		}
+		return
+	}
+
+	config.LoadEnv()
+
+	cfg, err := config.Load()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to load config: %v", err))
+	}
+
+	logger.Init(cfg.Log)
+	database.Init(cfg.Database)
+
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		logger.Log.Fatalf("Failed to get database connection: %v", err)
+	}
+
+	runner, err := migrate.NewRunner(sqlDB, migrations.FS)
+	if err != nil {
+		logger.Log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		err = runner.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				logger.Log.Fatalf("Invalid number of steps: %s", args[1])
+			}
+		}
+		err = runner.Down(ctx, steps)
+	case "goto":
+		err = runner.Goto(ctx, parseVersion(args))
+	case "force":
+		err = runner.Force(ctx, parseVersion(args))
+	case "status":
+		err = printStatus(ctx, runner)
+	case "drift":
+		err = checkDrift(ctx)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if errors.Is(err, migrate.ErrNoChange) {
+		logger.Log.Info("No migrations to apply")
+		return
+	}
+	if err != nil {
+		logger.Log.Fatalf("Migration failed: %v", err)
+	}
+
+	logger.Log.Infof("Migration command %q completed successfully", args[0])
+}
+
+func parseVersion(args []string) uint64 {
+	if len(args) != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	version, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		logger.Log.Fatalf("Invalid version: %s", args[1])
+	}
+
+	return version
+}
+
+func printStatus(ctx context.Context, runner *migrate.Runner) error {
+	statuses, err := runner.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		state := "pending"
+		if status.Dirty {
+			state = "dirty"
+		} else if status.Applied {
+			state = "applied"
+		}
+		fmt.Printf("%06d  %-8s %s\n", status.Version, state, status.Name)
+	}
+
+	return nil
+}
+
+func checkDrift(ctx context.Context) error {
+	drifts, err := drift.Check(ctx, database.DB, model.All())
+	if err != nil {
+		return err
+	}
+
+	if len(drifts) == 0 {
+		fmt.Println("No schema drift detected")
+		return nil
+	}
+
+	for _, d := range drifts {
+		fmt.Println(d)
+	}
+	fmt.Fprintf(os.Stderr, "%d schema drift(s) detected\n", len(drifts))
+	os.Exit(1)
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Alfian57/belajar-golang/internal/router"
+	"github.com/gin-gonic/gin"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `Usage: openapi [-o file]
+
+Prints the OpenAPI document of the API.
+
+  -o  write the document to file instead of stdout`)
+}
+
+func main() {
+	output := flag.String("o", "", "Write the document to this file")
+	flag.Usage = usage
+	flag.Parse()
+
+	gin.SetMode(gin.ReleaseMode)
+
+	document, err := router.Document()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to generate OpenAPI document: %v\n", err)
+		os.Exit(1)
+	}
+
+	encoded, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to encode OpenAPI document: %v\n", err)
+		os.Exit(1)
+	}
+	encoded = append(encoded, '\n')
+
+	if *output == "" {
+		os.Stdout.Write(encoded)
+		return
+	}
+
+	if err := os.WriteFile(*output, encoded, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write %s: %v\n", *output, err)
+		os.Exit(1)
+	}
+}
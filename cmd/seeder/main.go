@@ -28,7 +28,7 @@ func main() {
 	}
 
 	// Initialize logger
-	logger.Init()
+	logger.Init(cfg.Log)
 
 	// Initialize database connection
 	database.Init(cfg.Database)
@@ -1,49 +1,249 @@
 package config
 
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Cors     CorsConfig
+	Server    ServerConfig
+	JWT       JWTConfig
+	Database  DatabaseConfig  `envPrefix:"DB_"`
+	Cors      CorsConfig      `envPrefix:"CORS_"`
+	Password  PasswordConfig  `envPrefix:"PASSWORD_"`
+	Mail      MailConfig      `envPrefix:"MAIL_"`
+	Log       LogConfig       `envPrefix:"LOG_"`
+	RateLimit RateLimitConfig `envPrefix:"RATE_LIMIT_"`
+	Features  FeatureConfig
+	Metrics   MetricsConfig `envPrefix:"METRICS_"`
+	Tracing   TracingConfig `envPrefix:"TRACING_"`
+	Search    SearchConfig  `envPrefix:"SEARCH_"`
+	Bulk      BulkConfig    `envPrefix:"BULK_"`
+	Import    ImportConfig  `envPrefix:"IMPORT_"`
+
+	// origins records the layer each variable was read from
+	origins map[string]string
 }
 
 type ServerConfig struct {
-	Url            string   `env:"APP_URL" envDefault:"localhost:8000"`
-	TrustedProxies []string `env:"TRUSTED_PROXIES" envDefault:""`
+	Url string `env:"APP_URL" envDefault:"localhost:8000" validate:"required"`
+	// PublicURL is the scheme and host clients reach the API at, used for
+	// links sent by email
+	PublicURL      string        `env:"PUBLIC_URL" envDefault:"http://localhost:8000" validate:"required,url"`
+	TrustedProxies []string      `env:"TRUSTED_PROXIES" envDefault:""`
+	ShutdownDelay  time.Duration `env:"SHUTDOWN_DELAY" envDefault:"5s" validate:"min=0"`
+	// TenantBaseDomain resolves organizations from its subdomains, such as
+	// acme.example.com for example.com
+	TenantBaseDomain string `env:"TENANT_BASE_DOMAIN" validate:"omitempty,fqdn" reload:"true"`
+	// DefaultLocale is used when Accept-Language names no supported locale
+	DefaultLocale string `env:"DEFAULT_LOCALE" envDefault:"en" validate:"oneof=en id" reload:"true"`
+}
+
+type JWTConfig struct {
+	AccessTokenSecret  string `env:"ACCESS_TOKEN_SECRET" envDefault:"secret" secret:"true" minEntropy:"128"`
+	RefreshTokenSecret string `env:"REFRESH_TOKEN_SECRET" envDefault:"secret" secret:"true" minEntropy:"128"`
 }
 
 type DatabaseConfig struct {
-	Host     string `env:"DB_HOST" envDefault:"localhost"`
-	Port     int    `env:"DB_PORT" envDefault:"3306"`
-	Username string `env:"DB_USERNAME"`
-	Password string `env:"DB_PASSWORD"`
-	Name     string `env:"DB_NAME"`
+	Host     string `env:"HOST" envDefault:"localhost" validate:"required"`
+	Port     int    `env:"PORT" envDefault:"5432" validate:"min=1,max=65535"`
+	Username string `env:"USERNAME,required"`
+	Password string `env:"PASSWORD" secret:"true"`
+	Name     string `env:"NAME" envDefault:"golang" validate:"required"`
+	// SlowQueryThreshold logs slower queries as warnings
+	SlowQueryThreshold time.Duration `env:"SLOW_QUERY_THRESHOLD" envDefault:"1s" validate:"min=0"`
 }
 
 type CorsConfig struct {
-	AllowOrigins     []string `env:"CORS_ALLOW_ORIGINS" envDefault:"*"`
-	AllowMethods     []string `env:"CORS_ALLOW_METHODS" envDefault:"GET,POST,PUT,DELETE,OPTIONS"`
-	AllowCredentials bool     `env:"CORS_ALLOW_CREDENTIALS" envDefault:"true"`
+	AllowOrigins     []string `env:"ALLOW_ORIGINS" envDefault:"*" reload:"true"`
+	AllowMethods     []string `env:"ALLOW_METHODS" envDefault:"GET,POST,PUT,DELETE,OPTIONS"`
+	AllowCredentials bool     `env:"ALLOW_CREDENTIALS" envDefault:"true"`
+}
+
+type PasswordConfig struct {
+	MinLength        int    `env:"MIN_LENGTH" envDefault:"8" validate:"min=1,max=72"`
+	RequireUpper     bool   `env:"REQUIRE_UPPER" envDefault:"false"`
+	RequireLower     bool   `env:"REQUIRE_LOWER" envDefault:"false"`
+	RequireDigit     bool   `env:"REQUIRE_DIGIT" envDefault:"false"`
+	RequireSymbol    bool   `env:"REQUIRE_SYMBOL" envDefault:"false"`
+	DisallowIdentity bool   `env:"DISALLOW_IDENTITY" envDefault:"true"`
+	BreachedListFile string `env:"BREACHED_LIST_FILE"`
+	HistorySize      int    `env:"HISTORY_SIZE" envDefault:"0" validate:"min=0"`
+}
+
+type MailConfig struct {
+	Host     string `env:"HOST"`
+	Port     int    `env:"PORT" envDefault:"587" validate:"min=1,max=65535"`
+	Username string `env:"USERNAME"`
+	Password string `env:"PASSWORD" secret:"true"`
+	From     string `env:"FROM" envDefault:"no-reply@example.com" validate:"required"`
+}
+
+type LogConfig struct {
+	Level     string `env:"LEVEL" envDefault:"info" validate:"oneof=debug info warn error" reload:"true"`
+	DBLevel   string `env:"DB_LEVEL" validate:"omitempty,oneof=debug info warn error" reload:"true"`
+	HTTPLevel string `env:"HTTP_LEVEL" validate:"omitempty,oneof=debug info warn error" reload:"true"`
+	AuthLevel string `env:"AUTH_LEVEL" validate:"omitempty,oneof=debug info warn error" reload:"true"`
+	// Format is json or console, by default json when GIN_MODE is release
+	Format         string        `env:"FORMAT" validate:"omitempty,oneof=json console"`
+	File           string        `env:"FILE" envDefault:"logs/app.log"`
+	MaxSizeMB      int           `env:"MAX_SIZE_MB" envDefault:"100" validate:"min=1"`
+	MaxAgeDays     int           `env:"MAX_AGE_DAYS" envDefault:"30" validate:"min=0"`
+	MaxBackups     int           `env:"MAX_BACKUPS" envDefault:"10" validate:"min=0"`
+	Compress       bool          `env:"COMPRESS" envDefault:"true"`
+	RotateInterval time.Duration `env:"ROTATE_INTERVAL" envDefault:"24h" validate:"min=0"`
+}
+
+type RateLimitConfig struct {
+	MagicLinkRequests int           `env:"MAGIC_LINK_REQUESTS" envDefault:"3" validate:"min=1" reload:"true"`
+	MagicLinkWindow   time.Duration `env:"MAGIC_LINK_WINDOW" envDefault:"15m" validate:"min=1s" reload:"true"`
+}
+
+type FeatureConfig struct {
+	Flags []string `env:"FEATURE_FLAGS" reload:"true"`
 }
 
+type MetricsConfig struct {
+	Token string `env:"TOKEN" secret:"true"`
+}
+
+type TracingConfig struct {
+	Exporter    string  `env:"EXPORTER" envDefault:"none" validate:"oneof=none stdout otlp"`
+	Endpoint    string  `env:"OTLP_ENDPOINT"`
+	Insecure    bool    `env:"OTLP_INSECURE" envDefault:"false"`
+	File        string  `env:"FILE"`
+	SampleRatio float64 `env:"SAMPLE_RATIO" envDefault:"1" validate:"min=0,max=1"`
+	ServiceName string  `env:"SERVICE_NAME" envDefault:"belajar-golang" validate:"required"`
+}
+
+type SearchConfig struct {
+	// FuzzyThreshold is the trigram word similarity, from 0 to 1, a fuzzy
+	// user search needs to match. Lower values tolerate more typos.
+	FuzzyThreshold float64 `env:"FUZZY_THRESHOLD" envDefault:"0.4" validate:"min=0,max=1" reload:"true"`
+}
+
+type BulkConfig struct {
+	// MaxSize limits the items of one bulk request
+	MaxSize int `env:"MAX_SIZE" envDefault:"100" validate:"min=1" reload:"true"`
+	// ChunkSize is the number of rows written per transaction in best
+	// effort mode and per statement in atomic mode
+	ChunkSize int `env:"CHUNK_SIZE" envDefault:"50" validate:"min=1" reload:"true"`
+}
+
+type ImportConfig struct {
+	// MaxFileSize limits uploaded files, in bytes
+	MaxFileSize int64 `env:"MAX_FILE_SIZE" envDefault:"10485760" validate:"min=1" reload:"true"`
+	// MaxRows limits the rows of one file
+	MaxRows int `env:"MAX_ROWS" envDefault:"10000" validate:"min=1" reload:"true"`
+	// SyncRows is the most rows imported while the request waits, larger
+	// files are imported by a background job
+	SyncRows int `env:"SYNC_ROWS" envDefault:"100" validate:"min=0" reload:"true"`
+	// Workers is the number of import jobs run at once
+	Workers int `env:"WORKERS" envDefault:"2" validate:"min=1"`
+}
+
+// FeatureEnabled reports whether the named feature flag is switched on
+func (c *Config) FeatureEnabled(name string) bool {
+	for _, flag := range c.Features.Flags {
+		if flag == name {
+			return true
+		}
+	}
+	return false
+}
+
+var current atomic.Pointer[Config]
+
+func init() {
+	current.Store(Defaults())
+}
+
+// Current returns the configuration the application is running with,
+// including values hot reloaded by a Watcher. It is never nil: until Load
+// or NewWatcher sets it, it holds the Defaults.
+func Current() *Config {
+	return current.Load()
+}
+
+// SetCurrent replaces the configuration returned by Current.
+func SetCurrent(cfg *Config) {
+	current.Store(cfg)
+}
+
+// Defaults returns the configuration made of the envDefault tags alone.
+// Required settings without a default, such as DB_USERNAME, stay empty.
+func Defaults() *Config {
+	cfg := &Config{}
+	// Parse still sets every default when it reports the missing ones
+	_ = Parse(cfg, func(string) (string, bool) { return "", false })
+	return cfg
+}
+
+// Load reads the configuration from the default sources, see LoadFrom, and
+// makes it the Current one.
 func Load() (*Config, error) {
-	cfg := &Config{
-		Server: ServerConfig{
-			Url:            GetEnv("APP_URL", "localhost:8000"),
-			TrustedProxies: GetEnvSlice("TRUSTED_PROXIES", []string{}),
-		},
-		Database: DatabaseConfig{
-			Host:     GetEnv("DB_HOST", "localhost"),
-			Port:     GetEnvInt("DB_PORT", 3306),
-			Username: GetEnv("DB_USERNAME", ""),
-			Password: GetEnv("DB_PASSWORD", ""),
-			Name:     GetEnv("DB_NAME", "golang"),
-		},
-		Cors: CorsConfig{
-			AllowOrigins:     GetEnvSlice("CORS_ALLOW_ORIGINS", []string{"*"}),
-			AllowMethods:     GetEnvSlice("CORS_ALLOW_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
-			AllowCredentials: GetEnvBool("CORS_ALLOW_CREDENTIALS", true),
-		},
+	cfg, err := LoadFrom(Sources{})
+	if err != nil {
+		return nil, err
+	}
+
+	SetCurrent(cfg)
+	return cfg, nil
+}
+
+// LoadFrom merges, from lowest to highest precedence, the envDefault tags,
+// the base config file, the environment overlay file, environment variables
+// and command line overrides. The returned error is a *Error listing every
+// missing, unknown or invalid setting.
+func LoadFrom(sources Sources) (*Config, error) {
+	sources = sources.resolve()
+
+	lookup := &layeredLookup{
+		overrides: sources.Overrides,
+		origins:   make(map[string]string),
 	}
 
+	if sources.File != "" {
+		file, err := readFile(sources.File, false)
+		if err != nil {
+			return nil, err
+		}
+		lookup.file = file
+	}
+
+	if overlay := sources.overlayFile(); overlay != "" {
+		file, err := readFile(overlay, true)
+		if err != nil {
+			return nil, err
+		}
+		lookup.overlay = file
+	}
+
+	cfg := &Config{}
+	err := Parse(cfg, lookup.lookup)
+
+	var problems []Problem
+	var configErr *Error
+	if errors.As(err, &configErr) {
+		problems = configErr.Problems
+	} else if err != nil {
+		return nil, err
+	}
+
+	problems = append(problems, unknownKeys(sources.Overrides, lookup.origins, "-set flags")...)
+	if lookup.file != nil {
+		problems = append(problems, unknownKeys(lookup.file.values, lookup.origins, lookup.file.path)...)
+	}
+	if lookup.overlay != nil {
+		problems = append(problems, unknownKeys(lookup.overlay.values, lookup.origins, lookup.overlay.path)...)
+	}
+
+	if len(problems) > 0 {
+		return nil, &Error{Problems: problems}
+	}
+
+	cfg.origins = lookup.origins
+
 	return cfg, nil
 }
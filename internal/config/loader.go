@@ -0,0 +1,286 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Struct tags read by Parse:
+//
+//	env:"NAME"            environment variable holding the value
+//	env:"NAME,required"   fail when the variable is unset or empty
+//	envDefault:"value"    value used when the variable is unset or empty
+//	envSeparator:";"      separator for slices, defaults to a comma
+//	envPrefix:"DB_"       prefix added to every variable of a nested struct
+//	validate:"..."        validator rules checked after all values are set
+//	reload:"true"         value may change while running, see Watcher
+//	secret:"true"         value is redacted from the config dump and may be
+//	                      read from the file named by the NAME_FILE variable
+//	minEntropy:"128"      minimum estimated bits of entropy, see CheckSecrets
+const defaultSeparator = ","
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Problem is a single variable that could not be loaded.
+type Problem struct {
+	Key     string
+	Message string
+}
+
+// Error collects every problem found while loading, so a misconfigured
+// deployment can be fixed in one go instead of one variable at a time.
+type Error struct {
+	Problems []Problem
+}
+
+func (e *Error) Error() string {
+	lines := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		lines[i] = fmt.Sprintf("%s: %s", problem.Key, problem.Message)
+	}
+	return "invalid configuration: " + strings.Join(lines, "; ")
+}
+
+// LookupFunc returns the raw value of a variable and whether it is set.
+type LookupFunc func(key string) (string, bool)
+
+// Parse fills the struct pointed to by target from the variables returned by
+// lookup and validates the result. All problems are returned as one *Error.
+func Parse(target any, lookup LookupFunc) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return errors.New("config target must be a pointer to a struct")
+	}
+
+	p := &parser{lookup: lookup, keys: make(map[string]string)}
+	p.parseStruct(value.Elem(), "", "")
+
+	p.validate(target)
+
+	if len(p.problems) > 0 {
+		return &Error{Problems: p.problems}
+	}
+	return nil
+}
+
+type parser struct {
+	lookup   LookupFunc
+	problems []Problem
+	// keys maps a field path such as "Database.Port" to its variable name,
+	// so validation errors can name the variable to fix.
+	keys map[string]string
+}
+
+func (p *parser) parseStruct(value reflect.Value, prefix string, path string) {
+	walk(value, prefix, path, func(f setting) {
+		p.keys[f.Path] = f.Key
+
+		raw, ok := p.lookup(f.Key)
+		if isSecret(f) {
+			raw, ok = p.lookupSecretFile(f.Key, raw, ok)
+		}
+		if !ok || raw == "" {
+			if f.Required {
+				p.addProblem(f.Key, "is required")
+				return
+			}
+			raw, ok = f.Field.Tag.Lookup("envDefault")
+			if !ok || raw == "" {
+				return
+			}
+		}
+
+		separator := f.Field.Tag.Get("envSeparator")
+		if separator == "" {
+			separator = defaultSeparator
+		}
+
+		if err := setValue(f.Value, raw, separator); err != nil {
+			// Parse errors quote the value, which must not leak for secrets
+			if isSecret(f) {
+				p.addProblem(f.Key, "invalid value")
+				return
+			}
+			p.addProblem(f.Key, err.Error())
+		}
+	})
+}
+
+// lookupSecretFile reads a secret from the file named by KEY_FILE, the way
+// Docker and Kubernetes mount secrets. Setting both KEY and KEY_FILE is an
+// error since it is unclear which one is meant.
+func (p *parser) lookupSecretFile(key string, raw string, ok bool) (string, bool) {
+	path, set := p.lookup(key + "_FILE")
+	if !set || path == "" {
+		return raw, ok
+	}
+	if ok && raw != "" {
+		p.addProblem(key, "set either "+key+" or "+key+"_FILE, not both")
+		return "", false
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		p.addProblem(key+"_FILE", err.Error())
+		return "", false
+	}
+
+	return strings.TrimRight(string(content), "\r\n"), true
+}
+
+// setting is one tagged leaf field found by walk.
+type setting struct {
+	Field    reflect.StructField
+	Value    reflect.Value
+	Key      string
+	Path     string
+	Required bool
+}
+
+// walk calls fn for every field with an env tag, descending into nested
+// structs and adding their envPrefix to the variable names.
+func walk(value reflect.Value, prefix string, path string, fn func(setting)) {
+	structType := value.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldValue := value.Field(i)
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+
+		tag, hasTag := field.Tag.Lookup("env")
+		if !hasTag && field.Type.Kind() == reflect.Struct && field.Type != durationType {
+			walk(fieldValue, prefix+field.Tag.Get("envPrefix"), fieldPath, fn)
+			continue
+		}
+		if !hasTag || tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		fn(setting{
+			Field:    field,
+			Value:    fieldValue,
+			Key:      prefix + name,
+			Path:     fieldPath,
+			Required: options == "required",
+		})
+	}
+}
+
+func (p *parser) validate(target any) {
+	err := validator.New(validator.WithRequiredStructEnabled()).Struct(target)
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		if err != nil {
+			p.addProblem("config", err.Error())
+		}
+		return
+	}
+
+	for _, fieldError := range validationErrors {
+		// StructNamespace starts with the name of the root struct
+		_, path, _ := strings.Cut(fieldError.StructNamespace(), ".")
+		key := p.keys[path]
+		if key == "" {
+			key = path
+		}
+		// A value that failed to parse is already reported and left zero
+		if p.hasProblem(key) {
+			continue
+		}
+
+		message := fmt.Sprintf("failed %q validation", fieldError.Tag())
+		if fieldError.Param() != "" {
+			message = fmt.Sprintf("failed %q validation with %s", fieldError.Tag(), fieldError.Param())
+		}
+		p.addProblem(key, message)
+	}
+}
+
+func (p *parser) hasProblem(key string) bool {
+	for _, problem := range p.problems {
+		if problem.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) addProblem(key string, message string) {
+	p.problems = append(p.problems, Problem{Key: key, Message: message})
+}
+
+func setValue(value reflect.Value, raw string, separator string) error {
+	if value.Kind() == reflect.Slice {
+		parts := strings.Split(raw, separator)
+		slice := reflect.MakeSlice(value.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setScalar(slice.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		value.Set(slice)
+		return nil
+	}
+
+	return setScalar(value, raw)
+}
+
+func setScalar(value reflect.Value, raw string) error {
+	if value.Type() == durationType {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		value.SetInt(int64(duration))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		value.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		value.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(raw, 10, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer %q", raw)
+		}
+		value.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		value.SetFloat(parsed)
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+
+	return nil
+}
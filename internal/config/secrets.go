@@ -0,0 +1,106 @@
+package config
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// minSecretLength applies to every secret with a minEntropy tag.
+const minSecretLength = 32
+
+// insecureValues are placeholders from examples and documentation that must
+// never reach production.
+var insecureValues = []string{
+	"secret",
+	"password",
+	"changeme",
+	"postgres",
+	"your_password",
+	"your_access_token_secret",
+	"your_refresh_token_secret",
+}
+
+// CheckSecrets reports secrets that are placeholders, too short or too
+// predictable. Problems name the variable but never include its value.
+func (c *Config) CheckSecrets() error {
+	var problems []Problem
+	values := make(map[string][]string)
+
+	walk(reflect.ValueOf(c).Elem(), "", "", func(f setting) {
+		if !isSecret(f) || f.Value.Kind() != reflect.String {
+			return
+		}
+
+		value := f.Value.String()
+		minEntropy, hasMinimum := f.Field.Tag.Lookup("minEntropy")
+		if value == "" {
+			if hasMinimum {
+				problems = append(problems, Problem{Key: f.Key, Message: "is required"})
+			}
+			return
+		}
+		values[value] = append(values[value], f.Key)
+
+		if isInsecureValue(value) {
+			problems = append(problems, Problem{Key: f.Key, Message: "uses a default or example value"})
+			return
+		}
+		if !hasMinimum {
+			return
+		}
+
+		if len(value) < minSecretLength {
+			problems = append(problems, Problem{Key: f.Key, Message: "must be at least " + strconv.Itoa(minSecretLength) + " characters"})
+			return
+		}
+
+		bits, err := strconv.ParseFloat(minEntropy, 64)
+		if err == nil && entropyBits(value) < bits {
+			problems = append(problems, Problem{Key: f.Key, Message: "is too predictable, use a random value of at least " + minEntropy + " bits"})
+		}
+	})
+
+	// Reusing one secret for several purposes lets one leak compromise all
+	for _, keys := range values {
+		if len(keys) > 1 {
+			problems = append(problems, Problem{Key: strings.Join(keys, ", "), Message: "must not share the same value"})
+		}
+	}
+
+	if len(problems) > 0 {
+		return &Error{Problems: problems}
+	}
+	return nil
+}
+
+func isInsecureValue(value string) bool {
+	lower := strings.ToLower(value)
+	for _, insecure := range insecureValues {
+		if lower == insecure {
+			return true
+		}
+	}
+	return false
+}
+
+// entropyBits estimates the entropy of a value from the frequency of its
+// characters. It underestimates random secrets slightly and catches
+// repeated or low variety values.
+func entropyBits(value string) float64 {
+	counts := make(map[rune]int)
+	length := 0
+	for _, r := range value {
+		counts[r]++
+		length++
+	}
+
+	perChar := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(length)
+		perChar -= p * math.Log2(p)
+	}
+
+	return perChar * float64(length)
+}
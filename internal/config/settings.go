@@ -0,0 +1,106 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// secretMarkers catch secrets that were not tagged, so a new field named
+// like a credential is hidden from the dump by default.
+var secretMarkers = []string{"PASSWORD", "SECRET", "TOKEN", "PRIVATE_KEY", "API_KEY"}
+
+// Setting is the effective value of one variable.
+type Setting struct {
+	Key        string `json:"key"`
+	Value      any    `json:"value"`
+	Source     string `json:"source"`
+	Reloadable bool   `json:"reloadable"`
+}
+
+// Settings lists every variable with its effective value and the layer it
+// came from. Secrets are redacted, so the result is safe to show to admins.
+func (c *Config) Settings() []Setting {
+	var settings []Setting
+
+	walk(reflect.ValueOf(c).Elem(), "", "", func(f setting) {
+		value := f.Value.Interface()
+		if isSecret(f) && !f.Value.IsZero() {
+			value = redacted
+		}
+		if duration, ok := value.(time.Duration); ok {
+			value = duration.String()
+		}
+
+		source := c.origins[f.Key]
+		if source == "" || source == LayerDefault {
+			source = LayerDefault
+			// A secret read from KEY_FILE reports the layer that named the file
+			if fileSource := c.origins[f.Key+"_FILE"]; fileSource != "" && fileSource != LayerDefault {
+				source = fileSource
+			}
+		}
+
+		settings = append(settings, Setting{
+			Key:        f.Key,
+			Value:      value,
+			Source:     source,
+			Reloadable: isReloadable(f),
+		})
+	})
+
+	return settings
+}
+
+func isSecret(f setting) bool {
+	if f.Field.Tag.Get("secret") == "true" {
+		return true
+	}
+	for _, marker := range secretMarkers {
+		if strings.Contains(f.Key, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func isReloadable(f setting) bool {
+	return f.Field.Tag.Get("reload") == "true"
+}
+
+// mergeReloadable returns a copy of c with the reloadable settings taken from
+// next. It also returns the keys that changed and were applied, and the keys
+// that changed but need a restart.
+func (c *Config) mergeReloadable(next *Config) (*Config, []string, []string) {
+	merged := *c
+	merged.origins = make(map[string]string, len(c.origins))
+	for key, origin := range c.origins {
+		merged.origins[key] = origin
+	}
+
+	nextValues := make(map[string]setting)
+	walk(reflect.ValueOf(next).Elem(), "", "", func(f setting) {
+		nextValues[f.Key] = f
+	})
+
+	var applied, ignored []string
+	walk(reflect.ValueOf(&merged).Elem(), "", "", func(f setting) {
+		incoming := nextValues[f.Key]
+		if reflect.DeepEqual(f.Value.Interface(), incoming.Value.Interface()) {
+			return
+		}
+
+		if !isReloadable(f) {
+			ignored = append(ignored, f.Key)
+			return
+		}
+
+		f.Value.Set(incoming.Value)
+		merged.origins[f.Key] = next.origins[f.Key]
+		applied = append(applied, f.Key)
+	})
+
+	return &merged, applied, ignored
+}
@@ -0,0 +1,204 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Layers a value can come from, lowest precedence first.
+const (
+	LayerDefault = "default"
+	LayerFile    = "file"
+	LayerOverlay = "overlay"
+	LayerEnv     = "env"
+	LayerFlag    = "flag"
+)
+
+// defaultFiles are looked up in the working directory when no file is given.
+var defaultFiles = []string{"config.yaml", "config.yml", "config.toml"}
+
+// Sources selects where Load reads values from besides the environment.
+type Sources struct {
+	// File is the base YAML or TOML file. Empty means CONFIG_FILE or the
+	// first of config.yaml, config.yml and config.toml that exists.
+	File string
+	// Environment selects the overlay file next to the base file, such as
+	// config.production.yaml. Empty means APP_ENV.
+	Environment string
+	// Overrides are KEY=VALUE pairs given on the command line.
+	Overrides map[string]string
+}
+
+// BindFlags registers -config, -env and a repeatable -set KEY=VALUE on the
+// flag set. The returned sources are filled in once the flags are parsed.
+func BindFlags(fs *flag.FlagSet) *Sources {
+	sources := &Sources{Overrides: make(map[string]string)}
+
+	fs.StringVar(&sources.File, "config", "", "Path to a YAML or TOML config file")
+	fs.StringVar(&sources.Environment, "env", "", "Environment overlay to load, e.g. production")
+	fs.Func("set", "Override a setting as KEY=VALUE, may be repeated", func(value string) error {
+		key, raw, ok := strings.Cut(value, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("expected KEY=VALUE, got %q", value)
+		}
+		sources.Overrides[strings.ToUpper(key)] = raw
+		return nil
+	})
+
+	return sources
+}
+
+// resolve fills the empty fields from the environment and looks for a
+// default file.
+func (s Sources) resolve() Sources {
+	if s.File == "" {
+		s.File = os.Getenv("CONFIG_FILE")
+	}
+	if s.File == "" {
+		for _, name := range defaultFiles {
+			if _, err := os.Stat(name); err == nil {
+				s.File = name
+				break
+			}
+		}
+	}
+	if s.Environment == "" {
+		s.Environment = os.Getenv("APP_ENV")
+	}
+	return s
+}
+
+// overlayFile returns the environment specific file next to the base file.
+func (s Sources) overlayFile() string {
+	if s.File == "" || s.Environment == "" {
+		return ""
+	}
+	ext := filepath.Ext(s.File)
+	return strings.TrimSuffix(s.File, ext) + "." + s.Environment + ext
+}
+
+// files returns the config files that are read, for change detection.
+func (s Sources) files() []string {
+	s = s.resolve()
+
+	var files []string
+	if s.File != "" {
+		files = append(files, s.File)
+	}
+	if overlay := s.overlayFile(); overlay != "" {
+		files = append(files, overlay)
+	}
+	return files
+}
+
+// fileValues holds the flattened settings of one config file.
+type fileValues struct {
+	path   string
+	values map[string]string
+}
+
+// readFile parses a YAML or TOML file into variable names, so nested keys
+// such as db.host map to DB_HOST. A missing overlay file is not an error.
+func readFile(path string, optional bool) (*fileValues, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) && optional {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var document map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &document)
+	case ".toml":
+		err = toml.NewDecoder(bytes.NewReader(content)).Decode(&document)
+	default:
+		return nil, fmt.Errorf("unsupported config file type %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	flatten(document, "", values)
+
+	return &fileValues{path: path, values: values}, nil
+}
+
+func flatten(document map[string]any, prefix string, values map[string]string) {
+	for key, value := range document {
+		name := prefix + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+
+		switch v := value.(type) {
+		case map[string]any:
+			flatten(v, name+"_", values)
+		case []any:
+			parts := make([]string, len(v))
+			for i, part := range v {
+				parts[i] = fmt.Sprint(part)
+			}
+			values[name] = strings.Join(parts, defaultSeparator)
+		case nil:
+			values[name] = ""
+		default:
+			values[name] = fmt.Sprint(v)
+		}
+	}
+}
+
+// unknownKeys reports values that no field reads, usually typos.
+func unknownKeys(values map[string]string, known map[string]string, source string) []Problem {
+	var problems []Problem
+	for key := range values {
+		if _, ok := known[key]; !ok {
+			problems = append(problems, Problem{Key: key, Message: "unknown setting in " + source})
+		}
+	}
+	sort.Slice(problems, func(i, j int) bool { return problems[i].Key < problems[j].Key })
+	return problems
+}
+
+// layeredLookup resolves each variable from the highest layer that sets it
+// and remembers which layer that was.
+type layeredLookup struct {
+	overrides map[string]string
+	overlay   *fileValues
+	file      *fileValues
+	origins   map[string]string
+}
+
+func (l *layeredLookup) lookup(key string) (string, bool) {
+	if value, ok := l.overrides[key]; ok {
+		l.origins[key] = LayerFlag
+		return value, true
+	}
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		l.origins[key] = LayerEnv
+		return value, true
+	}
+	if l.overlay != nil {
+		if value, ok := l.overlay.values[key]; ok {
+			l.origins[key] = LayerOverlay
+			return value, true
+		}
+	}
+	if l.file != nil {
+		if value, ok := l.file.values[key]; ok {
+			l.origins[key] = LayerFile
+			return value, true
+		}
+	}
+
+	l.origins[key] = LayerDefault
+	return "", false
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const reloadPollInterval = 5 * time.Second
+
+// Update is sent to subscribers after a reload changed at least one
+// reloadable setting.
+type Update struct {
+	Previous *Config
+	Current  *Config
+	Changed  []string
+}
+
+// Watcher reloads the configuration when a config file changes or the
+// process receives SIGHUP. Only settings tagged reload:"true" are applied;
+// other changes are logged and wait for a restart.
+type Watcher struct {
+	sources     Sources
+	mu          sync.Mutex
+	subscribers []chan Update
+	modTimes    map[string]time.Time
+}
+
+// NewWatcher makes cfg the current configuration and returns a watcher that
+// reloads it from the same sources.
+func NewWatcher(sources Sources, cfg *Config) *Watcher {
+	SetCurrent(cfg)
+
+	w := &Watcher{sources: sources}
+	w.modTimes = w.readModTimes()
+
+	return w
+}
+
+// Subscribe returns a channel receiving every applied update. A subscriber
+// that falls behind only gets the latest update.
+func (w *Watcher) Subscribe() <-chan Update {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ch := make(chan Update, 1)
+	w.subscribers = append(w.subscribers, ch)
+
+	return ch
+}
+
+// Run polls the config files and listens for SIGHUP until ctx is done.
+func (w *Watcher) Run(ctx context.Context) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	ticker := time.NewTicker(reloadPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.close()
+			return
+		case <-hangup:
+			log.Println("Received SIGHUP, reloading configuration")
+			w.Reload()
+		case <-ticker.C:
+			modTimes := w.readModTimes()
+			if !sameModTimes(w.modTimes, modTimes) {
+				w.modTimes = modTimes
+				log.Println("Config file changed, reloading configuration")
+				w.Reload()
+			}
+		}
+	}
+}
+
+// Reload loads the configuration again and applies the reloadable changes.
+// An invalid configuration is reported and the current one is kept.
+func (w *Watcher) Reload() error {
+	next, err := LoadFrom(w.sources)
+	if err != nil {
+		log.Printf("Configuration reload failed, keeping current configuration: %v", err)
+		return err
+	}
+
+	previous := Current()
+	merged, applied, ignored := previous.mergeReloadable(next)
+
+	if len(ignored) > 0 {
+		log.Printf("Configuration changes need a restart to apply: %v", ignored)
+	}
+	if len(applied) == 0 {
+		return nil
+	}
+
+	SetCurrent(merged)
+	log.Printf("Configuration reloaded, changed: %v", applied)
+
+	w.notify(Update{Previous: previous, Current: merged, Changed: applied})
+
+	return nil
+}
+
+func (w *Watcher) notify(update Update) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, ch := range w.subscribers {
+		// Drop an update the subscriber has not read yet, the new one
+		// supersedes it
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- update
+	}
+}
+
+func (w *Watcher) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, ch := range w.subscribers {
+		close(ch)
+	}
+	w.subscribers = nil
+}
+
+func (w *Watcher) readModTimes() map[string]time.Time {
+	modTimes := make(map[string]time.Time)
+	for _, file := range w.sources.files() {
+		if info, err := os.Stat(file); err == nil {
+			modTimes[file] = info.ModTime()
+		}
+	}
+	return modTimes
+}
+
+func sameModTimes(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for file, modTime := range a {
+		if !b[file].Equal(modTime) {
+			return false
+		}
+	}
+	return true
+}
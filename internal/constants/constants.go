@@ -35,3 +35,24 @@ const (
 const (
 	DefaultPassword = "password"
 )
+
+// Magic Link Constants
+const (
+	MagicLinkTTL = 15 * time.Minute
+)
+
+// Impersonation Constants
+const (
+	DefaultImpersonationTTL = 15 * time.Minute
+	MaxImpersonationTTL     = time.Hour
+)
+
+// Organization Constants
+const (
+	InvitationTTL = 7 * 24 * time.Hour
+)
+
+// Group Constants
+const (
+	MaxGroupDepth = 32
+)
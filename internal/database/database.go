@@ -1,17 +1,18 @@
 package database
 
 import (
+	"context"
 	"fmt"
-	"log"
-	"os"
 	"time"
 
 	"github.com/Alfian57/belajar-golang/internal/config"
+	"github.com/Alfian57/belajar-golang/internal/health"
 	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/metrics"
+	"github.com/Alfian57/belajar-golang/internal/tracing"
 	_ "github.com/lib/pq"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	gormLogger "gorm.io/gorm/logger"
 )
 
 var DB *gorm.DB
@@ -20,33 +21,35 @@ func Init(config config.DatabaseConfig) {
 	// Initialize the database connection string
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=disable", config.Host, config.Username, config.Password, config.Name, config.Port)
 
-	newLogger := gormLogger.New(
-		log.New(os.Stdout, "\r\n", log.LstdFlags),
-		gormLogger.Config{
-			SlowThreshold:             time.Second,
-			LogLevel:                  gormLogger.Warn,
-			IgnoreRecordNotFoundError: true,
-			Colorful:                  true,
-		},
-	)
-
 	// Open a new database connection
 	var err error
 	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: newLogger,
+		Logger: gormLogger{slowThreshold: config.SlowQueryThreshold},
 	})
 	if err != nil {
 		logger.Log.Fatalf("error opening database connection: %v", err)
 	}
 
+	if err := DB.Use(metrics.GormPlugin{}); err != nil {
+		logger.Log.Fatalf("error registering database metrics: %v", err)
+	}
+	if err := DB.Use(tracing.GormPlugin{}); err != nil {
+		logger.Log.Fatalf("error registering database tracing: %v", err)
+	}
+
 	sqlDB, err := DB.DB()
 	if err != nil {
 		logger.Log.Fatalf("error opening database connection: %v", err)
 	}
+	metrics.RegisterDBStats(sqlDB, config.Name)
 
 	sqlDB.SetMaxIdleConns(10)
 	sqlDB.SetMaxOpenConns(100)
 	sqlDB.SetConnMaxLifetime(time.Hour)
 
+	health.Register("database", func(ctx context.Context) error {
+		return sqlDB.PingContext(ctx)
+	})
+
 	logger.Log.Infoln("successfully connected to the database")
 }
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+)
+
+// gormLogger writes GORM output through the "db" component of the request
+// logger, so LOG_DB_LEVEL controls it: errors are always logged, slow queries
+// from warn and every query from debug. Queries are logged with placeholders,
+// never with the bound values.
+type gormLogger struct {
+	slowThreshold time.Duration
+}
+
+// explainedPlaceholder matches the $1$ form the postgres dialector leaves
+// when a query is explained without its values.
+var explainedPlaceholder = regexp.MustCompile(`\$(\d+)\$`)
+
+var _ gormlogger.Interface = gormLogger{}
+var _ gorm.ParamsFilter = gormLogger{}
+
+// LogMode is a no-op, the level comes from LOG_DB_LEVEL.
+func (l gormLogger) LogMode(gormlogger.LogLevel) gormlogger.Interface {
+	return l
+}
+
+func (l gormLogger) Info(ctx context.Context, msg string, data ...any) {
+	log(ctx).Infof(msg, data...)
+}
+
+func (l gormLogger) Warn(ctx context.Context, msg string, data ...any) {
+	log(ctx).Warnf(msg, data...)
+}
+
+func (l gormLogger) Error(ctx context.Context, msg string, data ...any) {
+	log(ctx).Errorf(msg, data...)
+}
+
+func (l gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	queryLog := log(ctx).Desugar()
+
+	var message string
+	var lvl zapcore.Level
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		message, lvl = "query failed", zapcore.ErrorLevel
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold:
+		message, lvl = "slow query", zapcore.WarnLevel
+	default:
+		message, lvl = "query", zapcore.DebugLevel
+	}
+
+	// Check first, fc renders the SQL
+	checked := queryLog.Check(lvl, message)
+	if checked == nil {
+		return
+	}
+
+	sql, rows := fc()
+	sql = explainedPlaceholder.ReplaceAllString(sql, "$$$1")
+	fields := []zap.Field{
+		zap.String("sql", sql),
+		zap.Int64("rows", rows),
+		zap.Duration("elapsed", elapsed),
+		zap.String("source", utils.FileWithLineNum()),
+	}
+	if lvl == zapcore.ErrorLevel {
+		fields = append(fields, zap.Error(err))
+	}
+
+	checked.Write(fields...)
+}
+
+// ParamsFilter drops the bound values, which hold password hashes and
+// token hashes, from logged queries.
+func (l gormLogger) ParamsFilter(ctx context.Context, sql string, params ...any) (string, []any) {
+	return sql, nil
+}
+
+func log(ctx context.Context) *zap.SugaredLogger {
+	return logger.FromContext(ctx).Named(logger.ComponentDB)
+}
@@ -11,16 +11,66 @@ import (
 )
 
 func InitializeAuthHandler() *handler.AuthHandler {
-	wire.Build(handler.NewAuthHandler, service.NewAuthService, repository.NewUserRepository, repository.NewRefreshTokenRepository)
+	wire.Build(handler.NewAuthHandler, service.NewAuthService, service.NewPasswordService, repository.NewUserRepository, repository.NewRefreshTokenRepository, repository.NewMagicLinkTokenRepository, repository.NewPasswordHistoryRepository)
 	return &handler.AuthHandler{}
 }
 
 func InitializeUserHandler() *handler.UserHandler {
-	wire.Build(handler.NewUserHandler, service.NewUserService, repository.NewUserRepository)
+	wire.Build(handler.NewUserHandler, service.NewUserService, service.NewPasswordService, repository.NewUserRepository, repository.NewPasswordHistoryRepository)
 	return &handler.UserHandler{}
 }
 
 func InitializeUserService() *service.UserService {
-	wire.Build(service.NewUserService, repository.NewUserRepository)
+	wire.Build(service.NewUserService, service.NewPasswordService, repository.NewUserRepository, repository.NewPasswordHistoryRepository)
 	return &service.UserService{}
 }
+
+func InitializeUserImportHandler() *handler.UserImportHandler {
+	wire.Build(handler.NewUserImportHandler, service.NewUserImportService, service.NewUserService, service.NewPasswordService, repository.NewUserRepository, repository.NewPasswordHistoryRepository, repository.NewUserImportJobRepository, repository.NewMagicLinkTokenRepository)
+	return &handler.UserImportHandler{}
+}
+
+func InitializeImpersonationHandler() *handler.ImpersonationHandler {
+	wire.Build(handler.NewImpersonationHandler, service.NewImpersonationService, repository.NewUserRepository, repository.NewImpersonationSessionRepository, repository.NewGroupRepository)
+	return &handler.ImpersonationHandler{}
+}
+
+func InitializeImpersonationService() *service.ImpersonationService {
+	wire.Build(service.NewImpersonationService, repository.NewUserRepository, repository.NewImpersonationSessionRepository, repository.NewGroupRepository)
+	return &service.ImpersonationService{}
+}
+
+func InitializeOrganizationHandler() *handler.OrganizationHandler {
+	wire.Build(handler.NewOrganizationHandler, service.NewOrganizationService, repository.NewOrganizationRepository, repository.NewOrganizationMemberRepository, repository.NewOrganizationInvitationRepository, repository.NewUserRepository, repository.NewGroupRepository)
+	return &handler.OrganizationHandler{}
+}
+
+func InitializeOrganizationService() *service.OrganizationService {
+	wire.Build(service.NewOrganizationService, repository.NewOrganizationRepository, repository.NewOrganizationMemberRepository, repository.NewOrganizationInvitationRepository, repository.NewUserRepository, repository.NewGroupRepository)
+	return &service.OrganizationService{}
+}
+
+func InitializeGroupHandler() *handler.GroupHandler {
+	wire.Build(handler.NewGroupHandler, service.NewGroupService, repository.NewGroupRepository, repository.NewUserRepository, repository.NewUserPermissionRepository, repository.NewOrganizationMemberRepository)
+	return &handler.GroupHandler{}
+}
+
+func InitializeGroupService() *service.GroupService {
+	wire.Build(service.NewGroupService, repository.NewGroupRepository, repository.NewUserRepository, repository.NewUserPermissionRepository, repository.NewOrganizationMemberRepository)
+	return &service.GroupService{}
+}
+
+func InitializeConfigHandler() *handler.ConfigHandler {
+	wire.Build(handler.NewConfigHandler)
+	return &handler.ConfigHandler{}
+}
+
+func InitializeLogHandler() *handler.LogHandler {
+	wire.Build(handler.NewLogHandler)
+	return &handler.LogHandler{}
+}
+
+func InitializeHealthHandler() *handler.HealthHandler {
+	wire.Build(handler.NewHealthHandler)
+	return &handler.HealthHandler{}
+}
@@ -17,20 +17,111 @@ import (
 func InitializeAuthHandler() *handler.AuthHandler {
 	userRepository := repository.NewUserRepository()
 	refreshTokenRepository := repository.NewRefreshTokenRepository()
-	authService := service.NewAuthService(userRepository, refreshTokenRepository)
+	magicLinkTokenRepository := repository.NewMagicLinkTokenRepository()
+	passwordHistoryRepository := repository.NewPasswordHistoryRepository()
+	passwordService := service.NewPasswordService(passwordHistoryRepository)
+	authService := service.NewAuthService(userRepository, refreshTokenRepository, magicLinkTokenRepository, passwordService)
 	authHandler := handler.NewAuthHandler(authService)
 	return authHandler
 }
 
 func InitializeUserHandler() *handler.UserHandler {
 	userRepository := repository.NewUserRepository()
-	userService := service.NewUserService(userRepository)
+	passwordHistoryRepository := repository.NewPasswordHistoryRepository()
+	passwordService := service.NewPasswordService(passwordHistoryRepository)
+	userService := service.NewUserService(userRepository, passwordService)
 	userHandler := handler.NewUserHandler(userService)
 	return userHandler
 }
 
 func InitializeUserService() *service.UserService {
 	userRepository := repository.NewUserRepository()
-	userService := service.NewUserService(userRepository)
+	passwordHistoryRepository := repository.NewPasswordHistoryRepository()
+	passwordService := service.NewPasswordService(passwordHistoryRepository)
+	userService := service.NewUserService(userRepository, passwordService)
 	return userService
 }
+
+func InitializeUserImportHandler() *handler.UserImportHandler {
+	userRepository := repository.NewUserRepository()
+	passwordHistoryRepository := repository.NewPasswordHistoryRepository()
+	passwordService := service.NewPasswordService(passwordHistoryRepository)
+	userService := service.NewUserService(userRepository, passwordService)
+	userImportJobRepository := repository.NewUserImportJobRepository()
+	magicLinkTokenRepository := repository.NewMagicLinkTokenRepository()
+	userImportService := service.NewUserImportService(userService, userImportJobRepository, magicLinkTokenRepository)
+	userImportHandler := handler.NewUserImportHandler(userImportService)
+	return userImportHandler
+}
+
+func InitializeImpersonationHandler() *handler.ImpersonationHandler {
+	userRepository := repository.NewUserRepository()
+	impersonationSessionRepository := repository.NewImpersonationSessionRepository()
+	groupRepository := repository.NewGroupRepository()
+	impersonationService := service.NewImpersonationService(userRepository, impersonationSessionRepository, groupRepository)
+	impersonationHandler := handler.NewImpersonationHandler(impersonationService)
+	return impersonationHandler
+}
+
+func InitializeImpersonationService() *service.ImpersonationService {
+	userRepository := repository.NewUserRepository()
+	impersonationSessionRepository := repository.NewImpersonationSessionRepository()
+	groupRepository := repository.NewGroupRepository()
+	impersonationService := service.NewImpersonationService(userRepository, impersonationSessionRepository, groupRepository)
+	return impersonationService
+}
+
+func InitializeOrganizationHandler() *handler.OrganizationHandler {
+	organizationRepository := repository.NewOrganizationRepository()
+	organizationMemberRepository := repository.NewOrganizationMemberRepository()
+	organizationInvitationRepository := repository.NewOrganizationInvitationRepository()
+	userRepository := repository.NewUserRepository()
+	groupRepository := repository.NewGroupRepository()
+	organizationService := service.NewOrganizationService(organizationRepository, organizationMemberRepository, organizationInvitationRepository, userRepository, groupRepository)
+	organizationHandler := handler.NewOrganizationHandler(organizationService)
+	return organizationHandler
+}
+
+func InitializeOrganizationService() *service.OrganizationService {
+	organizationRepository := repository.NewOrganizationRepository()
+	organizationMemberRepository := repository.NewOrganizationMemberRepository()
+	organizationInvitationRepository := repository.NewOrganizationInvitationRepository()
+	userRepository := repository.NewUserRepository()
+	groupRepository := repository.NewGroupRepository()
+	organizationService := service.NewOrganizationService(organizationRepository, organizationMemberRepository, organizationInvitationRepository, userRepository, groupRepository)
+	return organizationService
+}
+
+func InitializeGroupHandler() *handler.GroupHandler {
+	groupRepository := repository.NewGroupRepository()
+	userRepository := repository.NewUserRepository()
+	userPermissionRepository := repository.NewUserPermissionRepository()
+	organizationMemberRepository := repository.NewOrganizationMemberRepository()
+	groupService := service.NewGroupService(groupRepository, userRepository, userPermissionRepository, organizationMemberRepository)
+	groupHandler := handler.NewGroupHandler(groupService)
+	return groupHandler
+}
+
+func InitializeGroupService() *service.GroupService {
+	groupRepository := repository.NewGroupRepository()
+	userRepository := repository.NewUserRepository()
+	userPermissionRepository := repository.NewUserPermissionRepository()
+	organizationMemberRepository := repository.NewOrganizationMemberRepository()
+	groupService := service.NewGroupService(groupRepository, userRepository, userPermissionRepository, organizationMemberRepository)
+	return groupService
+}
+
+func InitializeConfigHandler() *handler.ConfigHandler {
+	configHandler := handler.NewConfigHandler()
+	return configHandler
+}
+
+func InitializeLogHandler() *handler.LogHandler {
+	logHandler := handler.NewLogHandler()
+	return logHandler
+}
+
+func InitializeHealthHandler() *handler.HealthHandler {
+	healthHandler := handler.NewHealthHandler()
+	return healthHandler
+}
@@ -0,0 +1,335 @@
+// Package drift compares the GORM models with the live database schema and
+// reports columns and constraints that differ between the two.
+package drift
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	KindMissingTable     = "missing table"
+	KindMissingColumn    = "missing column"
+	KindExtraColumn      = "extra column"
+	KindTypeMismatch     = "type mismatch"
+	KindNullMismatch     = "nullability mismatch"
+	KindSizeMismatch     = "size mismatch"
+	KindPrimaryKey       = "primary key mismatch"
+	KindUniqueMismatch   = "unique mismatch"
+	KindMissingReference = "missing foreign key"
+)
+
+// Drift is a single difference between a model and its table.
+type Drift struct {
+	Table  string
+	Column string
+	Kind   string
+	Detail string
+}
+
+func (d Drift) String() string {
+	if d.Column == "" {
+		return fmt.Sprintf("%s: %s: %s", d.Table, d.Kind, d.Detail)
+	}
+	return fmt.Sprintf("%s.%s: %s: %s", d.Table, d.Column, d.Kind, d.Detail)
+}
+
+type column struct {
+	DataType  string
+	Nullable  bool
+	MaxLength *int
+	// Generated columns are computed by the database, e.g. search vectors
+	Generated bool
+}
+
+type foreignKey struct {
+	Column string
+	Table  string
+}
+
+// compatibleTypes lists the Postgres data types each Go type may be stored in.
+var compatibleTypes = map[reflect.Type][]string{
+	reflect.TypeOf(""):          {"character varying", "text", "character"},
+	reflect.TypeOf(false):       {"boolean"},
+	reflect.TypeOf(int(0)):      {"integer", "bigint", "smallint"},
+	reflect.TypeOf(int32(0)):    {"integer", "smallint"},
+	reflect.TypeOf(int64(0)):    {"bigint"},
+	reflect.TypeOf(float64(0)):  {"double precision", "numeric", "real"},
+	reflect.TypeOf(time.Time{}): {"timestamp without time zone", "timestamp with time zone", "date"},
+	reflect.TypeOf(uuid.UUID{}): {"uuid"},
+	reflect.TypeOf([]byte{}):    {"bytea"},
+}
+
+// Check compares every model with its table. Composite unique constraints
+// and foreign keys that have no relation field on the model are not
+// reported, since GORM has no metadata to compare them with.
+func Check(ctx context.Context, db *gorm.DB, models []any) ([]Drift, error) {
+	db = db.WithContext(ctx)
+
+	var drifts []Drift
+	for _, value := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(value); err != nil {
+			return nil, fmt.Errorf("failed to parse model %T: %w", value, err)
+		}
+
+		tableDrifts, err := checkTable(db, stmt.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect table %s: %w", stmt.Schema.Table, err)
+		}
+		drifts = append(drifts, tableDrifts...)
+	}
+
+	return drifts, nil
+}
+
+func checkTable(db *gorm.DB, s *schema.Schema) ([]Drift, error) {
+	table := s.Table
+
+	columns, err := loadColumns(db, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return []Drift{{Table: table, Kind: KindMissingTable, Detail: "table does not exist"}}, nil
+	}
+
+	var drifts []Drift
+	add := func(column, kind, format string, args ...any) {
+		drifts = append(drifts, Drift{Table: table, Column: column, Kind: kind, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	modelColumns := make(map[string]bool)
+	for _, field := range s.Fields {
+		if field.DBName == "" || field.IgnoreMigration {
+			continue
+		}
+		modelColumns[field.DBName] = true
+
+		dbColumn, ok := columns[field.DBName]
+		if !ok {
+			add(field.DBName, KindMissingColumn, "field %s has no column", field.Name)
+			continue
+		}
+
+		goType := field.FieldType
+		isPointer := goType.Kind() == reflect.Ptr
+		if isPointer {
+			goType = goType.Elem()
+		}
+
+		if allowed, known := compatibleTypes[goType]; known && !contains(allowed, dbColumn.DataType) {
+			add(field.DBName, KindTypeMismatch, "field %s is %s but column is %s", field.Name, field.FieldType, dbColumn.DataType)
+		}
+
+		if dbColumn.Nullable && !isPointer && !field.PrimaryKey {
+			add(field.DBName, KindNullMismatch, "column is nullable but field %s cannot hold NULL", field.Name)
+		}
+		if !dbColumn.Nullable && isPointer {
+			add(field.DBName, KindNullMismatch, "column is NOT NULL but field %s is a pointer", field.Name)
+		}
+
+		if field.Size > 0 && dbColumn.MaxLength != nil && *dbColumn.MaxLength != field.Size {
+			add(field.DBName, KindSizeMismatch, "field %s has size %d but column has %d", field.Name, field.Size, *dbColumn.MaxLength)
+		}
+	}
+
+	// Generated columns only serve queries, models need not read them
+	for name, dbColumn := range columns {
+		if !modelColumns[name] && !dbColumn.Generated {
+			add(name, KindExtraColumn, "column has no field on the model")
+		}
+	}
+
+	primaryKeys, err := loadPrimaryKey(db, table)
+	if err != nil {
+		return nil, err
+	}
+	if want := s.PrimaryFieldDBNames; !sameSet(want, primaryKeys) {
+		add("", KindPrimaryKey, "model has (%s) but table has (%s)", strings.Join(want, ", "), strings.Join(primaryKeys, ", "))
+	}
+
+	uniqueColumns, err := loadUniqueColumns(db, table)
+	if err != nil {
+		return nil, err
+	}
+	modelUnique := uniqueFields(s)
+	for name := range modelUnique {
+		if !contains(uniqueColumns, name) {
+			add(name, KindUniqueMismatch, "model declares a unique index the table does not have")
+		}
+	}
+	for _, name := range uniqueColumns {
+		if !modelUnique[name] {
+			add(name, KindUniqueMismatch, "table has a unique constraint the model does not declare")
+		}
+	}
+
+	foreignKeys, err := loadForeignKeys(db, table)
+	if err != nil {
+		return nil, err
+	}
+	for _, relationship := range s.Relationships.BelongsTo {
+		for _, reference := range relationship.References {
+			if reference.ForeignKey == nil || reference.PrimaryKey == nil {
+				continue
+			}
+
+			expected := foreignKey{Column: reference.ForeignKey.DBName, Table: relationship.FieldSchema.Table}
+			if !containsForeignKey(foreignKeys, expected) {
+				add(expected.Column, KindMissingReference, "relation %s expects a reference to %s", relationship.Name, expected.Table)
+			}
+		}
+	}
+
+	sort.Slice(drifts, func(i, j int) bool {
+		if drifts[i].Column != drifts[j].Column {
+			return drifts[i].Column < drifts[j].Column
+		}
+		return drifts[i].Kind < drifts[j].Kind
+	})
+
+	return drifts, nil
+}
+
+// uniqueFields returns the columns the model declares unique on their own.
+func uniqueFields(s *schema.Schema) map[string]bool {
+	unique := make(map[string]bool)
+	for _, field := range s.Fields {
+		if field.Unique && field.DBName != "" {
+			unique[field.DBName] = true
+		}
+	}
+	for _, index := range s.ParseIndexes() {
+		if index.Class == "UNIQUE" && len(index.Fields) == 1 && index.Fields[0].Field != nil {
+			unique[index.Fields[0].DBName] = true
+		}
+	}
+	return unique
+}
+
+func loadColumns(db *gorm.DB, table string) (map[string]column, error) {
+	var rows []struct {
+		ColumnName             string
+		DataType               string
+		IsNullable             string
+		CharacterMaximumLength *int
+		IsGenerated            string
+	}
+
+	err := db.Raw(`
+		SELECT column_name, data_type, is_nullable, character_maximum_length, is_generated
+		FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = ?`, table).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]column, len(rows))
+	for _, row := range rows {
+		columns[row.ColumnName] = column{
+			DataType:  row.DataType,
+			Nullable:  row.IsNullable == "YES",
+			MaxLength: row.CharacterMaximumLength,
+			Generated: row.IsGenerated == "ALWAYS",
+		}
+	}
+
+	return columns, nil
+}
+
+func loadPrimaryKey(db *gorm.DB, table string) ([]string, error) {
+	var columns []string
+	err := db.Raw(`
+		SELECT a.attname
+		FROM pg_index i
+		JOIN pg_class t ON t.oid = i.indrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = ANY(i.indkey)
+		WHERE n.nspname = current_schema() AND t.relname = ? AND i.indisprimary`, table).
+		Scan(&columns).Error
+	return columns, err
+}
+
+// loadUniqueColumns returns the columns covered by a single column unique
+// constraint or index. Partial and expression indexes are skipped.
+func loadUniqueColumns(db *gorm.DB, table string) ([]string, error) {
+	var columns []string
+	err := db.Raw(`
+		SELECT DISTINCT a.attname
+		FROM pg_index i
+		JOIN pg_class t ON t.oid = i.indrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = i.indkey[0]
+		WHERE n.nspname = current_schema() AND t.relname = ?
+			AND i.indisunique AND NOT i.indisprimary AND i.indnatts = 1
+			AND i.indexprs IS NULL AND i.indpred IS NULL`, table).
+		Scan(&columns).Error
+	return columns, err
+}
+
+func loadForeignKeys(db *gorm.DB, table string) ([]foreignKey, error) {
+	var rows []struct {
+		ColumnName      string
+		ReferencedTable string
+	}
+
+	err := db.Raw(`
+		SELECT kcu.column_name, ccu.table_name AS referenced_table
+		FROM information_schema.table_constraints tc
+		JOIN information_schema.key_column_usage kcu
+			ON kcu.constraint_name = tc.constraint_name AND kcu.table_schema = tc.table_schema
+		JOIN information_schema.constraint_column_usage ccu
+			ON ccu.constraint_name = tc.constraint_name AND ccu.table_schema = tc.table_schema
+		WHERE tc.constraint_type = 'FOREIGN KEY' AND tc.table_schema = current_schema() AND tc.table_name = ?`, table).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	foreignKeys := make([]foreignKey, len(rows))
+	for i, row := range rows {
+		foreignKeys[i] = foreignKey{Column: row.ColumnName, Table: row.ReferencedTable}
+	}
+
+	return foreignKeys, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsForeignKey(foreignKeys []foreignKey, expected foreignKey) bool {
+	for _, fk := range foreignKeys {
+		if fk == expected {
+			return true
+		}
+	}
+	return false
+}
+
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, value := range a {
+		if !contains(b, value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package drift_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/drift"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/migrate"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/migrations"
+	"go.uber.org/zap/zaptest"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// databaseURLVariable names the database the test migrates. It is never
+// read from the DB_ settings, which may point at a database in use.
+const databaseURLVariable = "DRIFT_TEST_DATABASE_URL"
+
+// TestNoSchemaDrift migrates the database at DRIFT_TEST_DATABASE_URL to the
+// latest version and fails on any difference from the models, like
+// `migrate drift`. It is skipped without the variable and fails when the
+// database it names cannot be used.
+func TestNoSchemaDrift(t *testing.T) {
+	databaseURL := os.Getenv(databaseURLVariable)
+	if databaseURL == "" {
+		t.Skipf("%s is not set", databaseURLVariable)
+	}
+	logger.Log = zaptest.NewLogger(t).Sugar()
+
+	db := openDatabase(t, databaseURL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database connection: %v", err)
+	}
+	runner, err := migrate.NewRunner(sqlDB, migrations.FS)
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+	if err := runner.Up(ctx); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+
+	drifts, err := drift.Check(ctx, db, model.All())
+	if err != nil {
+		t.Fatalf("failed to check schema drift: %v", err)
+	}
+	for _, d := range drifts {
+		t.Error(d)
+	}
+}
+
+func openDatabase(t *testing.T, databaseURL string) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.Open(databaseURL), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to %s: %v", databaseURLVariable, err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database connection: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	return db
+}
@@ -6,12 +6,26 @@ type LoginRequest struct {
 }
 
 type RegisterRequest struct {
-	Email                string `json:"email" form:"email" binding:"required,email,min=3,max=100"`
-	Username             string `json:"username" form:"username" binding:"required,min=3,max=100"`
-	Password             string `json:"password" form:"password" binding:"required,min=8"`
+	Email                string `json:"email" form:"email" binding:"required,email,min=3,max=100,unique_email"`
+	Username             string `json:"username" form:"username" binding:"required,min=3,max=100,username,unique_username"`
+	Password             string `json:"password" form:"password" binding:"required,password_strength"`
 	PasswordConfirmation string `json:"password_confirmation" form:"password_confirmation" binding:"required,eqfield=Password"`
 }
 
+type ChangePasswordRequest struct {
+	CurrentPassword      string `json:"current_password" form:"current_password" binding:"required"`
+	Password             string `json:"password" form:"password" binding:"required,password_strength"`
+	PasswordConfirmation string `json:"password_confirmation" form:"password_confirmation" binding:"required,eqfield=Password"`
+}
+
+type MagicLinkRequest struct {
+	Email string `json:"email" form:"email" binding:"required,email,max=100"`
+}
+
+type MagicLinkVerifyRequest struct {
+	Token string `json:"token" form:"token" binding:"required"`
+}
+
 type Credentials struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
@@ -0,0 +1,86 @@
+package dto
+
+import (
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/google/uuid"
+)
+
+// Bulk modes. Atomic writes every item or none, best effort writes the
+// valid items and reports the others.
+const (
+	BulkModeAtomic     = "atomic"
+	BulkModeBestEffort = "best_effort"
+)
+
+// Bulk item statuses.
+const (
+	BulkStatusCreated = "created"
+	BulkStatusUpdated = "updated"
+	BulkStatusDeleted = "deleted"
+	BulkStatusFailed  = "failed"
+	// BulkStatusSkipped marks valid items of an atomic operation that was
+	// rolled back because of other items.
+	BulkStatusSkipped = "skipped"
+)
+
+// BulkItemResult is the outcome of one item, by its index in the request.
+// Err is rendered into Code, Message and Errors by response.WriteBulkResponse.
+type BulkItemResult struct {
+	Index   int               `json:"index"`
+	ID      uuid.UUID         `json:"id,omitzero"`
+	Status  string            `json:"status"`
+	Code    errs.Code         `json:"code,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Errors  []errs.FieldError `json:"errors,omitempty"`
+	Err     error             `json:"-"`
+}
+
+type BulkResult struct {
+	Mode      string           `json:"mode"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Items     []BulkItemResult `json:"items"`
+}
+
+// NewBulkResult starts a result with one pending item per request item.
+func NewBulkResult(mode string, size int) BulkResult {
+	if mode == "" {
+		mode = BulkModeAtomic
+	}
+
+	items := make([]BulkItemResult, size)
+	for i := range items {
+		items[i].Index = i
+	}
+
+	return BulkResult{Mode: mode, Items: items}
+}
+
+// Atomic reports whether the operation writes every item or none.
+func (r *BulkResult) Atomic() bool {
+	return r.Mode == BulkModeAtomic
+}
+
+// Succeed records item i as written with status.
+func (r *BulkResult) Succeed(i int, id uuid.UUID, status string) {
+	r.Items[i].ID = id
+	r.Items[i].Status = status
+	r.Succeeded++
+}
+
+// Fail records the error of item i.
+func (r *BulkResult) Fail(i int, err error) {
+	r.Items[i].Status = BulkStatusFailed
+	r.Items[i].Err = err
+	r.Failed++
+}
+
+// Skip marks the items that did not fail as skipped, for atomic operations
+// rolled back because of failed items.
+func (r *BulkResult) Skip() {
+	for i := range r.Items {
+		if r.Items[i].Err == nil {
+			r.Items[i].Status = BulkStatusSkipped
+		}
+	}
+}
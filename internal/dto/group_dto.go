@@ -0,0 +1,29 @@
+package dto
+
+import "github.com/google/uuid"
+
+type CreateGroupRequest struct {
+	Name     string     `json:"name" form:"name" binding:"required,min=3,max=100"`
+	ParentID *uuid.UUID `json:"parent_id" form:"parent_id"`
+	Role     string     `json:"role" form:"role" binding:"omitempty,oneof=member admin"`
+}
+
+type UpdateGroupRequest struct {
+	ID       uuid.UUID  `json:"id" form:"id"`
+	Name     string     `json:"name" form:"name" binding:"required,min=3,max=100"`
+	ParentID *uuid.UUID `json:"parent_id" form:"parent_id"`
+	Role     string     `json:"role" form:"role" binding:"omitempty,oneof=member admin"`
+}
+
+type AddGroupMemberRequest struct {
+	UserID uuid.UUID `json:"user_id" form:"user_id" binding:"required"`
+}
+
+type SetPermissionsRequest struct {
+	Permissions []string `json:"permissions" form:"permissions" binding:"omitempty,dive,required,max=100"`
+}
+
+type EffectivePermissions struct {
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/google/uuid"
+)
+
+type ImpersonateRequest struct {
+	TargetID        uuid.UUID `json:"-" form:"-"`
+	Reason          string    `json:"reason" form:"reason" binding:"required,min=3,max=255"`
+	DurationMinutes int       `json:"duration_minutes" form:"duration_minutes" binding:"omitempty,min=1,max=60"`
+	IPAddress       string    `json:"-" form:"-"`
+	UserAgent       string    `json:"-" form:"-"`
+}
+
+type ImpersonationResponse struct {
+	SessionID   uuid.UUID  `json:"session_id"`
+	AccessToken string     `json:"access_token"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	User        model.User `json:"user"`
+	Actor       model.User `json:"actor"`
+}
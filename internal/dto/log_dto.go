@@ -0,0 +1,6 @@
+package dto
+
+type UpdateLogLevelsRequest struct {
+	Level      string            `json:"level" binding:"omitempty,oneof=debug info warn error"`
+	Components map[string]string `json:"components" binding:"omitempty,dive,omitempty,oneof=debug info warn error"`
+}
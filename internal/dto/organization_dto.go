@@ -0,0 +1,20 @@
+package dto
+
+type CreateOrganizationRequest struct {
+	Name string `json:"name" form:"name" binding:"required,min=3,max=100"`
+	Slug string `json:"slug" form:"slug" binding:"omitempty,min=3,max=63"`
+}
+
+type UpdateOrganizationRequest struct {
+	Name string `json:"name" form:"name" binding:"required,min=3,max=100"`
+	Slug string `json:"slug" form:"slug" binding:"omitempty,min=3,max=63"`
+}
+
+type UpdateOrganizationMemberRequest struct {
+	Role string `json:"role" form:"role" binding:"required,oneof=owner admin member"`
+}
+
+type CreateInvitationRequest struct {
+	Email string `json:"email" form:"email" binding:"required,email,max=100"`
+	Role  string `json:"role" form:"role" binding:"omitempty,oneof=admin member"`
+}
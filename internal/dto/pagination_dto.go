@@ -1,17 +1,27 @@
 package dto
 
+// PaginationRequest selects a page by number, or by a cursor from a previous
+// response. Cursors stay stable while rows are inserted, pages do not.
 type PaginationRequest struct {
-	Page  int `json:"page" form:"page" binding:"omitempty,min=1"`
-	Limit int `json:"limit" form:"limit" binding:"omitempty,min=1,max=100"`
+	Page   int    `json:"page" form:"page" binding:"omitempty,min=1"`
+	Limit  int    `json:"limit" form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor string `json:"cursor" form:"cursor" binding:"omitempty,max=512"`
+	// IncludeTotal counts the matching rows, by default only without a
+	// cursor.
+	IncludeTotal *bool `json:"include_total" form:"include_total"`
 }
 
+// PaginationResponse describes a page. Page and the totals are left out
+// when the page was selected by cursor or counting was skipped.
 type PaginationResponse struct {
-	Page       int   `json:"page"`
-	Limit      int   `json:"limit"`
-	TotalItems int64 `json:"total_items"`
-	TotalPages int   `json:"total_pages"`
-	HasNext    bool  `json:"has_next"`
-	HasPrev    bool  `json:"has_prev"`
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	TotalItems *int64 `json:"total_items,omitempty"`
+	TotalPages int    `json:"total_pages,omitempty"`
+	HasNext    bool   `json:"has_next"`
+	HasPrev    bool   `json:"has_prev"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
 
 type PaginatedResult[T any] struct {
@@ -35,6 +45,14 @@ func (p *PaginationRequest) SetDefaults() {
 	}
 }
 
+// CountTotal reports whether the total should be counted.
+func (p *PaginationRequest) CountTotal() bool {
+	if p.IncludeTotal != nil {
+		return *p.IncludeTotal
+	}
+	return p.Cursor == ""
+}
+
 func NewPaginationResponse(page, limit int, totalItems int64) PaginationResponse {
 	totalPages := int((totalItems + int64(limit) - 1) / int64(limit))
 	if totalPages == 0 {
@@ -44,9 +62,15 @@ func NewPaginationResponse(page, limit int, totalItems int64) PaginationResponse
 	return PaginationResponse{
 		Page:       page,
 		Limit:      limit,
-		TotalItems: totalItems,
+		TotalItems: &totalItems,
 		TotalPages: totalPages,
 		HasNext:    page < totalPages,
 		HasPrev:    page > 1,
 	}
 }
+
+// WithTotal adds the total of a page selected by cursor.
+func (p PaginationResponse) WithTotal(totalItems int64) PaginationResponse {
+	p.TotalItems = &totalItems
+	return p
+}
@@ -1,23 +1,116 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"mime/multipart"
+
+	"github.com/Alfian57/belajar-golang/internal/listing"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/internal/utils/search"
+	"github.com/google/uuid"
+)
 
 type CreateUserRequest struct {
-	Email                string `json:"email" form:"email" binding:"required,min=3,max=100,email"`
-	Username             string `json:"username" form:"username" binding:"required,min=3,max=100"`
-	Password             string `json:"password" form:"password" binding:"required,min=8"`
+	Email                string `json:"email" form:"email" binding:"required,min=3,max=100,email,unique_email"`
+	Username             string `json:"username" form:"username" binding:"required,min=3,max=100,username,unique_username"`
+	Password             string `json:"password" form:"password" binding:"required,password_strength"`
 	PasswordConfirmation string `json:"password_confirmation" form:"password_confirmation" binding:"required,eqfield=Password"`
 }
 
 type UpdateUserRequest struct {
 	ID       uuid.UUID `json:"id" form:"id"`
-	Email    string    `json:"email" form:"email" binding:"required,min=3,max=100,email"`
-	Username string    `json:"username" form:"username" binding:"required,min=3"`
+	Email    string    `json:"email" form:"email" binding:"required,min=3,max=100,email,unique_email=ID"`
+	Username string    `json:"username" form:"username" binding:"required,min=3,max=100,username,unique_username=ID"`
+}
+
+// BulkCreateUsersRequest creates many users, validated like
+// CreateUserRequest one by one.
+type BulkCreateUsersRequest struct {
+	Mode  string              `json:"mode" form:"mode" binding:"omitempty,oneof=atomic best_effort"`
+	Users []CreateUserRequest `json:"users" form:"users" binding:"required,min=1"`
+}
+
+// BulkUpdateUsersRequest updates many users by the ID of each item.
+type BulkUpdateUsersRequest struct {
+	Mode  string              `json:"mode" form:"mode" binding:"omitempty,oneof=atomic best_effort"`
+	Users []UpdateUserRequest `json:"users" form:"users" binding:"required,min=1"`
 }
 
+type BulkDeleteUsersRequest struct {
+	Mode string      `json:"mode" form:"mode" binding:"omitempty,oneof=atomic best_effort"`
+	IDs  []uuid.UUID `json:"ids" form:"ids" binding:"required,min=1"`
+}
+
+// GetUsersFilter selects users. Filter, Sort and Fields take the fields of
+// UserListing. OrderBy and OrderType are the older form of Sort, which
+// takes precedence.
 type GetUsersFilter struct {
 	PaginationRequest
-	Search    string `json:"search" form:"search" binding:"omitempty,max=255"`
-	OrderBy   string `json:"order_by" form:"order_by" binding:"omitempty,oneof=username created_at"`
-	OrderType string `json:"order_type" form:"order_type" binding:"omitempty,oneof=ASC DESC asc desc"`
+	Search    string         `json:"search" form:"search" binding:"omitempty,max=255"`
+	OrderBy   string         `json:"order_by" form:"order_by" binding:"omitempty,oneof=username created_at"`
+	OrderType string         `json:"order_type" form:"order_type" binding:"omitempty,oneof=ASC DESC asc desc"`
+	Filter    listing.Filter `json:"filter" form:"filter"`
+	Sort      string         `json:"sort" form:"sort" binding:"omitempty,max=255"`
+	Fields    string         `json:"fields" form:"fields" binding:"omitempty,max=255"`
+}
+
+// UserListing declares the fields of users that can be filtered, sorted and
+// selected.
+var UserListing = listing.Spec{
+	Fields: []listing.Field{
+		{Name: "id", Column: "id", Kind: listing.UUID, Operators: listing.Equality},
+		{Name: "email", Column: "email", Kind: listing.String, Operators: listing.Text, Sortable: true},
+		{Name: "username", Column: "username", Kind: listing.String, Operators: listing.Text, Sortable: true},
+		{Name: "role", Column: "role", Kind: listing.String, Operators: listing.Equality},
+		{Name: "is_banned", Column: "is_banned", Kind: listing.Bool, Operators: []listing.Operator{listing.Eq, listing.Ne}},
+		{Name: "created_at", Column: "created_at", Kind: listing.Time, Operators: listing.Range, Sortable: true},
+		{Name: "updated_at", Column: "updated_at", Kind: listing.Time, Operators: listing.Range, Sortable: true},
+	},
+	Key:         "id",
+	DefaultSort: "created_at",
+}
+
+// ExportUsersRequest selects the users to export like GetUsersFilter, whose
+// pagination parameters are ignored. Fields picks the columns.
+type ExportUsersRequest struct {
+	GetUsersFilter
+	Format string `json:"format" form:"format" binding:"omitempty,oneof=csv xlsx"`
+}
+
+// Password sources of imports. File reads the password column, generate
+// creates a password per user that only the invite email tells.
+const (
+	ImportPasswordsFile     = "file"
+	ImportPasswordsGenerate = "generate"
+)
+
+// ImportUsersRequest creates users from the rows of a CSV or XLSX file whose
+// first row names the columns. Mapping maps the email, username and
+// password fields to the header of their column, which by default is the
+// field name in any case. Format defaults to the file extension.
+type ImportUsersRequest struct {
+	File      *multipart.FileHeader `json:"file" form:"file" binding:"required"`
+	Format    string                `json:"format" form:"format" binding:"omitempty,oneof=csv xlsx"`
+	Mapping   map[string]string     `json:"mapping" form:"mapping"`
+	DryRun    bool                  `json:"dry_run" form:"dry_run"`
+	Passwords string                `json:"passwords" form:"passwords" binding:"omitempty,oneof=file generate"`
+	// Invite emails each created user a sign-in link, required with
+	// generated passwords
+	Invite bool `json:"invite" form:"invite" binding:"required_if=Passwords generate"`
+}
+
+// SearchUsersRequest searches users by username and email, e.g. for a user
+// picker. Mode fulltext matches words by prefix, fuzzy tolerates typos and
+// auto, the default, does both.
+type SearchUsersRequest struct {
+	Query string `json:"q" form:"q" binding:"required,max=100"`
+	Mode  string `json:"mode" form:"mode" binding:"omitempty,oneof=auto fulltext fuzzy"`
+	Limit int    `json:"limit" form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// UserSearchResult is a user found by a search, with the relevance of the
+// match and the matched fragments of its username and email.
+type UserSearchResult struct {
+	model.User
+	Rank       float64                      `json:"rank"`
+	Highlights map[string][]search.Fragment `json:"highlights"`
 }
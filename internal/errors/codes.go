@@ -0,0 +1,162 @@
+package errs
+
+import "net/http"
+
+// Code is a stable, machine readable error code. Clients branch on codes, so
+// a code is never renamed or reused; add a new one instead.
+type Code string
+
+// Generic codes, used for errors without a code of their own.
+const (
+	CodeBadRequest       Code = "bad_request"
+	CodeUnauthorized     Code = "unauthorized"
+	CodeForbidden        Code = "forbidden"
+	CodeNotFound         Code = "not_found"
+	CodeConflict         Code = "conflict"
+	CodeValidationFailed Code = "validation_failed"
+	CodeTooManyRequests  Code = "too_many_requests"
+	CodeInternal         Code = "internal_error"
+)
+
+const (
+	CodeInvalidCredentials   Code = "invalid_credentials"
+	CodeTokenNotFound        Code = "token_not_found"
+	CodeRefreshTokenNotFound Code = "refresh_token_not_found"
+	CodeInvalidTokenClaims   Code = "invalid_token_claims"
+	CodeMagicLinkInvalid     Code = "magic_link_invalid"
+
+	CodeUserNotFound   Code = "user_not_found"
+	CodeUsernameTaken  Code = "username_taken"
+	CodeUserReferenced Code = "user_referenced"
+	CodeBulkFailed     Code = "bulk_failed"
+
+	CodeImportJobNotFound Code = "import_job_not_found"
+
+	CodeOrganizationNotFound       Code = "organization_not_found"
+	CodeOrganizationMemberNotFound Code = "organization_member_not_found"
+	CodeInvitationNotFound         Code = "invitation_not_found"
+	CodeInvitationInvalid          Code = "invitation_invalid"
+	CodeLastOrganizationOwner      Code = "last_organization_owner"
+
+	CodeGroupNotFound       Code = "group_not_found"
+	CodeGroupMemberNotFound Code = "group_member_not_found"
+
+	CodeImpersonationSessionNotFound Code = "impersonation_session_not_found"
+	CodeImpersonationNotAllowed      Code = "impersonation_not_allowed"
+	CodeImpersonationTargetInvalid   Code = "impersonation_target_invalid"
+)
+
+// CodeInfo describes a code in the catalog.
+type CodeInfo struct {
+	Code   Code   `json:"code"`
+	Status int    `json:"status"`
+	Title  string `json:"title"`
+}
+
+// catalog lists every code with the status it is returned with and a short
+// title that does not change between occurrences.
+var catalog = []CodeInfo{
+	{CodeBadRequest, http.StatusBadRequest, "Bad request"},
+	{CodeUnauthorized, http.StatusUnauthorized, "Unauthorized"},
+	{CodeForbidden, http.StatusForbidden, "Forbidden"},
+	{CodeNotFound, http.StatusNotFound, "Not found"},
+	{CodeConflict, http.StatusConflict, "Conflict"},
+	{CodeValidationFailed, http.StatusUnprocessableEntity, "Validation failed"},
+	{CodeTooManyRequests, http.StatusTooManyRequests, "Too many requests"},
+	{CodeInternal, http.StatusInternalServerError, "Internal server error"},
+
+	{CodeInvalidCredentials, http.StatusUnauthorized, "Invalid credentials"},
+	{CodeTokenNotFound, http.StatusUnauthorized, "Token not found"},
+	{CodeRefreshTokenNotFound, http.StatusUnauthorized, "Refresh token not found"},
+	{CodeInvalidTokenClaims, http.StatusUnauthorized, "Invalid token claims"},
+	{CodeMagicLinkInvalid, http.StatusUnauthorized, "Invalid sign-in link"},
+
+	{CodeUserNotFound, http.StatusNotFound, "User not found"},
+	{CodeUsernameTaken, http.StatusUnprocessableEntity, "Username taken"},
+	{CodeUserReferenced, http.StatusConflict, "User is referenced"},
+	{CodeBulkFailed, http.StatusUnprocessableEntity, "Bulk operation failed"},
+
+	{CodeImportJobNotFound, http.StatusNotFound, "Import job not found"},
+
+	{CodeOrganizationNotFound, http.StatusNotFound, "Organization not found"},
+	{CodeOrganizationMemberNotFound, http.StatusNotFound, "Organization member not found"},
+	{CodeInvitationNotFound, http.StatusNotFound, "Invitation not found"},
+	{CodeInvitationInvalid, http.StatusUnprocessableEntity, "Invitation no longer valid"},
+	{CodeLastOrganizationOwner, http.StatusUnprocessableEntity, "Last organization owner"},
+
+	{CodeGroupNotFound, http.StatusNotFound, "Group not found"},
+	{CodeGroupMemberNotFound, http.StatusNotFound, "Group member not found"},
+
+	{CodeImpersonationSessionNotFound, http.StatusNotFound, "Impersonation session not found"},
+	{CodeImpersonationNotAllowed, http.StatusForbidden, "Not allowed while impersonating"},
+	{CodeImpersonationTargetInvalid, http.StatusUnprocessableEntity, "User cannot be impersonated"},
+}
+
+// Codes returns the catalog of error codes.
+func Codes() []CodeInfo {
+	return append([]CodeInfo(nil), catalog...)
+}
+
+// Lookup returns the catalog entry of code.
+func Lookup(code Code) (CodeInfo, bool) {
+	for _, info := range catalog {
+		if info.Code == code {
+			return info, true
+		}
+	}
+
+	return CodeInfo{}, false
+}
+
+// CodeForStatus returns the generic code of an HTTP status, for errors
+// created without a code.
+func CodeForStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusUnprocessableEntity:
+		return CodeValidationFailed
+	case http.StatusTooManyRequests:
+		return CodeTooManyRequests
+	}
+
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
+
+// Field error codes. Binding failures use the validator tag instead, such as
+// "required", "min" or "email".
+const (
+	FieldInvalid          = "invalid"
+	FieldUnknown          = "unknown"
+	FieldSlug             = "slug"
+	FieldNotFound         = "not_found"
+	FieldCycle            = "cycle"
+	FieldTooDeep          = "too_deep"
+	FieldGlobalOnly       = "global_only"
+	FieldTaken            = "taken"
+	FieldIncorrect        = "incorrect"
+	FieldUnchanged        = "unchanged"
+	FieldReused           = "reused"
+	FieldAlreadyMember    = "already_member"
+	FieldTooShort         = "too_short"
+	FieldMissingUppercase = "missing_uppercase"
+	FieldMissingLowercase = "missing_lowercase"
+	FieldMissingDigit     = "missing_digit"
+	FieldMissingSymbol    = "missing_symbol"
+	FieldContainsIdentity = "contains_identity"
+	FieldBreached         = "breached"
+	FieldTooMany          = "too_many"
+	FieldDuplicate        = "duplicate"
+	FieldTooLarge         = "too_large"
+)
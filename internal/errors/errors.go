@@ -6,9 +6,13 @@ import (
 )
 
 // FieldError represents an error on a specific field during validation.
+// Param is interpolated into translated messages, like the parameter of a
+// validator tag.
 type FieldError struct {
 	Field string `json:"field"`
+	Code  string `json:"code"`
 	Error string `json:"error"`
+	Param string `json:"-"`
 }
 
 // ValidationError represents a collection of validation errors.
@@ -17,10 +21,12 @@ type ValidationError struct {
 }
 
 // AppError is a general application error with an HTTP code and message.
+// ErrorCode is the stable code clients branch on.
 type AppError struct {
-	Code    int    `json:"-"`
-	Message string `json:"message"`
-	Err     error  `json:"-"`
+	Code      int    `json:"-"`
+	ErrorCode Code   `json:"code"`
+	Message   string `json:"message"`
+	Err       error  `json:"-"`
 }
 
 // Error implements the error interface for AppError.
@@ -36,6 +42,29 @@ func (e *AppError) Unwrap() error {
 	return e.Err
 }
 
+// Is matches errors with the same code, so a wrapped copy of a sentinel
+// still matches it with errors.Is.
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	return ok && t.ErrorCode != "" && t.ErrorCode == e.ErrorCode
+}
+
+// Wrap returns a copy of the error with err as its cause.
+func (e *AppError) Wrap(err error) *AppError {
+	wrapped := *e
+	wrapped.Err = err
+	return &wrapped
+}
+
+// GetErrorCode returns the code of the error, or the generic code of its
+// status when it has none.
+func (e *AppError) GetErrorCode() Code {
+	if e.ErrorCode != "" {
+		return e.ErrorCode
+	}
+	return CodeForStatus(e.Code)
+}
+
 // Error implements the error interface for ValidationError.
 func (e *ValidationError) Error() string {
 	if len(e.Errors) == 0 {
@@ -44,12 +73,14 @@ func (e *ValidationError) Error() string {
 	return e.Errors[0].Error
 }
 
-// Helper function to create a new AppError.
+// Helper function to create a new AppError with the generic code of its
+// status.
 func NewAppError(code int, message string, err error) *AppError {
 	return &AppError{
-		Code:    code,
-		Message: message,
-		Err:     err,
+		Code:      code,
+		ErrorCode: CodeForStatus(code),
+		Message:   message,
+		Err:       err,
 	}
 }
 
@@ -59,21 +90,45 @@ func NewValidationError(fieldErrors []FieldError) *ValidationError {
 }
 
 // Helper function to create a new FieldError.
-func NewFieldError(field, message string) FieldError {
-	return FieldError{Field: field, Error: message}
+func NewFieldError(field, code, message string) FieldError {
+	return FieldError{Field: field, Code: code, Error: message}
+}
+
+// WithParam returns a copy of the field error with param set.
+func (e FieldError) WithParam(param string) FieldError {
+	e.Param = param
+	return e
 }
 
 // Common errors used in the application.
 var (
-	ErrTokenNotFound        = &AppError{Code: http.StatusUnauthorized, Message: "token not found"}
-	ErrRefreshTokenNotFound = &AppError{Code: http.StatusUnauthorized, Message: "invalid token claims"}
-	ErrInvalidTokenClaims   = &AppError{Code: http.StatusInternalServerError, Message: "token not found"}
+	ErrTokenNotFound        = &AppError{Code: http.StatusUnauthorized, ErrorCode: CodeTokenNotFound, Message: "token not found"}
+	ErrRefreshTokenNotFound = &AppError{Code: http.StatusUnauthorized, ErrorCode: CodeRefreshTokenNotFound, Message: "refresh token not found"}
+	ErrInvalidTokenClaims   = &AppError{Code: http.StatusUnauthorized, ErrorCode: CodeInvalidTokenClaims, Message: "invalid token claims"}
+	ErrInvalidCredentials   = &AppError{Code: http.StatusUnauthorized, ErrorCode: CodeInvalidCredentials, Message: "username or password is incorrect"}
+	ErrMagicLinkInvalid     = &AppError{Code: http.StatusUnauthorized, ErrorCode: CodeMagicLinkInvalid, Message: "sign-in link is invalid or has expired"}
+
+	ErrUserNotFound   = &AppError{Code: http.StatusNotFound, ErrorCode: CodeUserNotFound, Message: "user not found"}
+	ErrUsernameExist  = &AppError{Code: http.StatusUnprocessableEntity, ErrorCode: CodeUsernameTaken, Message: "username already exists"}
+	ErrUserReferenced = &AppError{Code: http.StatusConflict, ErrorCode: CodeUserReferenced, Message: "user is part of an audit trail and cannot be deleted, ban them instead"}
+
+	ErrImportJobNotFound = &AppError{Code: http.StatusNotFound, ErrorCode: CodeImportJobNotFound, Message: "import job not found"}
+
+	ErrOrganizationNotFound       = &AppError{Code: http.StatusNotFound, ErrorCode: CodeOrganizationNotFound, Message: "organization not found"}
+	ErrOrganizationMemberNotFound = &AppError{Code: http.StatusNotFound, ErrorCode: CodeOrganizationMemberNotFound, Message: "organization member not found"}
+	ErrInvitationNotFound         = &AppError{Code: http.StatusNotFound, ErrorCode: CodeInvitationNotFound, Message: "invitation not found"}
+	ErrInvitationInvalid          = &AppError{Code: http.StatusUnprocessableEntity, ErrorCode: CodeInvitationInvalid, Message: "invitation is no longer valid"}
+	ErrLastOrganizationOwner      = &AppError{Code: http.StatusUnprocessableEntity, ErrorCode: CodeLastOrganizationOwner, Message: "organization must keep at least one owner"}
+
+	ErrGroupNotFound       = &AppError{Code: http.StatusNotFound, ErrorCode: CodeGroupNotFound, Message: "group not found"}
+	ErrGroupMemberNotFound = &AppError{Code: http.StatusNotFound, ErrorCode: CodeGroupMemberNotFound, Message: "group member not found"}
 
-	ErrUserNotFound  = &AppError{Code: http.StatusNotFound, Message: "user not found"}
-	ErrUsernameExist = &AppError{Code: http.StatusUnprocessableEntity, Message: "username already exists"}
+	ErrImpersonationSessionNotFound = &AppError{Code: http.StatusNotFound, ErrorCode: CodeImpersonationSessionNotFound, Message: "impersonation session not found"}
+	ErrImpersonationNotAllowed      = &AppError{Code: http.StatusForbidden, ErrorCode: CodeImpersonationNotAllowed, Message: "action not allowed while impersonating"}
+	ErrImpersonationTargetInvalid   = &AppError{Code: http.StatusUnprocessableEntity, ErrorCode: CodeImpersonationTargetInvalid, Message: "user cannot be impersonated"}
 
-	ErrInternalServer = &AppError{Code: http.StatusInternalServerError, Message: "internal server error"}
-	ErrBadRequest     = &AppError{Code: http.StatusBadRequest, Message: "bad request"}
-	ErrUnauthorized   = &AppError{Code: http.StatusUnauthorized, Message: "unauthorized"}
-	ErrForbidden      = &AppError{Code: http.StatusForbidden, Message: "forbidden"}
+	ErrInternalServer = &AppError{Code: http.StatusInternalServerError, ErrorCode: CodeInternal, Message: "internal server error"}
+	ErrBadRequest     = &AppError{Code: http.StatusBadRequest, ErrorCode: CodeBadRequest, Message: "bad request"}
+	ErrUnauthorized   = &AppError{Code: http.StatusUnauthorized, ErrorCode: CodeUnauthorized, Message: "unauthorized"}
+	ErrForbidden      = &AppError{Code: http.StatusForbidden, ErrorCode: CodeForbidden, Message: "forbidden"}
 )
@@ -2,10 +2,15 @@ package handler
 
 import (
 	"net/http"
+	"strings"
 
+	"github.com/Alfian57/belajar-golang/internal/config"
 	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
 	"github.com/Alfian57/belajar-golang/internal/response"
 	"github.com/Alfian57/belajar-golang/internal/service"
+	"github.com/Alfian57/belajar-golang/internal/utils/auth"
+	"github.com/Alfian57/belajar-golang/internal/utils/token"
 	"github.com/gin-gonic/gin"
 )
 
@@ -32,11 +37,7 @@ func (h *AuthHandler) Login(ctx *gin.Context) {
 		return
 	}
 
-	accessTokenMaxAge := 15 * 60        // 15 minutes in seconds
-	refreshTokenMaxAge := 7 * 24 * 3600 // 7 days in seconds
-
-	ctx.SetCookie("access_token", credentials.AccessToken, accessTokenMaxAge, "/", "", false, true)
-	ctx.SetCookie("refresh_token", credentials.RefreshToken, refreshTokenMaxAge, "/", "", false, true)
+	setLoginCookies(ctx, credentials)
 
 	response.WriteMessageResponse(ctx, http.StatusOK, "user successfully logged in")
 }
@@ -92,3 +93,81 @@ func (h *AuthHandler) Logout(ctx *gin.Context) {
 
 	response.WriteMessageResponse(ctx, http.StatusOK, "user successfully logged out")
 }
+
+func (h *AuthHandler) ChangePassword(ctx *gin.Context) {
+	var request dto.ChangePasswordRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	if err := h.service.ChangePassword(ctx, user, request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "password successfully changed")
+}
+
+func (h *AuthHandler) RequestMagicLink(ctx *gin.Context) {
+	var request dto.MagicLinkRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	verifyURL := publicURL(ctx.FullPath() + "/verify")
+
+	h.service.RequestMagicLink(ctx, request, browserFingerprint(ctx), verifyURL)
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "if the email is registered, a sign-in link has been sent")
+}
+
+func (h *AuthHandler) VerifyMagicLink(ctx *gin.Context) {
+	var request dto.MagicLinkVerifyRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	credentials, err := h.service.LoginWithMagicLink(ctx, request.Token, browserFingerprint(ctx))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	setLoginCookies(ctx, credentials)
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "user successfully logged in")
+}
+
+func setLoginCookies(ctx *gin.Context, credentials dto.Credentials) {
+	accessTokenMaxAge := 15 * 60        // 15 minutes in seconds
+	refreshTokenMaxAge := 7 * 24 * 3600 // 7 days in seconds
+
+	ctx.SetCookie("access_token", credentials.AccessToken, accessTokenMaxAge, "/", "", false, true)
+	ctx.SetCookie("refresh_token", credentials.RefreshToken, refreshTokenMaxAge, "/", "", false, true)
+}
+
+// browserFingerprint identifies the requesting browser by its user agent.
+// It is empty when the client does not send one.
+func browserFingerprint(ctx *gin.Context) string {
+	userAgent := ctx.Request.UserAgent()
+	if userAgent == "" {
+		return ""
+	}
+	return token.Hash(userAgent)
+}
+
+// publicURL returns the absolute URL of path under the configured public URL.
+// Links sent by email are never built from the Host header, which the client
+// controls.
+func publicURL(path string) string {
+	return strings.TrimSuffix(config.Current().Server.PublicURL, "/") + path
+}
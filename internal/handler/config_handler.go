@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Alfian57/belajar-golang/internal/config"
+	"github.com/Alfian57/belajar-golang/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+type ConfigHandler struct{}
+
+func NewConfigHandler() *ConfigHandler {
+	return &ConfigHandler{}
+}
+
+// GetConfig shows the effective configuration with secrets redacted
+func (h *ConfigHandler) GetConfig(ctx *gin.Context) {
+	response.WriteDataResponse(ctx, http.StatusOK, config.Current().Settings())
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"path"
+
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/openapi"
+	"github.com/Alfian57/belajar-golang/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+type DocsHandler struct {
+	document []byte
+}
+
+func NewDocsHandler(document *openapi.Document) (*DocsHandler, error) {
+	encoded, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DocsHandler{document: encoded}, nil
+}
+
+// Spec serves the OpenAPI document
+func (h *DocsHandler) Spec(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "application/json", h.document)
+}
+
+// UI serves Swagger UI for the OpenAPI document
+func (h *DocsHandler) UI(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", openapi.SwaggerUI)
+}
+
+// Asset serves a stylesheet or script of Swagger UI
+func (h *DocsHandler) Asset(ctx *gin.Context) {
+	file := ctx.Param("file")
+
+	extension := path.Ext(file)
+	if extension != ".css" && extension != ".js" {
+		response.WriteErrorResponse(ctx, errs.NewAppError(http.StatusNotFound, "asset not found", nil))
+		return
+	}
+	if _, err := fs.Stat(openapi.SwaggerUIAssets, file); err != nil {
+		response.WriteErrorResponse(ctx, errs.NewAppError(http.StatusNotFound, "asset not found", nil))
+		return
+	}
+
+	ctx.FileFromFS(file, http.FS(openapi.SwaggerUIAssets))
+}
+
+// Problems serves the catalog of error codes
+func (h *DocsHandler) Problems(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, errs.Codes())
+}
+
+// Problem serves the catalog entry of an error code, the type of problem
+// responses points here
+func (h *DocsHandler) Problem(ctx *gin.Context) {
+	info, ok := errs.Lookup(errs.Code(ctx.Param("code")))
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.NewAppError(http.StatusNotFound, "error code not found", nil))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, info)
+}
@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	"github.com/Alfian57/belajar-golang/internal/response"
+	"github.com/Alfian57/belajar-golang/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type GroupHandler struct {
+	service *service.GroupService
+}
+
+func NewGroupHandler(s *service.GroupService) *GroupHandler {
+	return &GroupHandler{
+		service: s,
+	}
+}
+
+func (h *GroupHandler) GetAllGroups(ctx *gin.Context) {
+	groups, err := h.service.GetAllGroups(ctx)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, groups)
+}
+
+func (h *GroupHandler) CreateGroup(ctx *gin.Context) {
+	var request dto.CreateGroupRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	group, err := h.service.CreateGroup(ctx, request)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusCreated, group)
+}
+
+func (h *GroupHandler) GetGroupByID(ctx *gin.Context) {
+	id, err := uuidParam(ctx, "id")
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	group, err := h.service.GetGroupByID(ctx, id.String())
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, group)
+}
+
+func (h *GroupHandler) UpdateGroup(ctx *gin.Context) {
+	var request dto.UpdateGroupRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	id, err := uuidParam(ctx, "id")
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+	request.ID = id
+
+	group, err := h.service.UpdateGroup(ctx, request)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, group)
+}
+
+func (h *GroupHandler) DeleteGroup(ctx *gin.Context) {
+	id, err := uuidParam(ctx, "id")
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	if err := h.service.DeleteGroup(ctx, id); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "group successfully deleted")
+}
+
+func (h *GroupHandler) GetMembers(ctx *gin.Context) {
+	id, err := uuidParam(ctx, "id")
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	members, err := h.service.GetMembers(ctx, id)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, members)
+}
+
+func (h *GroupHandler) AddMember(ctx *gin.Context) {
+	var request dto.AddGroupMemberRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	id, err := uuidParam(ctx, "id")
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	if err := h.service.AddMember(ctx, id, request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusCreated, "group member successfully added")
+}
+
+func (h *GroupHandler) RemoveMember(ctx *gin.Context) {
+	id, err := uuidParam(ctx, "id")
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	userID, err := uuidParam(ctx, "user_id")
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	if err := h.service.RemoveMember(ctx, id, userID); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "group member successfully removed")
+}
+
+func (h *GroupHandler) SetGroupPermissions(ctx *gin.Context) {
+	var request dto.SetPermissionsRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	id, err := uuidParam(ctx, "id")
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	if err := h.service.SetGroupPermissions(ctx, id, request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "group permissions successfully updated")
+}
+
+func (h *GroupHandler) GetUserGroups(ctx *gin.Context) {
+	id, err := uuidParam(ctx, "id")
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	groups, err := h.service.GetEffectiveGroups(ctx, id)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, groups)
+}
+
+func (h *GroupHandler) GetUserPermissions(ctx *gin.Context) {
+	id, err := uuidParam(ctx, "id")
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	permissions, err := h.service.GetEffectivePermissions(ctx, id)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, permissions)
+}
+
+func (h *GroupHandler) SetUserPermissions(ctx *gin.Context) {
+	var request dto.SetPermissionsRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	id, err := uuidParam(ctx, "id")
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	if err := h.service.SetUserPermissions(ctx, id, request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "user permissions successfully updated")
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Alfian57/belajar-golang/internal/health"
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct{}
+
+func NewHealthHandler() *HealthHandler {
+	return &HealthHandler{}
+}
+
+// Live reports that the process is running. It checks no dependencies, so a
+// database outage does not make the orchestrator restart healthy pods.
+//
+// Probes only read the status code; the bodies use the common health check
+// shape instead of the API envelope.
+func (h *HealthHandler) Live(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, health.Report{Status: health.StatusUp})
+}
+
+// Ready reports whether the API can serve traffic
+func (h *HealthHandler) Ready(ctx *gin.Context) {
+	report := health.Ready(ctx.Request.Context())
+
+	statusCode := http.StatusOK
+	if report.Status != health.StatusUp {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(statusCode, report)
+}
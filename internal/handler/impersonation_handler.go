@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/response"
+	"github.com/Alfian57/belajar-golang/internal/service"
+	"github.com/Alfian57/belajar-golang/internal/utils/auth"
+	"github.com/gin-gonic/gin"
+)
+
+type ImpersonationHandler struct {
+	service *service.ImpersonationService
+}
+
+func NewImpersonationHandler(s *service.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		service: s,
+	}
+}
+
+func (h *ImpersonationHandler) Start(ctx *gin.Context) {
+	var request dto.ImpersonateRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	id, err := uuidParam(ctx, "id")
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+	request.TargetID = id
+	request.IPAddress = ctx.ClientIP()
+	request.UserAgent = truncate(ctx.Request.UserAgent(), 255)
+
+	actor, ok := auth.GetCurrentActor(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	result, err := h.service.Start(ctx, actor, request)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusCreated, result)
+}
+
+func (h *ImpersonationHandler) Stop(ctx *gin.Context) {
+	sessionID, ok := auth.GetImpersonationSessionID(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrImpersonationSessionNotFound)
+		return
+	}
+
+	if err := h.service.End(ctx, sessionID); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "impersonation successfully ended")
+}
+
+func truncate(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+	return string(runes[:max])
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+type LogHandler struct{}
+
+func NewLogHandler() *LogHandler {
+	return &LogHandler{}
+}
+
+// GetLevels shows the root log level and the level of each component
+func (h *LogHandler) GetLevels(ctx *gin.Context) {
+	response.WriteDataResponse(ctx, http.StatusOK, logger.CurrentLevels())
+}
+
+// UpdateLevels changes log levels until the next restart or config reload.
+// Omitted fields keep their level, an empty component level follows the root
+// level again.
+func (h *LogHandler) UpdateLevels(ctx *gin.Context) {
+	var request dto.UpdateLogLevelsRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	// Check every component before changing any
+	known := logger.CurrentLevels().Components
+	for component := range request.Components {
+		if _, ok := known[component]; !ok {
+			response.WriteErrorResponse(ctx, errs.NewValidationError([]errs.FieldError{
+				errs.NewFieldError("components", errs.FieldUnknown, "unknown log component "+component).WithParam(component),
+			}))
+			return
+		}
+	}
+
+	// Levels were validated by binding
+	if request.Level != "" {
+		_ = logger.SetLevel(request.Level)
+	}
+	for component, level := range request.Components {
+		_ = logger.SetComponentLevel(component, level)
+	}
+
+	levels := logger.CurrentLevels()
+	logger.FromContext(ctx).Infow("log levels changed", "levels", levels)
+	response.WriteDataResponse(ctx, http.StatusOK, levels)
+}
@@ -0,0 +1,310 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/response"
+	"github.com/Alfian57/belajar-golang/internal/service"
+	"github.com/Alfian57/belajar-golang/internal/tenant"
+	"github.com/Alfian57/belajar-golang/internal/utils/auth"
+	"github.com/gin-gonic/gin"
+)
+
+type OrganizationHandler struct {
+	service *service.OrganizationService
+}
+
+func NewOrganizationHandler(s *service.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		service: s,
+	}
+}
+
+func (h *OrganizationHandler) GetMyOrganizations(ctx *gin.Context) {
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	organizations, err := h.service.GetUserOrganizations(ctx, user)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, organizations)
+}
+
+func (h *OrganizationHandler) CreateOrganization(ctx *gin.Context) {
+	var request dto.CreateOrganizationRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	organization, err := h.service.CreateOrganization(ctx, user, request)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusCreated, organization)
+}
+
+func (h *OrganizationHandler) GetOrganization(ctx *gin.Context) {
+	organization, ok := tenant.FromContext(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrOrganizationNotFound)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, organization)
+}
+
+func (h *OrganizationHandler) UpdateOrganization(ctx *gin.Context) {
+	var request dto.UpdateOrganizationRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	organization, ok := tenant.FromContext(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrOrganizationNotFound)
+		return
+	}
+
+	organization, err := h.service.UpdateOrganization(ctx, organization, request)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, organization)
+}
+
+func (h *OrganizationHandler) DeleteOrganization(ctx *gin.Context) {
+	organization, ok := tenant.FromContext(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrOrganizationNotFound)
+		return
+	}
+
+	if err := h.service.DeleteOrganization(ctx, organization); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "organization successfully deleted")
+}
+
+func (h *OrganizationHandler) GetMembers(ctx *gin.Context) {
+	organization, ok := tenant.FromContext(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrOrganizationNotFound)
+		return
+	}
+
+	members, err := h.service.GetMembers(ctx, organization)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, members)
+}
+
+func (h *OrganizationHandler) UpdateMember(ctx *gin.Context) {
+	var request dto.UpdateOrganizationMemberRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	organization, ok := tenant.FromContext(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrOrganizationNotFound)
+		return
+	}
+
+	userID, err := uuidParam(ctx, "user_id")
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	if err := h.service.UpdateMemberRole(ctx, organization, user, userID, request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "organization member successfully updated")
+}
+
+func (h *OrganizationHandler) RemoveMember(ctx *gin.Context) {
+	organization, ok := tenant.FromContext(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrOrganizationNotFound)
+		return
+	}
+
+	userID, err := uuidParam(ctx, "user_id")
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	if err := h.service.RemoveMember(ctx, organization, user, userID); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "organization member successfully removed")
+}
+
+func (h *OrganizationHandler) Leave(ctx *gin.Context) {
+	organization, ok := tenant.FromContext(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrOrganizationNotFound)
+		return
+	}
+
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	if err := h.service.RemoveMember(ctx, organization, user, user.ID); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "organization successfully left")
+}
+
+func (h *OrganizationHandler) GetInvitations(ctx *gin.Context) {
+	organization, ok := tenant.FromContext(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrOrganizationNotFound)
+		return
+	}
+
+	invitations, err := h.service.GetPendingInvitations(ctx, organization)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, invitations)
+}
+
+func (h *OrganizationHandler) CreateInvitation(ctx *gin.Context) {
+	var request dto.CreateInvitationRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	organization, ok := tenant.FromContext(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrOrganizationNotFound)
+		return
+	}
+
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	// Invitation links point at the public invitation endpoint of the same API version
+	apiPrefix, _, _ := strings.Cut(ctx.FullPath(), "/organizations/")
+	invitationURL := publicURL(apiPrefix + "/invitations")
+
+	invitation, err := h.service.Invite(ctx, organization, user, request, invitationURL)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusCreated, invitation)
+}
+
+func (h *OrganizationHandler) RevokeInvitation(ctx *gin.Context) {
+	organization, ok := tenant.FromContext(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrOrganizationNotFound)
+		return
+	}
+
+	invitationID, err := uuidParam(ctx, "invitation_id")
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	if err := h.service.RevokeInvitation(ctx, organization, invitationID); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "invitation successfully revoked")
+}
+
+func (h *OrganizationHandler) GetInvitation(ctx *gin.Context) {
+	invitation, err := h.service.GetInvitation(ctx, ctx.Param("token"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, invitation)
+}
+
+func (h *OrganizationHandler) AcceptInvitation(ctx *gin.Context) {
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	organization, err := h.service.AcceptInvitation(ctx, user, ctx.Param("token"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, organization)
+}
+
+func (h *OrganizationHandler) DeclineInvitation(ctx *gin.Context) {
+	if err := h.service.DeclineInvitation(ctx, ctx.Param("token")); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "invitation successfully declined")
+}
@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"github.com/Alfian57/belajar-golang/internal/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// uuidParam returns the path parameter name as a UUID. Malformed IDs fail
+// the uuid_param rule with a 422 instead of reaching the service.
+func uuidParam(ctx *gin.Context, name string) (uuid.UUID, error) {
+	value := ctx.Param(name)
+	if err := validation.Value(name, value, "uuid_param"); err != nil {
+		return uuid.Nil, err
+	}
+
+	return uuid.Parse(value)
+}
@@ -4,10 +4,12 @@ import (
 	"net/http"
 
 	"github.com/Alfian57/belajar-golang/internal/dto"
+	"github.com/Alfian57/belajar-golang/internal/listing"
+	"github.com/Alfian57/belajar-golang/internal/logger"
 	"github.com/Alfian57/belajar-golang/internal/response"
 	"github.com/Alfian57/belajar-golang/internal/service"
+	"github.com/Alfian57/belajar-golang/internal/utils/spreadsheet"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 type UserHandler struct {
@@ -29,13 +31,93 @@ func (h *UserHandler) GetAllUsers(ctx *gin.Context) {
 
 	query.PaginationRequest.SetDefaults()
 
+	// Binding only reads flat parameters, filter[role]=member is nested
+	filter, err := listing.ParseFilter(ctx.Request.URL.Query())
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+	if len(filter) > 0 {
+		query.Filter = filter
+	}
+
 	result, err := h.service.GetAllUsers(ctx, query)
 	if err != nil {
 		response.WriteErrorResponse(ctx, err)
 		return
 	}
 
-	response.WritePaginatedResponse(ctx, http.StatusOK, result)
+	if query.Fields == "" {
+		response.WritePaginatedResponse(ctx, http.StatusOK, result)
+		return
+	}
+
+	data, err := listing.Select(result.Data, query.Fields)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+	response.WritePaginatedResponse(ctx, http.StatusOK, dto.PaginatedResult[map[string]any]{
+		Data:       data,
+		Pagination: result.Pagination,
+	})
+}
+
+// ExportUsers downloads the users of a listing as a CSV or XLSX file.
+func (h *UserHandler) ExportUsers(ctx *gin.Context) {
+	var request dto.ExportUsersRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	filter, err := listing.ParseFilter(ctx.Request.URL.Query())
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+	if len(filter) > 0 {
+		request.Filter = filter
+	}
+	if request.Format == "" {
+		request.Format = spreadsheet.CSV
+	}
+
+	ctx.Header("Content-Type", spreadsheet.ContentType(request.Format))
+	ctx.Header("Content-Disposition", `attachment; filename="users.`+request.Format+`"`)
+	if err := h.service.ExportUsers(ctx, request, ctx.Writer); err != nil {
+		// Once the file has started the status is sent, the download ends
+		// short instead
+		if ctx.Writer.Written() {
+			logger.FromContext(ctx).Errorw("export interrupted", "error", err)
+			return
+		}
+
+		// The error is JSON, not the file the headers announced
+		ctx.Writer.Header().Del("Content-Type")
+		ctx.Writer.Header().Del("Content-Disposition")
+		response.WriteErrorResponse(ctx, err)
+	}
+}
+
+func (h *UserHandler) SearchUsers(ctx *gin.Context) {
+	var request dto.SearchUsersRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	if request.Limit == 0 {
+		request.Limit = 10
+	}
+
+	results, err := h.service.SearchUsers(ctx, request)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, results)
 }
 
 func (h *UserHandler) CreateUser(ctx *gin.Context) {
@@ -54,9 +136,7 @@ func (h *UserHandler) CreateUser(ctx *gin.Context) {
 }
 
 func (h *UserHandler) GetUserByID(ctx *gin.Context) {
-	idParam := ctx.Param("id")
-
-	id, err := uuid.Parse(idParam)
+	id, err := uuidParam(ctx, "id")
 	if err != nil {
 		response.WriteErrorResponse(ctx, err)
 		return
@@ -78,7 +158,7 @@ func (h *UserHandler) UpdateUser(ctx *gin.Context) {
 		return
 	}
 
-	id, err := uuid.Parse(ctx.Param("id"))
+	id, err := uuidParam(ctx, "id")
 	if err != nil {
 		response.WriteErrorResponse(ctx, err)
 		return
@@ -94,9 +174,7 @@ func (h *UserHandler) UpdateUser(ctx *gin.Context) {
 }
 
 func (h *UserHandler) DeleteUser(ctx *gin.Context) {
-	idParam := ctx.Param("id")
-
-	id, err := uuid.Parse(idParam)
+	id, err := uuidParam(ctx, "id")
 	if err != nil {
 		response.WriteErrorResponse(ctx, err)
 		return
@@ -109,3 +187,51 @@ func (h *UserHandler) DeleteUser(ctx *gin.Context) {
 
 	response.WriteMessageResponse(ctx, http.StatusOK, "user successfully deleted")
 }
+
+func (h *UserHandler) BulkCreateUsers(ctx *gin.Context) {
+	var request dto.BulkCreateUsersRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	result, err := h.service.BulkCreateUsers(ctx, request)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteBulkResponse(ctx, result)
+}
+
+func (h *UserHandler) BulkUpdateUsers(ctx *gin.Context) {
+	var request dto.BulkUpdateUsersRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	result, err := h.service.BulkUpdateUsers(ctx, request)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteBulkResponse(ctx, result)
+}
+
+func (h *UserHandler) BulkDeleteUsers(ctx *gin.Context) {
+	var request dto.BulkDeleteUsersRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	result, err := h.service.BulkDeleteUsers(ctx, request)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteBulkResponse(ctx, result)
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Alfian57/belajar-golang/internal/config"
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/response"
+	"github.com/Alfian57/belajar-golang/internal/service"
+	"github.com/Alfian57/belajar-golang/internal/utils/auth"
+	"github.com/gin-gonic/gin"
+)
+
+type UserImportHandler struct {
+	service *service.UserImportService
+}
+
+func NewUserImportHandler(s *service.UserImportService) *UserImportHandler {
+	return &UserImportHandler{
+		service: s,
+	}
+}
+
+// Import answers 200 with the finished job of small files and 202 with the
+// pending job of files imported in the background.
+func (h *UserImportHandler) Import(ctx *gin.Context) {
+	// Stop reading uploads past the limit, with room for the other fields
+	maxSize := config.Current().Import.MaxFileSize + 1<<20
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxSize)
+
+	var request dto.ImportUsersRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			err = service.ImportFileTooLarge()
+		}
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	// Invites sign in through the magic link endpoint of the same API version
+	apiPrefix, _, _ := strings.Cut(ctx.FullPath(), "/admin/")
+	signInURL := publicURL(apiPrefix + "/magic-link/verify")
+
+	job, err := h.service.Import(ctx, request, user, signInURL, response.Describe)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	status := http.StatusOK
+	if !job.Finished() {
+		status = http.StatusAccepted
+	}
+	response.WriteDataResponse(ctx, status, job)
+}
+
+func (h *UserImportHandler) GetJob(ctx *gin.Context) {
+	id, err := uuidParam(ctx, "id")
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	job, err := h.service.GetJob(ctx, id)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, job)
+}
@@ -0,0 +1,203 @@
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+// Source is the language messages are written in. Code carries English
+// messages, the English bundle only fills in the ones code does not write,
+// such as validation messages.
+const Source = "en"
+
+//go:embed locales
+var files embed.FS
+
+var (
+	// bundles holds the flattened messages of every locale, e.g.
+	// bundles["id"]["validation.required"].
+	bundles map[string]map[string]string
+	// locales lists the bundle names with Source first, matching tags.
+	locales []string
+	matcher language.Matcher
+)
+
+func init() {
+	loaded, err := load(files, "locales")
+	if err != nil {
+		panic(err)
+	}
+	if _, ok := loaded[Source]; !ok {
+		panic("i18n: no bundle for the source language " + Source)
+	}
+
+	bundles = loaded
+	locales = append(locales, Source)
+	for locale := range loaded {
+		if locale != Source {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales[1:])
+
+	tags := make([]language.Tag, len(locales))
+	for i, locale := range locales {
+		tags[i] = language.Make(locale)
+	}
+	matcher = language.NewMatcher(tags)
+}
+
+// load reads the bundles of dir, one YAML or JSON file per locale named after
+// it, e.g. id.yaml.
+func load(fsys fs.FS, dir string) (map[string]map[string]string, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := make(map[string]map[string]string)
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := path.Ext(name)
+		locale := strings.TrimSuffix(name, ext)
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		var tree map[string]any
+		switch ext {
+		case ".yaml", ".yml":
+			err = yaml.Unmarshal(data, &tree)
+		case ".json":
+			err = json.Unmarshal(data, &tree)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("i18n: parsing %s: %w", name, err)
+		}
+
+		messages := make(map[string]string)
+		if err := flatten("", tree, messages); err != nil {
+			return nil, fmt.Errorf("i18n: %s: %w", name, err)
+		}
+		loaded[locale] = messages
+	}
+
+	return loaded, nil
+}
+
+// flatten turns nested keys into dotted ones.
+func flatten(prefix string, tree map[string]any, messages map[string]string) error {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch value := value.(type) {
+		case string:
+			messages[key] = value
+		case map[string]any:
+			if err := flatten(key, value, messages); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%s is a %T, expected a message or a group", key, value)
+		}
+	}
+
+	return nil
+}
+
+// Locales returns the supported locales, Source first.
+func Locales() []string {
+	return append([]string(nil), locales...)
+}
+
+// Supported reports whether there is a bundle for locale.
+func Supported(locale string) bool {
+	_, ok := bundles[locale]
+	return ok
+}
+
+// Match picks the supported locale closest to an Accept-Language header, so
+// id-ID gets id. Without a match it returns fallback.
+func Match(acceptLanguage, fallback string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return fallback
+	}
+
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return fallback
+	}
+
+	return locales[index]
+}
+
+type contextKey struct{}
+
+// WithLocale stores the locale of a request in ctx.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// FromContext returns the locale of ctx, Source when it has none.
+func FromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(contextKey{}).(string); ok {
+		return locale
+	}
+	return Source
+}
+
+// Defined reports whether the source bundle has a message for key. The
+// source bundle is complete, other locales may miss keys.
+func Defined(key string) bool {
+	_, ok := bundles[Source][key]
+	return ok
+}
+
+// T returns the message of key in the locale of ctx with {name} params
+// interpolated. Messages fall back from the locale to message, which is in
+// the source language, then to the source bundle. Keys without any message
+// come back unchanged.
+func T(ctx context.Context, key, message string, params map[string]string) string {
+	locale := FromContext(ctx)
+	if translated, ok := bundles[locale][key]; ok && locale != Source {
+		return interpolate(translated, params)
+	}
+
+	if message != "" {
+		return message
+	}
+	if translated, ok := bundles[Source][key]; ok {
+		return interpolate(translated, params)
+	}
+
+	return key
+}
+
+func interpolate(message string, params map[string]string) string {
+	if len(params) == 0 {
+		return message
+	}
+
+	pairs := make([]string, 0, len(params)*2)
+	for name, value := range params {
+		pairs = append(pairs, "{"+name+"}", value)
+	}
+
+	return strings.NewReplacer(pairs...).Replace(message)
+}
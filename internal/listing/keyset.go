@@ -0,0 +1,105 @@
+package listing
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrKeyset is returned for keyset values that do not fit the sort of a
+// query.
+var ErrKeyset = errors.New("listing: keyset does not match the sort")
+
+// Keyset positions a query next to a row by its values of the sorted fields
+// followed by its key.
+type Keyset struct {
+	Values []any
+	// Backward seeks towards the start of the order, for previous pages.
+	Backward bool
+}
+
+// Position returns the values of item for the sort of the query followed
+// by its key, in the text form Keyset takes. Fields are read by name from
+// the JSON encoding of item.
+func (q Query) Position(item any) ([]string, error) {
+	fields, err := jsonFields(item)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := q.orders()
+	values := make([]string, len(orders))
+	for i, order := range orders {
+		value, ok := fields[order.Field.Name]
+		if !ok {
+			return nil, fmt.Errorf("listing: %T has no field %s", item, order.Field.Name)
+		}
+		if text, ok := value.(string); ok {
+			values[i] = text
+		} else {
+			values[i] = fmt.Sprint(value)
+		}
+	}
+
+	return values, nil
+}
+
+// Keyset parses values returned by Position for the sort of the query.
+func (q Query) Keyset(values []string, backward bool) (Keyset, error) {
+	orders := q.orders()
+	if len(values) != len(orders) {
+		return Keyset{}, ErrKeyset
+	}
+
+	keyset := Keyset{Values: make([]any, len(values)), Backward: backward}
+	for i, order := range orders {
+		value, err := order.Field.Parse(values[i])
+		if err != nil {
+			return Keyset{}, ErrKeyset
+		}
+		keyset.Values[i] = value
+	}
+
+	return keyset, nil
+}
+
+// Select keeps the fields, a list like id,email, of every item, read from
+// their JSON encoding. Parse checks the list.
+func Select[T any](items []T, fields string) ([]map[string]any, error) {
+	names := strings.Split(fields, ",")
+
+	selected := make([]map[string]any, len(items))
+	for i, item := range items {
+		all, err := jsonFields(item)
+		if err != nil {
+			return nil, err
+		}
+
+		selected[i] = make(map[string]any, len(names))
+		for _, name := range names {
+			if value, ok := all[name]; ok {
+				selected[i][name] = value
+			}
+		}
+	}
+
+	return selected, nil
+}
+
+func jsonFields(item any) (map[string]any, error) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var fields map[string]any
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
@@ -0,0 +1,314 @@
+// Package listing parses the filter, sort and fields query parameters of list
+// endpoints against a whitelist of fields, and applies them as GORM scopes:
+//
+//	filter[role]=member&filter[email][suffix]=@acme.com
+//	filter[created_at][gte]=2024-01-01
+//	sort=-created_at,username
+//	fields=id,email
+//
+// Columns only come from the whitelist and values are bound as parameters,
+// so no part of a request ends up in the SQL text.
+package listing
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/google/uuid"
+)
+
+// Kind is the type of the values of a field.
+type Kind int
+
+const (
+	String Kind = iota
+	Number
+	Bool
+	Time
+	UUID
+)
+
+// Operator compares a field with the value of a filter.
+type Operator string
+
+const (
+	Eq       Operator = "eq"
+	Ne       Operator = "ne"
+	Gt       Operator = "gt"
+	Gte      Operator = "gte"
+	Lt       Operator = "lt"
+	Lte      Operator = "lte"
+	In       Operator = "in"
+	Contains Operator = "contains"
+	Prefix   Operator = "prefix"
+	Suffix   Operator = "suffix"
+)
+
+// Common operator sets of fields.
+var (
+	Equality = []Operator{Eq, Ne, In}
+	Range    = []Operator{Eq, Ne, Gt, Gte, Lt, Lte}
+	Text     = []Operator{Eq, Ne, In, Contains, Prefix, Suffix}
+)
+
+// MaxValues limits the comma separated values of an in filter.
+const MaxValues = 100
+
+// Field is a field of a resource that may be filtered, sorted or selected.
+type Field struct {
+	// Name is the name in query parameters and responses, the JSON name.
+	Name   string
+	Column string
+	Kind   Kind
+	// Operators lists the filters allowed on the field, none for fields
+	// that cannot be filtered.
+	Operators []Operator
+	// Sortable fields must be NOT NULL, keyset pagination compares them.
+	Sortable bool
+}
+
+// Spec declares the fields of a resource. Every field can be selected.
+type Spec struct {
+	Fields []Field
+	// Key names the unique field breaking ties between sorted rows.
+	Key string
+	// DefaultSort is the sort of requests without one.
+	DefaultSort string
+}
+
+// Filter holds filter parameters by field and operator, e.g.
+// filter[created_at][gte]=2024-01-01 is Filter{"created_at": {"gte": ...}}.
+// filter[role]=member is short for filter[role][eq]=member.
+type Filter map[string]map[string]string
+
+// Condition is a parsed filter. Value holds a slice for in filters.
+type Condition struct {
+	Field    Field
+	Operator Operator
+	Value    any
+}
+
+// Order sorts by a field.
+type Order struct {
+	Field Field
+	Desc  bool
+}
+
+// Query is a parsed listing request.
+type Query struct {
+	Conditions []Condition
+	Sort       []Order
+	key        Field
+}
+
+// ParseFilter collects the filter[...] parameters of a query string.
+func ParseFilter(values url.Values) (Filter, error) {
+	filter := Filter{}
+
+	var fieldErrors []errs.FieldError
+	for param, value := range values {
+		rest, ok := strings.CutPrefix(param, "filter[")
+		if !ok {
+			continue
+		}
+
+		name, rest, ok := strings.Cut(rest, "]")
+		operator := string(Eq)
+		if ok && rest != "" {
+			inner, opened := strings.CutPrefix(rest, "[")
+			inner, closed := strings.CutSuffix(inner, "]")
+			operator = inner
+			ok = opened && closed && inner != "" && !strings.ContainsAny(inner, "[]")
+		}
+		if !ok || name == "" {
+			fieldErrors = append(fieldErrors, errs.NewFieldError(param, errs.FieldInvalid, "filter parameters look like filter[field] or filter[field][operator]"))
+			continue
+		}
+
+		if filter[name] == nil {
+			filter[name] = map[string]string{}
+		}
+		filter[name][operator] = value[len(value)-1]
+	}
+
+	if fieldErrors != nil {
+		return nil, errs.NewValidationError(fieldErrors)
+	}
+	return filter, nil
+}
+
+// Parse checks filter, a sort like -created_at,username and a fields list
+// like id,email against the spec, see Select for the fields. Unknown fields and operators and invalid
+// values are reported together in a validation error.
+func (s Spec) Parse(filter Filter, sort, fields string) (Query, error) {
+	query := Query{}
+
+	var fieldErrors []errs.FieldError
+	// Filters come from maps, sorting keeps errors and the SQL stable
+	for _, name := range slices.Sorted(maps.Keys(filter)) {
+		field, ok := s.field(name)
+		for _, operator := range slices.Sorted(maps.Keys(filter[name])) {
+			raw := filter[name][operator]
+			param := fmt.Sprintf("filter[%s][%s]", name, operator)
+			if !ok || len(field.Operators) == 0 {
+				fieldErrors = append(fieldErrors, errs.NewFieldError(param, errs.FieldUnknown, "unknown filter field "+name).WithParam(name))
+				continue
+			}
+
+			condition, err := field.condition(Operator(operator), raw)
+			if err != nil {
+				fieldErrors = append(fieldErrors, *err)
+				continue
+			}
+			query.Conditions = append(query.Conditions, condition)
+		}
+	}
+
+	if sort == "" {
+		sort = s.DefaultSort
+	}
+	order, err := s.parseSort(sort)
+	if err != nil {
+		fieldErrors = append(fieldErrors, *err)
+	}
+	query.Sort = order
+
+	if fields != "" {
+		for _, name := range strings.Split(fields, ",") {
+			if _, ok := s.field(name); !ok {
+				fieldErrors = append(fieldErrors, errs.NewFieldError("fields", errs.FieldUnknown, "unknown field "+name).WithParam(name))
+			}
+		}
+	}
+
+	if fieldErrors != nil {
+		return Query{}, errs.NewValidationError(fieldErrors)
+	}
+
+	query.key, _ = s.field(s.Key)
+	return query, nil
+}
+
+// parseSort parses a sort like -created_at,username, where a leading minus
+// sorts in descending order.
+func (s Spec) parseSort(sort string) ([]Order, *errs.FieldError) {
+	var order []Order
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(sort, ",") {
+		desc := strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+
+		field, ok := s.field(name)
+		if !ok || !field.Sortable || seen[name] {
+			fieldError := errs.NewFieldError("sort", errs.FieldUnknown, "unknown sort field "+name).WithParam(name)
+			return nil, &fieldError
+		}
+		seen[name] = true
+		order = append(order, Order{Field: field, Desc: desc})
+	}
+
+	return order, nil
+}
+
+func (s Spec) field(name string) (Field, bool) {
+	for _, field := range s.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
+
+// Sorting returns the sort of the query in the form Parse takes.
+func (q Query) Sorting() string {
+	names := make([]string, len(q.Sort))
+	for i, order := range q.Sort {
+		names[i] = order.Field.Name
+		if order.Desc {
+			names[i] = "-" + names[i]
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+func (f Field) condition(operator Operator, raw string) (Condition, *errs.FieldError) {
+	param := fmt.Sprintf("filter[%s][%s]", f.Name, operator)
+
+	if !slices.Contains(f.Operators, operator) {
+		fieldError := errs.NewFieldError(param, errs.FieldUnknown, "unknown operator "+string(operator)).WithParam(string(operator))
+		return Condition{}, &fieldError
+	}
+
+	if operator == In {
+		raws := strings.Split(raw, ",")
+		if len(raws) > MaxValues {
+			fieldError := errs.NewFieldError(param, errs.FieldInvalid, fmt.Sprintf("at most %d values are allowed", MaxValues))
+			return Condition{}, &fieldError
+		}
+
+		values := make([]any, len(raws))
+		for i, raw := range raws {
+			value, err := f.Parse(raw)
+			if err != nil {
+				fieldError := errs.NewFieldError(param, errs.FieldInvalid, err.Error())
+				return Condition{}, &fieldError
+			}
+			values[i] = value
+		}
+		return Condition{Field: f, Operator: operator, Value: values}, nil
+	}
+
+	// Pattern operators match text, whatever the kind
+	if operator == Contains || operator == Prefix || operator == Suffix {
+		return Condition{Field: f, Operator: operator, Value: raw}, nil
+	}
+
+	value, err := f.Parse(raw)
+	if err != nil {
+		fieldError := errs.NewFieldError(param, errs.FieldInvalid, err.Error())
+		return Condition{}, &fieldError
+	}
+	return Condition{Field: f, Operator: operator, Value: value}, nil
+}
+
+// Parse converts a value of the field from its text form. Times are
+// RFC 3339 timestamps or dates, which mean midnight UTC.
+func (f Field) Parse(raw string) (any, error) {
+	switch f.Kind {
+	case Number:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number", f.Name)
+		}
+		return value, nil
+	case Bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false", f.Name)
+		}
+		return value, nil
+	case Time:
+		if value, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+			return value, nil
+		}
+		value, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a date or an RFC 3339 timestamp", f.Name)
+		}
+		return value, nil
+	case UUID:
+		value, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a UUID", f.Name)
+		}
+		return value, nil
+	default:
+		return raw, nil
+	}
+}
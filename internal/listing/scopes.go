@@ -0,0 +1,105 @@
+package listing
+
+import (
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike escapes the wildcards of a LIKE pattern, so value matches
+// literally.
+func EscapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+// Where applies the conditions of the query.
+func (q Query) Where(db *gorm.DB) *gorm.DB {
+	for _, condition := range q.Conditions {
+		db = condition.apply(db)
+	}
+	return db
+}
+
+func (c Condition) apply(db *gorm.DB) *gorm.DB {
+	column := c.Field.Column
+
+	switch c.Operator {
+	case Ne:
+		return db.Where(column+" <> ?", c.Value)
+	case Gt:
+		return db.Where(column+" > ?", c.Value)
+	case Gte:
+		return db.Where(column+" >= ?", c.Value)
+	case Lt:
+		return db.Where(column+" < ?", c.Value)
+	case Lte:
+		return db.Where(column+" <= ?", c.Value)
+	case In:
+		return db.Where(column+" IN ?", c.Value)
+	case Contains:
+		return db.Where(column+" ILIKE ?", "%"+EscapeLike(c.Value.(string))+"%")
+	case Prefix:
+		return db.Where(column+" ILIKE ?", EscapeLike(c.Value.(string))+"%")
+	case Suffix:
+		return db.Where(column+" ILIKE ?", "%"+EscapeLike(c.Value.(string)))
+	default:
+		return db.Where(column+" = ?", c.Value)
+	}
+}
+
+// Order sorts by the sort of the query, then by the key.
+func (q Query) Order(db *gorm.DB) *gorm.DB {
+	return q.order(db, false)
+}
+
+func (q Query) order(db *gorm.DB, reverse bool) *gorm.DB {
+	for _, order := range q.orders() {
+		direction := "ASC"
+		if order.Desc != reverse {
+			direction = "DESC"
+		}
+		db = db.Order(order.Field.Column + " " + direction)
+	}
+	return db
+}
+
+// orders returns the sort followed by the key, which follows the direction
+// of the last field.
+func (q Query) orders() []Order {
+	desc := len(q.Sort) > 0 && q.Sort[len(q.Sort)-1].Desc
+	return append(slices.Clone(q.Sort), Order{Field: q.key, Desc: desc})
+}
+
+// Seek selects the rows after the keyset in the order of the query, or
+// before it when it is backward, and orders them away from it. Rows before
+// a keyset come back in reverse order.
+func (q Query) Seek(keyset Keyset) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		// (a > ?) OR (a = ? AND b > ?) OR ..., row comparisons cannot mix
+		// directions
+		orders := q.orders()
+		clauses := make([]string, len(orders))
+		var args []any
+		for i, order := range orders {
+			parts := make([]string, 0, i+1)
+			for j := range i {
+				parts = append(parts, orders[j].Field.Column+" = ?")
+				args = append(args, keyset.Values[j])
+			}
+
+			comparison := " > ?"
+			if order.Desc != keyset.Backward {
+				comparison = " < ?"
+			}
+			parts = append(parts, order.Field.Column+comparison)
+			args = append(args, keyset.Values[i])
+
+			clauses[i] = "(" + strings.Join(parts, " AND ") + ")"
+		}
+
+		return q.order(db.Where(strings.Join(clauses, " OR "), args...), keyset.Backward)
+	}
+}
@@ -0,0 +1,165 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/Alfian57/belajar-golang/internal/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Components with a level of their own. Log through one with Named, e.g.
+// FromContext(ctx).Named(ComponentAuth).
+const (
+	ComponentDB   = "db"
+	ComponentHTTP = "http"
+	ComponentAuth = "auth"
+)
+
+// level applies to lines outside of a component, and to components without
+// a level of their own.
+var level = zap.NewAtomicLevelAt(zap.InfoLevel)
+
+type componentLevel struct {
+	level zap.AtomicLevel
+	// inherit is set while the component follows level
+	inherit atomic.Bool
+}
+
+var components = map[string]*componentLevel{
+	ComponentDB:   newComponentLevel(),
+	ComponentHTTP: newComponentLevel(),
+	ComponentAuth: newComponentLevel(),
+}
+
+func newComponentLevel() *componentLevel {
+	component := &componentLevel{level: zap.NewAtomicLevel()}
+	component.inherit.Store(true)
+	return component
+}
+
+// Levels is the current root level and the level of each component. An empty
+// component level means it follows the root level.
+type Levels struct {
+	Level      string            `json:"level"`
+	Components map[string]string `json:"components"`
+}
+
+// CurrentLevels returns the levels in effect.
+func CurrentLevels() Levels {
+	levels := Levels{
+		Level:      level.Level().String(),
+		Components: make(map[string]string, len(components)),
+	}
+
+	for name, component := range components {
+		levels.Components[name] = ""
+		if !component.inherit.Load() {
+			levels.Components[name] = component.level.Level().String()
+		}
+	}
+
+	return levels
+}
+
+// SetLevel changes the minimum level logged, e.g. "debug" or "warn".
+func SetLevel(name string) error {
+	parsed, err := zapcore.ParseLevel(name)
+	if err != nil {
+		return err
+	}
+
+	level.SetLevel(parsed)
+
+	return nil
+}
+
+// SetComponentLevel changes the level of one component. An empty name makes
+// it follow the root level again.
+func SetComponentLevel(component, name string) error {
+	target, ok := components[component]
+	if !ok {
+		return fmt.Errorf("unknown log component %q", component)
+	}
+
+	if name == "" {
+		target.inherit.Store(true)
+		return nil
+	}
+
+	parsed, err := zapcore.ParseLevel(name)
+	if err != nil {
+		return err
+	}
+
+	target.level.SetLevel(parsed)
+	target.inherit.Store(false)
+
+	return nil
+}
+
+// ApplyLevels sets the root and component levels from the LOG_* settings.
+func ApplyLevels(cfg config.LogConfig) error {
+	if err := SetLevel(cfg.Level); err != nil {
+		return err
+	}
+
+	for component, name := range map[string]string{
+		ComponentDB:   cfg.DBLevel,
+		ComponentHTTP: cfg.HTTPLevel,
+		ComponentAuth: cfg.AuthLevel,
+	} {
+		if err := SetComponentLevel(component, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// enabled reports whether a line at lvl from the logger named name is
+// written. Names of child loggers, such as "auth.jwt", use the level of
+// their first segment.
+func enabled(name string, lvl zapcore.Level) bool {
+	component, _, _ := strings.Cut(name, ".")
+	if target, ok := components[component]; ok && !target.inherit.Load() {
+		return target.level.Enabled(lvl)
+	}
+
+	return level.Enabled(lvl)
+}
+
+// levelCore filters entries by the level of the component that wrote them.
+type levelCore struct {
+	zapcore.Core
+}
+
+// Enabled is asked before the logger name is known, so it lets through any
+// level a component could write.
+func (c levelCore) Enabled(lvl zapcore.Level) bool {
+	if level.Enabled(lvl) {
+		return true
+	}
+
+	for _, component := range components {
+		if !component.inherit.Load() && component.level.Enabled(lvl) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return levelCore{Core: c.Core.With(fields)}
+}
+
+func (c levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !enabled(entry.LoggerName, entry.Level) {
+		return checked
+	}
+
+	return c.Core.Check(entry, checked)
+}
@@ -1,26 +1,131 @@
 package logger
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/config"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 var Log *zap.SugaredLogger
 
-func Init() {
-	config := zap.Config{
-		Level:            zap.NewAtomicLevelAt(zap.InfoLevel),
-		Development:      false,
-		Encoding:         "json",
-		EncoderConfig:    zap.NewDevelopmentEncoderConfig(),
-		OutputPaths:      []string{"stdout", "./logs/app.log"},
-		ErrorOutputPaths: []string{"stderr"},
+type contextKey struct{}
+
+// Init builds Log from the LOG_* settings. Lines go to stdout and, unless
+// LOG_FILE is empty, to a file rotated by size and every LOG_ROTATE_INTERVAL.
+func Init(cfg config.LogConfig) {
+	outputs := []zapcore.WriteSyncer{zapcore.Lock(os.Stdout)}
+
+	if cfg.File != "" {
+		if err := os.MkdirAll(filepath.Dir(cfg.File), 0755); err != nil {
+			panic(err)
+		}
+
+		file := &lumberjack.Logger{
+			Filename:   cfg.File,
+			MaxSize:    cfg.MaxSizeMB,
+			MaxAge:     cfg.MaxAgeDays,
+			MaxBackups: cfg.MaxBackups,
+			Compress:   cfg.Compress,
+		}
+		outputs = append(outputs, zapcore.AddSync(file))
+
+		if cfg.RotateInterval > 0 {
+			go rotateEvery(file, cfg.RotateInterval)
+		}
 	}
 
-	logger, err := config.Build()
-	if err != nil {
+	// The core accepts every level, levelCore filters by component
+	core := zapcore.NewCore(newEncoder(cfg.Format), zapcore.NewMultiWriteSyncer(outputs...), zapcore.DebugLevel)
+	core = levelCore{Core: newRedactingCore(core)}
+
+	if err := ApplyLevels(cfg); err != nil {
 		panic(err)
 	}
-	defer logger.Sync()
+
+	logger := zap.New(core,
+		zap.AddCaller(),
+		zap.AddStacktrace(zapcore.ErrorLevel),
+		zap.ErrorOutput(zapcore.Lock(os.Stderr)),
+	)
 
 	Log = logger.Sugar()
 }
+
+// newEncoder returns a JSON encoder, or a colored console encoder for
+// development. Without LOG_FORMAT the format follows GIN_MODE.
+func newEncoder(format string) zapcore.Encoder {
+	if format == "" {
+		format = "console"
+		if os.Getenv("GIN_MODE") == "release" {
+			format = "json"
+		}
+	}
+
+	if format == "console" {
+		encoderConfig := zap.NewDevelopmentEncoderConfig()
+		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		return zapcore.NewConsoleEncoder(encoderConfig)
+	}
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	encoderConfig.EncodeDuration = zapcore.StringDurationEncoder
+	return zapcore.NewJSONEncoder(encoderConfig)
+}
+
+// rotateEvery starts a new file every interval, on top of the size based
+// rotation of lumberjack.
+func rotateEvery(file *lumberjack.Logger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := file.Rotate(); err != nil && Log != nil {
+			Log.Errorw("failed to rotate log file", "file", file.Filename, "error", err)
+		}
+	}
+}
+
+// WithContext returns a copy of ctx carrying log. FromContext returns it
+// for the rest of the request.
+func WithContext(ctx context.Context, log *zap.SugaredLogger) context.Context {
+	return context.WithValue(ctx, contextKey{}, log)
+}
+
+// With adds fields, such as the user ID once the request is authenticated,
+// to the logger carried by ctx.
+func With(ctx context.Context, keysAndValues ...any) context.Context {
+	return WithContext(ctx, requestLogger(ctx).With(keysAndValues...))
+}
+
+// FromContext returns the logger for the request in ctx, falling back to Log
+// outside of a request. Lines carry the trace and span IDs of the current
+// span so they can be matched to traces.
+func FromContext(ctx context.Context) *zap.SugaredLogger {
+	log := requestLogger(ctx)
+
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return log
+	}
+
+	return log.With(
+		"trace_id", spanContext.TraceID().String(),
+		"span_id", spanContext.SpanID().String(),
+	)
+}
+
+func requestLogger(ctx context.Context) *zap.SugaredLogger {
+	if log, ok := ctx.Value(contextKey{}).(*zap.SugaredLogger); ok {
+		return log
+	}
+
+	return Log
+}
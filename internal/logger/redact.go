@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const Redacted = "[REDACTED]"
+
+// sensitiveMarkers match field names whose values must never reach the logs,
+// such as "password", "new_password" or "refresh_token".
+var sensitiveMarkers = []string{"password", "secret", "token", "authorization", "cookie", "api_key"}
+
+// IsSensitive reports whether a field, header or parameter named key holds a
+// secret.
+func IsSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, marker := range sensitiveMarkers {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactingCore replaces the values of sensitive fields before they are
+// encoded, so a careless Infow("...", "password", p) cannot leak p.
+type redactingCore struct {
+	zapcore.Core
+}
+
+func newRedactingCore(core zapcore.Core) zapcore.Core {
+	return redactingCore{Core: core}
+}
+
+func (c redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return redactingCore{Core: c.Core.With(redactFields(fields))}
+}
+
+func (c redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+
+	return checked
+}
+
+func (c redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, redactFields(fields))
+}
+
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	redacted := fields
+	copied := false
+	for i, field := range fields {
+		if !IsSensitive(field.Key) {
+			continue
+		}
+
+		// Copy before the first change, the caller owns fields
+		if !copied {
+			redacted = append([]zapcore.Field(nil), fields...)
+			copied = true
+		}
+		redacted[i] = zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: Redacted}
+	}
+
+	return redacted
+}
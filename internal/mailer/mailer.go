@@ -0,0 +1,103 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/Alfian57/belajar-golang/internal/config"
+	"github.com/Alfian57/belajar-golang/internal/health"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+var Default Mailer = LogMailer{}
+
+// Init configures the default mailer. Without a mail host, messages are only
+// written to the log, which is convenient for local development.
+func Init(cfg config.MailConfig) {
+	if cfg.Host == "" {
+		Default = LogMailer{}
+		health.Unregister("mailer")
+		return
+	}
+
+	smtpMailer := &SMTPMailer{config: cfg}
+	Default = smtpMailer
+	health.Register("mailer", smtpMailer.Ping)
+}
+
+// Send delivers a message through the default mailer.
+func Send(ctx context.Context, message Message) error {
+	return Default.Send(ctx, message)
+}
+
+type SMTPMailer struct {
+	config config.MailConfig
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, message Message) error {
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, m.config.From, []string{message.To}, m.build(message))
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Ping checks that the SMTP server accepts connections
+func (m *SMTPMailer) Ping(ctx context.Context) error {
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
+
+func (m *SMTPMailer) build(message Message) []byte {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "From: %s\r\n", m.config.From)
+	fmt.Fprintf(&sb, "To: %s\r\n", message.To)
+	fmt.Fprintf(&sb, "Subject: %s\r\n", message.Subject)
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n")
+	sb.WriteString("\r\n")
+	sb.WriteString(message.Body)
+	return []byte(sb.String())
+}
+
+// LogMailer writes messages to the application log instead of sending them.
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, message Message) error {
+	logger.FromContext(ctx).Infow("mail not sent, no mail host configured", "to", message.To, "subject", message.Subject, "body", message.Body)
+	return nil
+}
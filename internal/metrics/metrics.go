@@ -0,0 +1,135 @@
+// Package metrics defines the Prometheus collectors of the API and the
+// registry served at /metrics.
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "belajar_golang"
+
+// Results used by the business counters
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+	ResultError   = "error"
+)
+
+// Login methods
+const (
+	LoginMethodPassword  = "password"
+	LoginMethodMagicLink = "magic_link"
+)
+
+// Registry holds every collector of the API. It is separate from the global
+// default registry so libraries cannot add metrics behind our back.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route template, method and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route template, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "GORM statement latency by operation and table.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	DBQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_query_errors_total",
+		Help:      "GORM statements that failed, by operation and table.",
+	}, []string{"operation", "table"})
+
+	Logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_logins_total",
+		Help:      "Login attempts by method and result.",
+	}, []string{"method", "result"})
+
+	Registrations = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_registrations_total",
+		Help:      "Users registered through the public endpoint.",
+	})
+
+	TokenRefreshes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_token_refreshes_total",
+		Help:      "Refresh token rotations by result.",
+	}, []string{"result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		HTTPRequestsInFlight,
+		DBQueryDuration,
+		DBQueryErrors,
+		Logins,
+		Registrations,
+		TokenRefreshes,
+	)
+}
+
+// Handler serves the registry in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// RegisterDBStats exposes the connection pool statistics of db.
+func RegisterDBStats(db *sql.DB, name string) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// ObserveLogin counts a login attempt from the error it returned.
+func ObserveLogin(method string, err error) {
+	Logins.WithLabelValues(method, result(err)).Inc()
+}
+
+// ObserveRefresh counts a refresh token rotation from the error it returned.
+func ObserveRefresh(err error) {
+	TokenRefreshes.WithLabelValues(result(err)).Inc()
+}
+
+// result separates rejected credentials from server side errors, which
+// call for different alerts.
+func result(err error) string {
+	if err == nil {
+		return ResultSuccess
+	}
+
+	var appErr *errs.AppError
+	if errors.As(err, &appErr) && appErr.Code >= http.StatusInternalServerError {
+		return ResultError
+	}
+
+	return ResultFailure
+}
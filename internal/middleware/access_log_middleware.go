@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLogMiddleware writes one structured line per request through the
+// request logger, which carries the request ID, route and, once
+// authenticated, the user ID. Path and query values of
+// sensitive parameters, such as invitation and magic link tokens, are
+// redacted.
+func AccessLogMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		fields := []any{
+			"method", ctx.Request.Method,
+			"path", redactedPath(ctx),
+			"status", status,
+			"latency", time.Since(start),
+			"bytes", ctx.Writer.Size(),
+			"client_ip", ctx.ClientIP(),
+			"user_agent", ctx.Request.UserAgent(),
+		}
+
+		if query := redactedQuery(ctx.Request.URL.Query()); query != "" {
+			fields = append(fields, "query", query)
+		}
+		if len(ctx.Errors) > 0 {
+			fields = append(fields, "errors", ctx.Errors.String())
+		}
+
+		log := logger.FromContext(ctx).Named(logger.ComponentHTTP)
+		switch {
+		case status >= http.StatusInternalServerError:
+			log.Errorw("request", fields...)
+		case status >= http.StatusBadRequest:
+			log.Warnw("request", fields...)
+		default:
+			log.Infow("request", fields...)
+		}
+	}
+}
+
+func redactedPath(ctx *gin.Context) string {
+	segments := strings.Split(ctx.Request.URL.Path, "/")
+	for _, param := range ctx.Params {
+		if !logger.IsSensitive(param.Key) {
+			continue
+		}
+
+		for i, segment := range segments {
+			if segment == param.Value {
+				segments[i] = logger.Redacted
+			}
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func redactedQuery(query url.Values) string {
+	for key, values := range query {
+		if !logger.IsSensitive(key) {
+			continue
+		}
+
+		for i := range values {
+			values[i] = logger.Redacted
+		}
+	}
+
+	return query.Encode()
+}
@@ -1,7 +1,9 @@
 package middleware
 
 import (
+	"github.com/Alfian57/belajar-golang/internal/di"
 	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/logger"
 	"github.com/Alfian57/belajar-golang/internal/model"
 	"github.com/Alfian57/belajar-golang/internal/response"
 	"github.com/gin-gonic/gin"
@@ -11,7 +13,7 @@ func AdminMiddleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		user := ctx.MustGet("user").(model.User)
 
-		if user.Role != model.UserRoleAdmin {
+		if !isAdmin(ctx, user) {
 			response.WriteErrorResponse(ctx, errs.ErrForbidden)
 			ctx.Abort()
 			return
@@ -20,3 +22,29 @@ func AdminMiddleware() gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// isAdmin reports whether the current user is a global admin. The result is
+// cached on the request, so it must only be called for the effective user.
+func isAdmin(ctx *gin.Context, user model.User) bool {
+	if cached, exists := ctx.Get("is_admin"); exists {
+		return cached.(bool)
+	}
+
+	ok := hasAdminRole(ctx, user)
+	ctx.Set("is_admin", ok)
+	return ok
+}
+
+// hasAdminRole reports whether the user is a global admin, either by role or
+// through a global group granting the admin role.
+func hasAdminRole(ctx *gin.Context, user model.User) bool {
+	groupService := di.InitializeGroupService()
+
+	ok, err := groupService.HasGlobalRole(ctx, user, model.UserRoleAdmin)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to check admin role", "user_id", user.ID, "error", err)
+		return false
+	}
+
+	return ok
+}
@@ -1,8 +1,12 @@
 package middleware
 
 import (
+	"strings"
+
 	"github.com/Alfian57/belajar-golang/internal/di"
 	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/model"
 	"github.com/Alfian57/belajar-golang/internal/response"
 	"github.com/Alfian57/belajar-golang/internal/utils/jwt"
 	"github.com/gin-gonic/gin"
@@ -12,36 +16,99 @@ func AuthMiddleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authService := di.InitializeUserService()
 
-		accessToken, err := ctx.Cookie("access_token")
-		if err != nil {
+		accessToken, ok := accessTokenFromRequest(ctx)
+		if !ok {
 			response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
 			ctx.Abort()
 			return
 		}
 
-		userID, err := jwt.ValidateAccessToken(accessToken)
+		claims, err := jwt.ParseAccessToken(accessToken)
 		if err != nil {
 			response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
 			ctx.Abort()
 			return
 		}
 
-		if userID == "" {
+		if claims.UserID == "" {
 			response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
 			ctx.Abort()
 			return
 		}
 
-		user, err := authService.GetUserByID(ctx, userID)
+		user, err := authService.GetUserByID(ctx, claims.UserID)
 		if err != nil {
 			response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
 			ctx.Abort()
 			return
 		}
 
+		ctx.Request = ctx.Request.WithContext(logger.With(ctx.Request.Context(), "user_id", user.ID))
+
+		if claims.IsImpersonation() {
+			actor, err := impersonationActor(ctx, claims)
+			if err != nil {
+				response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+				ctx.Abort()
+				return
+			}
+
+			ctx.Request = ctx.Request.WithContext(logger.With(ctx.Request.Context(), "actor_id", actor.ID))
+			ctx.Set("actor", actor)
+			ctx.Set("impersonation_session_id", claims.SessionID)
+
+			// Let clients show a banner while acting as someone else
+			ctx.Header("X-Impersonated-By", actor.ID.String())
+
+			logger.FromContext(ctx).Named(logger.ComponentAuth).Infow("impersonated request", "session_id", claims.SessionID)
+		}
+
 		ctx.Set("access_token", accessToken)
 		ctx.Set("user", user)
 
 		ctx.Next()
 	}
 }
+
+// accessTokenFromRequest reads the access token from an "Authorization: Bearer"
+// header, falling back to the access_token cookie.
+func accessTokenFromRequest(ctx *gin.Context) (string, bool) {
+	scheme, accessToken, found := strings.Cut(ctx.GetHeader("Authorization"), " ")
+	if found && strings.EqualFold(scheme, "Bearer") && accessToken != "" {
+		return accessToken, true
+	}
+
+	accessToken, err := ctx.Cookie("access_token")
+	if err != nil || accessToken == "" {
+		return "", false
+	}
+
+	return accessToken, true
+}
+
+// impersonationActor checks that the impersonation session is still active and
+// that the actor behind it is still an admin.
+func impersonationActor(ctx *gin.Context, claims jwt.AccessClaims) (model.User, error) {
+	impersonationService := di.InitializeImpersonationService()
+	userService := di.InitializeUserService()
+
+	session, err := impersonationService.GetActiveSession(ctx, claims.SessionID)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	if session.ActorID.String() != claims.ActorID || session.TargetID.String() != claims.UserID {
+		return model.User{}, errs.ErrUnauthorized
+	}
+
+	actor, err := userService.GetUserByID(ctx, claims.ActorID)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	if !hasAdminRole(ctx, actor) {
+		return model.User{}, errs.ErrForbidden
+	}
+
+	return actor, nil
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/Alfian57/belajar-golang/internal/config"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// CorsMiddleware answers CORS requests for the origins of the current
+// configuration, so CORS_ALLOW_ORIGINS can be hot reloaded. "*" allows every
+// origin with a literal * header, which browsers refuse for credentialed
+// requests; listed origins may hold wildcards, such as
+// https://*.example.com.
+func CorsMiddleware(cfg config.CorsConfig) gin.HandlerFunc {
+	var mu sync.Mutex
+	origins := cfg.AllowOrigins
+	handler := cors.New(corsConfig(cfg, origins))
+
+	return func(ctx *gin.Context) {
+		current := config.Current().Cors.AllowOrigins
+
+		mu.Lock()
+		if !slices.Equal(current, origins) {
+			next := corsConfig(cfg, current)
+			if err := next.Validate(); err != nil {
+				logger.FromContext(ctx).Errorw("invalid CORS origins, keeping the previous ones", "origins", current, "error", err)
+			} else {
+				handler = cors.New(next)
+			}
+			origins = current
+		}
+		serve := handler
+		mu.Unlock()
+
+		serve(ctx)
+	}
+}
+
+func corsConfig(cfg config.CorsConfig, origins []string) cors.Config {
+	corsCfg := cors.Config{
+		AllowMethods:     cfg.AllowMethods,
+		AllowCredentials: cfg.AllowCredentials,
+	}
+	if slices.Contains(origins, "*") {
+		corsCfg.AllowAllOrigins = true
+	} else {
+		corsCfg.AllowOrigins = origins
+		corsCfg.AllowWildcard = true
+	}
+
+	return corsCfg
+}
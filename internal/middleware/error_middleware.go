@@ -11,7 +11,7 @@ func ErrorMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
-				logger.Log.Errorw("panic recovered", "error", err)
+				logger.FromContext(c).Errorw("panic recovered", "error", err)
 				response.WriteErrorResponse(c, errs.ErrInternalServer)
 				c.Abort()
 			}
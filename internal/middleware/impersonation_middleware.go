@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/response"
+	"github.com/Alfian57/belajar-golang/internal/utils/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// NoImpersonationMiddleware blocks sensitive actions, such as changing the
+// password, for requests made with an impersonation token.
+func NoImpersonationMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if auth.IsImpersonating(ctx) {
+			response.WriteErrorResponse(ctx, errs.ErrImpersonationNotAllowed)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/Alfian57/belajar-golang/internal/config"
+	"github.com/Alfian57/belajar-golang/internal/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// LocaleMiddleware picks the locale of error messages from Accept-Language,
+// falling back to DEFAULT_LOCALE, and stores it in the request context, see
+// i18n.FromContext.
+func LocaleMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		locale := i18n.Match(ctx.GetHeader("Accept-Language"), config.Current().Server.DefaultLocale)
+
+		ctx.Header("Content-Language", locale)
+		ctx.Writer.Header().Add("Vary", "Accept-Language")
+		ctx.Request = ctx.Request.WithContext(i18n.WithLocale(ctx.Request.Context(), locale))
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/config"
+	"github.com/Alfian57/belajar-golang/internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware records every request under its route template, such as
+// /api/v1/admin/users/:id, so IDs in paths do not explode the label values.
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		metrics.HTTPRequestsInFlight.Inc()
+
+		ctx.Next()
+
+		metrics.HTTPRequestsInFlight.Dec()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(ctx.Writer.Status())
+
+		metrics.HTTPRequests.WithLabelValues(ctx.Request.Method, route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(ctx.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// MetricsAuthMiddleware requires METRICS_TOKEN as a bearer token when it is
+// set. Without a token the endpoint is open, for scrapers on a private network.
+func MetricsAuthMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token := config.Current().Metrics.Token
+		if token == "" {
+			ctx.Next()
+			return
+		}
+
+		expected := "Bearer " + token
+		if subtle.ConstantTimeCompare([]byte(ctx.GetHeader("Authorization")), []byte(expected)) != 1 {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		ctx.Next()
+	}
+}
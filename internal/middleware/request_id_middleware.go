@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds IDs taken from clients, which end up in every
+// log line of the request.
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware keeps the X-Request-ID of the caller, or assigns one,
+// and echoes it in the response. The request context gets a logger that adds
+// the request ID and route to every line, see logger.FromContext.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		ctx.Set("request_id", requestID)
+		ctx.Header(RequestIDHeader, requestID)
+		trace.SpanFromContext(ctx.Request.Context()).SetAttributes(attribute.String("request.id", requestID))
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		requestCtx := logger.With(ctx.Request.Context(), "request_id", requestID, "route", route)
+		ctx.Request = ctx.Request.WithContext(requestCtx)
+
+		ctx.Next()
+	}
+}
+
+// validRequestID accepts printable ASCII without spaces, so a client cannot
+// forge log lines or headers through its request ID.
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+
+	for _, r := range requestID {
+		if r <= ' ' || r > '~' {
+			return false
+		}
+	}
+
+	return true
+}
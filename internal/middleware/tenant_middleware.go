@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/Alfian57/belajar-golang/internal/config"
+	"github.com/Alfian57/belajar-golang/internal/di"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/response"
+	"github.com/Alfian57/belajar-golang/internal/tenant"
+	"github.com/Alfian57/belajar-golang/internal/utils/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// TenantMiddleware resolves the organization of the request from the
+// :organization path parameter, the X-Organization header or the subdomain,
+// in that order. Requests without any of them stay global. Authenticated
+// users must be members of the organization unless they are global admins.
+func TenantMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		identifier := tenantIdentifier(ctx)
+		if identifier == "" {
+			ctx.Next()
+			return
+		}
+
+		organizationService := di.InitializeOrganizationService()
+
+		organization, err := organizationService.Resolve(ctx, identifier)
+		if err != nil {
+			response.WriteErrorResponse(ctx, err)
+			ctx.Abort()
+			return
+		}
+
+		if user, ok := auth.GetCurrentUser(ctx); ok {
+			member, err := organizationService.GetMembership(ctx, organization.ID, user.ID)
+			switch {
+			case err == nil:
+				ctx.Set(tenant.RoleKey, member.Role)
+			case err == errs.ErrOrganizationMemberNotFound && isAdmin(ctx, user):
+				// Global admins can act on any organization without being a member
+			case err == errs.ErrOrganizationMemberNotFound:
+				// Hide organizations the user does not belong to
+				response.WriteErrorResponse(ctx, errs.ErrOrganizationNotFound)
+				ctx.Abort()
+				return
+			default:
+				response.WriteErrorResponse(ctx, err)
+				ctx.Abort()
+				return
+			}
+		}
+
+		ctx.Set(tenant.OrganizationKey, organization)
+
+		ctx.Next()
+	}
+}
+
+// OrganizationRoleMiddleware allows members of the resolved organization with
+// one of the given roles. Global admins are always allowed, with or without a
+// resolved organization.
+func OrganizationRoleMiddleware(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, ok := auth.GetCurrentUser(ctx)
+		if !ok {
+			response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+			ctx.Abort()
+			return
+		}
+
+		if isAdmin(ctx, user) {
+			ctx.Next()
+			return
+		}
+
+		if _, ok := tenant.FromContext(ctx); !ok {
+			response.WriteErrorResponse(ctx, errs.ErrForbidden)
+			ctx.Abort()
+			return
+		}
+
+		if !slices.Contains(roles, tenant.RoleFromContext(ctx)) {
+			response.WriteErrorResponse(ctx, errs.ErrForbidden)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func tenantIdentifier(ctx *gin.Context) string {
+	if identifier := ctx.Param("organization"); identifier != "" {
+		return identifier
+	}
+
+	if identifier := ctx.GetHeader("X-Organization"); identifier != "" {
+		return identifier
+	}
+
+	return subdomain(ctx.Request.Host, config.Current().Server.TenantBaseDomain)
+}
+
+// subdomain returns the leftmost label of host when host is a direct
+// subdomain of baseDomain, e.g. "acme" for "acme.example.com".
+func subdomain(host string, baseDomain string) string {
+	if baseDomain == "" {
+		return ""
+	}
+
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	label, found := strings.CutSuffix(strings.ToLower(host), "."+strings.ToLower(baseDomain))
+	if !found || label == "" || strings.Contains(label, ".") || label == "www" || label == "api" {
+		return ""
+	}
+
+	return label
+}
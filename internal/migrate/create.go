@@ -0,0 +1,44 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+var namePattern = regexp.MustCompile(`^\w+$`)
+
+// Create writes an empty up and down migration to dir, numbered after the
+// highest existing version. It returns the paths of the new files.
+func Create(dir string, name string) ([]string, error) {
+	if !namePattern.MatchString(name) {
+		return nil, fmt.Errorf("migration name %q may only contain letters, digits and underscores", name)
+	}
+
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	version := uint64(1)
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+
+	base := fmt.Sprintf("%06d_%s", version, name)
+	paths := []string{
+		filepath.Join(dir, base+".up.sql"),
+		filepath.Join(dir, base+".down.sql"),
+	}
+
+	for _, path := range paths {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, err
+		}
+		file.Close()
+	}
+
+	return paths, nil
+}
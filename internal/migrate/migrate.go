@@ -0,0 +1,310 @@
+// Package migrate applies the embedded SQL migrations. It keeps its state in
+// the same schema_migrations table as the golang-migrate CLI, so databases
+// migrated with either tool stay compatible.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/Alfian57/belajar-golang/internal/logger"
+)
+
+// lockID identifies the advisory lock held while migrating, so replicas
+// starting at the same time run migrations one after another.
+const lockID = 7_318_642_905
+
+var (
+	ErrDirty      = errors.New("database is dirty, fix the last migration and force a version")
+	ErrNoChange   = errors.New("no change")
+	ErrNoVersion  = errors.New("unknown migration version")
+	ErrNoDownStep = errors.New("nothing to roll back")
+)
+
+type Runner struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// Status describes one migration and whether it has been applied.
+type Status struct {
+	Version uint64
+	Name    string
+	Applied bool
+	Dirty   bool
+}
+
+func NewRunner(db *sql.DB, fsys fs.FS) (*Runner, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Runner{db: db, migrations: migrations}, nil
+}
+
+// Up applies all pending migrations.
+func (r *Runner) Up(ctx context.Context) error {
+	return r.withLock(ctx, func(conn *sql.Conn, current uint64) error {
+		applied := 0
+		for _, migration := range r.migrations {
+			if migration.Version <= current {
+				continue
+			}
+			if err := r.apply(ctx, conn, migration.Version, migration.Name, migration.Up, migration.Version); err != nil {
+				return err
+			}
+			applied++
+		}
+
+		if applied == 0 {
+			return ErrNoChange
+		}
+		return nil
+	})
+}
+
+// Down rolls back the last n applied migrations.
+func (r *Runner) Down(ctx context.Context, n int) error {
+	return r.withLock(ctx, func(conn *sql.Conn, current uint64) error {
+		for i := 0; i < n; i++ {
+			index := r.indexOf(current)
+			if index < 0 {
+				if i == 0 {
+					return ErrNoDownStep
+				}
+				return nil
+			}
+
+			migration := r.migrations[index]
+			previous := uint64(0)
+			if index > 0 {
+				previous = r.migrations[index-1].Version
+			}
+
+			if err := r.apply(ctx, conn, migration.Version, migration.Name, migration.Down, previous); err != nil {
+				return err
+			}
+			current = previous
+		}
+		return nil
+	})
+}
+
+// Goto migrates up or down until the given version is the current one.
+// Version 0 rolls back every migration.
+func (r *Runner) Goto(ctx context.Context, version uint64) error {
+	if version != 0 && r.indexOf(version) < 0 {
+		return ErrNoVersion
+	}
+
+	return r.withLock(ctx, func(conn *sql.Conn, current uint64) error {
+		if version == current {
+			return ErrNoChange
+		}
+
+		if version > current {
+			for _, migration := range r.migrations {
+				if migration.Version <= current || migration.Version > version {
+					continue
+				}
+				if err := r.apply(ctx, conn, migration.Version, migration.Name, migration.Up, migration.Version); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		for index := r.indexOf(current); index >= 0 && r.migrations[index].Version > version; index-- {
+			migration := r.migrations[index]
+			previous := uint64(0)
+			if index > 0 {
+				previous = r.migrations[index-1].Version
+			}
+			if err := r.apply(ctx, conn, migration.Version, migration.Name, migration.Down, previous); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Force sets the version without running any migration and clears the dirty
+// flag. Use it after fixing a migration that failed halfway.
+func (r *Runner) Force(ctx context.Context, version uint64) error {
+	conn, err := r.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer r.unlock(conn)
+
+	if err := ensureTable(ctx, conn); err != nil {
+		return err
+	}
+
+	return setVersion(ctx, conn, version, false)
+}
+
+// Version returns the current version and whether the last migration failed.
+func (r *Runner) Version(ctx context.Context) (uint64, bool, error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	defer conn.Close()
+
+	if err := ensureTable(ctx, conn); err != nil {
+		return 0, false, err
+	}
+
+	return currentVersion(ctx, conn)
+}
+
+// Status lists all known migrations with their applied state.
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	current, dirty, err := r.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(r.migrations))
+	for i, migration := range r.migrations {
+		statuses[i] = Status{
+			Version: migration.Version,
+			Name:    migration.Name,
+			Applied: migration.Version <= current,
+			Dirty:   dirty && migration.Version == current,
+		}
+	}
+
+	return statuses, nil
+}
+
+// Migrations returns the loaded migrations sorted by version.
+func (r *Runner) Migrations() []Migration {
+	return r.migrations
+}
+
+func (r *Runner) withLock(ctx context.Context, fn func(conn *sql.Conn, current uint64) error) error {
+	conn, err := r.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer r.unlock(conn)
+
+	if err := ensureTable(ctx, conn); err != nil {
+		return err
+	}
+
+	current, dirty, err := currentVersion(ctx, conn)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("%w (version %d)", ErrDirty, current)
+	}
+
+	return fn(conn, current)
+}
+
+// apply runs one script in a transaction. The version is marked dirty first,
+// so a failure leaves a trace that must be resolved with Force.
+func (r *Runner) apply(ctx context.Context, conn *sql.Conn, version uint64, name string, script string, target uint64) error {
+	logger.Log.Infow("applying migration", "version", version, "name", name, "target", target)
+
+	if err := setVersion(ctx, conn, version, true); err != nil {
+		return err
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration %d_%s failed: %w", version, name, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return setVersion(ctx, conn, target, false)
+}
+
+func (r *Runner) indexOf(version uint64) int {
+	for i, migration := range r.migrations {
+		if migration.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// lock takes the advisory lock on a dedicated connection; session level
+// advisory locks belong to the connection that acquired them.
+func (r *Runner) lock(ctx context.Context) (*sql.Conn, error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	return conn, nil
+}
+
+func (r *Runner) unlock(conn *sql.Conn) {
+	if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockID); err != nil {
+		logger.Log.Errorw("failed to release migration lock", "error", err)
+	}
+	conn.Close()
+}
+
+func ensureTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS "schema_migrations" ("version" BIGINT NOT NULL PRIMARY KEY, "dirty" BOOLEAN NOT NULL)`)
+	return err
+}
+
+func currentVersion(ctx context.Context, conn *sql.Conn) (uint64, bool, error) {
+	var version int64
+	var dirty bool
+
+	err := conn.QueryRowContext(ctx, `SELECT "version", "dirty" FROM "schema_migrations" LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return uint64(version), dirty, nil
+}
+
+// setVersion stores the single schema_migrations row. Version 0 means no
+// migration is applied and is stored as an empty table.
+func setVersion(ctx context.Context, conn *sql.Conn, version uint64, dirty bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM "schema_migrations"`); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if version > 0 || dirty {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO "schema_migrations" ("version", "dirty") VALUES ($1, $2)`, int64(version), dirty); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Group struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	OrganizationID *uuid.UUID `json:"organization_id" gorm:"type:uuid"`
+	ParentID       *uuid.UUID `json:"parent_id" gorm:"type:uuid"`
+	Name           string     `json:"name" gorm:"not null"`
+	Role           *string    `json:"role"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (Group) TableName() string {
+	return "groups"
+}
+
+type GroupMember struct {
+	GroupID   uuid.UUID `json:"group_id" gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	User      *User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+func (GroupMember) TableName() string {
+	return "group_members"
+}
+
+type GroupPermission struct {
+	GroupID    uuid.UUID `json:"group_id" gorm:"type:uuid;primaryKey"`
+	Permission string    `json:"permission" gorm:"primaryKey"`
+}
+
+func (GroupPermission) TableName() string {
+	return "group_permissions"
+}
+
+type UserPermission struct {
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	Permission string    `json:"permission" gorm:"primaryKey"`
+}
+
+func (UserPermission) TableName() string {
+	return "user_permissions"
+}
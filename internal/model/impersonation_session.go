@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ImpersonationSession struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	ActorID   uuid.UUID  `json:"actor_id" gorm:"type:uuid;not null"`
+	TargetID  uuid.UUID  `json:"target_id" gorm:"type:uuid;not null"`
+	Reason    string     `json:"reason" gorm:"not null"`
+	IPAddress string     `json:"ip_address" gorm:"not null"`
+	UserAgent string     `json:"user_agent" gorm:"not null"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	EndedAt   *time.Time `json:"ended_at"`
+}
+
+func (ImpersonationSession) TableName() string {
+	return "impersonation_sessions"
+}
+
+// IsActive reports whether the session can still be used at the given time
+func (s ImpersonationSession) IsActive(now time.Time) bool {
+	return s.EndedAt == nil && now.Before(s.ExpiresAt)
+}
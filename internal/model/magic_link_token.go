@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MagicLinkToken struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	UserID      uuid.UUID  `json:"user_id" gorm:"type:uuid;not null"`
+	Email       string     `json:"email" gorm:"not null"`
+	TokenHash   string     `json:"-" gorm:"uniqueIndex;not null"`
+	Fingerprint string     `json:"-" gorm:"not null"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	ExpiresAt   time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt      *time.Time `json:"used_at"`
+}
+
+func (MagicLinkToken) TableName() string {
+	return "magic_link_tokens"
+}
@@ -0,0 +1,21 @@
+package model
+
+// All returns one value of every model backed by a migrated table. The
+// schema drift checker compares these against the database.
+func All() []any {
+	return []any{
+		&User{},
+		&RefreshToken{},
+		&PasswordHistory{},
+		&MagicLinkToken{},
+		&ImpersonationSession{},
+		&Organization{},
+		&OrganizationMember{},
+		&OrganizationInvitation{},
+		&Group{},
+		&GroupMember{},
+		&GroupPermission{},
+		&UserPermission{},
+		&UserImportJob{},
+	}
+}
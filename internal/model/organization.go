@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	OrganizationRoleOwner  = "owner"
+	OrganizationRoleAdmin  = "admin"
+	OrganizationRoleMember = "member"
+)
+
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusDeclined = "declined"
+	InvitationStatusRevoked  = "revoked"
+)
+
+type Organization struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	Name      string    `json:"name" gorm:"not null"`
+	Slug      string    `json:"slug" gorm:"uniqueIndex;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (Organization) TableName() string {
+	return "organizations"
+}
+
+type OrganizationMember struct {
+	ID             uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	OrganizationID uuid.UUID `json:"organization_id" gorm:"type:uuid;not null"`
+	UserID         uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	Role           string    `json:"role" gorm:"not null"`
+	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
+	User           *User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+func (OrganizationMember) TableName() string {
+	return "organization_members"
+}
+
+// IsAdmin reports whether the member can manage the organization
+func (m OrganizationMember) IsAdmin() bool {
+	return m.Role == OrganizationRoleOwner || m.Role == OrganizationRoleAdmin
+}
+
+type OrganizationInvitation struct {
+	ID             uuid.UUID     `json:"id" gorm:"type:uuid;primary_key"`
+	OrganizationID uuid.UUID     `json:"organization_id" gorm:"type:uuid;not null"`
+	Email          string        `json:"email" gorm:"not null"`
+	Role           string        `json:"role" gorm:"not null"`
+	TokenHash      string        `json:"-" gorm:"uniqueIndex;not null"`
+	Status         string        `json:"status" gorm:"not null"`
+	InvitedBy      *uuid.UUID    `json:"invited_by" gorm:"type:uuid"`
+	CreatedAt      time.Time     `json:"created_at" gorm:"autoCreateTime"`
+	ExpiresAt      time.Time     `json:"expires_at" gorm:"not null"`
+	RespondedAt    *time.Time    `json:"responded_at"`
+	Organization   *Organization `json:"organization,omitempty" gorm:"foreignKey:OrganizationID"`
+}
+
+func (OrganizationInvitation) TableName() string {
+	return "organization_invitations"
+}
+
+// IsPending reports whether the invitation can still be answered at the given time
+func (i OrganizationInvitation) IsPending(now time.Time) bool {
+	return i.Status == InvitationStatusPending && now.Before(i.ExpiresAt)
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PasswordHistory struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	UserID       uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	PasswordHash string    `json:"-" gorm:"not null"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (PasswordHistory) TableName() string {
+	return "password_histories"
+}
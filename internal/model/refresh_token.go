@@ -11,7 +11,7 @@ type RefreshToken struct {
 	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
 	TokenHash string    `json:"token_hash" gorm:"not null"`
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
-	ExpiresAt int64     `json:"expires_at" gorm:"not null"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null"`
 }
 
 func (RefreshToken) TableName() string {
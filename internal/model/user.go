@@ -18,6 +18,7 @@ type User struct {
 	Username  string    `json:"username" gorm:"uniqueIndex;not null"`
 	Password  string    `json:"-" gorm:"not null"`
 	Role      string    `json:"role" gorm:"not null"`
+	IsBanned  bool      `json:"is_banned" gorm:"not null;default:false"`
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 }
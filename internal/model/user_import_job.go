@@ -0,0 +1,68 @@
+package model
+
+import (
+	"time"
+
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/google/uuid"
+)
+
+const (
+	ImportStatusPending   = "pending"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+)
+
+const (
+	ImportRowValid   = "valid"
+	ImportRowCreated = "created"
+	ImportRowFailed  = "failed"
+)
+
+// UserImportJob is an import of users from a file. Small files are imported
+// while the request waits, larger ones in the background, with the counts
+// reporting the progress.
+type UserImportJob struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	CreatedBy     *uuid.UUID `json:"created_by" gorm:"type:uuid"`
+	Filename      string     `json:"filename" gorm:"not null"`
+	DryRun        bool       `json:"dry_run" gorm:"not null"`
+	Status        string     `json:"status" gorm:"not null"`
+	TotalRows     int        `json:"total_rows" gorm:"not null"`
+	ProcessedRows int        `json:"processed_rows" gorm:"not null"`
+	SucceededRows int        `json:"succeeded_rows" gorm:"not null"`
+	FailedRows    int        `json:"failed_rows" gorm:"not null"`
+	// Preview holds the first rows with their outcome, Failures every
+	// failed row
+	Preview  []ImportRow `json:"preview" gorm:"serializer:json;not null"`
+	Failures []ImportRow `json:"failures" gorm:"serializer:json;not null"`
+	// Error is why the job itself failed
+	Error      string     `json:"error,omitempty" gorm:"not null"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+func (UserImportJob) TableName() string {
+	return "user_import_jobs"
+}
+
+// Finished reports whether the job has stopped, successfully or not.
+func (j UserImportJob) Finished() bool {
+	return j.Status == ImportStatusCompleted || j.Status == ImportStatusFailed
+}
+
+// ImportRow is the outcome of a row of an imported file. Number is the row
+// in the file, counting the header. Errors are rendered in the language of
+// the request that started the import.
+type ImportRow struct {
+	Number   int               `json:"row"`
+	Email    string            `json:"email"`
+	Username string            `json:"username"`
+	Status   string            `json:"status"`
+	ID       uuid.UUID         `json:"id,omitzero"`
+	Code     errs.Code         `json:"code,omitempty"`
+	Message  string            `json:"message,omitempty"`
+	Errors   []errs.FieldError `json:"errors,omitempty"`
+}
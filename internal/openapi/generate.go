@@ -0,0 +1,309 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/response"
+)
+
+// Security schemes. The access token is accepted as a bearer token or in
+// the access_token cookie, the refresh token only in its cookie.
+const (
+	BearerAuth  = "bearerAuth"
+	CookieAuth  = "cookieAuth"
+	RefreshAuth = "refreshCookie"
+)
+
+var (
+	// Authenticated accepts either form of the access token.
+	Authenticated = []SecurityRequirement{{BearerAuth: {}}, {CookieAuth: {}}}
+	// RefreshCookie needs the access and the refresh token cookies.
+	RefreshCookie = []SecurityRequirement{{CookieAuth: {}, RefreshAuth: {}}}
+)
+
+// Route describes one registered route.
+type Route struct {
+	Method string
+	// Path uses gin syntax, e.g. /api/v1/groups/:id
+	Path        string
+	ID          string
+	Tag         string
+	Summary     string
+	Description string
+	Security    []SecurityRequirement
+
+	// Query is a struct bound with ShouldBindQuery, Body one bound from the
+	// request body. Upload bodies are multipart forms described by their
+	// form tags, for files.
+	Query  any
+	Body   any
+	Upload bool
+
+	// Status is the success status, 200 by default.
+	Status int
+	// Data is the data of a response.Response. Without it the success
+	// response carries a message.
+	Data      any
+	Paginated bool
+	// Raw replaces the response.Response envelope, e.g. for probes. It is
+	// served as ContentType, application/json by default, or one of several
+	// types separated by commas, e.g. for downloads.
+	Raw         any
+	ContentType string
+
+	// Errors lists error statuses besides the ones implied by the route,
+	// such as 404.
+	Errors []int
+}
+
+// Key identifies the route like gin does, e.g. "GET /api/v1/groups/:id".
+func (r Route) Key() string {
+	return r.Method + " " + r.Path
+}
+
+// Generate builds the document of routes. It fails on routes described twice
+// or sharing an operation ID.
+func Generate(info Info, routes []Route) (*Document, error) {
+	s := newSchemas()
+	s.of(response.Response{})
+	s.of(errs.FieldError{})
+	s.of(response.Problem{})
+
+	document := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]*PathItem),
+		Components: Components{
+			Schemas:         s.components,
+			Responses:       errorResponses(),
+			SecuritySchemes: securitySchemes(),
+		},
+	}
+
+	ids := make(map[string]string)
+	tags := make(map[string]bool)
+	for _, route := range routes {
+		if other, ok := ids[route.ID]; ok {
+			return nil, fmt.Errorf("openapi: %s and %s share the operation ID %q", other, route.Key(), route.ID)
+		}
+		ids[route.ID] = route.Key()
+
+		path, parameters := convertPath(route.Path)
+		item, ok := document.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			document.Paths[path] = item
+		}
+
+		method := strings.ToLower(route.Method)
+		if _, ok := (*item)[method]; ok {
+			return nil, fmt.Errorf("openapi: %s is described twice", route.Key())
+		}
+		(*item)[method] = operation(s, route, parameters)
+
+		if route.Tag != "" && !tags[route.Tag] {
+			tags[route.Tag] = true
+			document.Tags = append(document.Tags, Tag{Name: route.Tag})
+		}
+	}
+
+	sort.Slice(document.Tags, func(i, j int) bool { return document.Tags[i].Name < document.Tags[j].Name })
+
+	return document, nil
+}
+
+func operation(s *schemas, route Route, parameters []Parameter) *Operation {
+	op := &Operation{
+		Summary:     route.Summary,
+		Description: route.Description,
+		OperationID: route.ID,
+		Parameters:  parameters,
+		Responses:   make(map[string]*Response),
+		Security:    route.Security,
+	}
+	if route.Tag != "" {
+		op.Tags = []string{route.Tag}
+	}
+
+	if route.Query != nil {
+		op.Parameters = append(op.Parameters, s.parameters(route.Query)...)
+	}
+
+	if route.Body != nil && route.Upload {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{
+				"multipart/form-data": {Schema: s.form(route.Body)},
+			},
+		}
+	} else if route.Body != nil {
+		schema := s.of(route.Body)
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{
+				"application/json":                  {Schema: schema},
+				"application/x-www-form-urlencoded": {Schema: schema},
+			},
+		}
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	op.Responses[strconv.Itoa(status)] = successResponse(s, route, status)
+
+	var errorStatuses []int
+	if route.Query != nil || route.Body != nil {
+		errorStatuses = append(errorStatuses, http.StatusBadRequest, http.StatusUnprocessableEntity)
+	} else if hasUUIDParameter(parameters) {
+		// Malformed IDs fail the uuid_param rule
+		errorStatuses = append(errorStatuses, http.StatusUnprocessableEntity)
+	}
+	if route.Security != nil {
+		errorStatuses = append(errorStatuses, http.StatusUnauthorized)
+	}
+	errorStatuses = append(errorStatuses, route.Errors...)
+	if route.Raw == nil {
+		errorStatuses = append(errorStatuses, http.StatusInternalServerError)
+	}
+
+	for _, code := range errorStatuses {
+		name := "Error"
+		if code == http.StatusUnprocessableEntity {
+			name = "ValidationError"
+		}
+		op.Responses[strconv.Itoa(code)] = &Response{Ref: "#/components/responses/" + name}
+	}
+
+	return op
+}
+
+func successResponse(s *schemas, route Route, status int) *Response {
+	description := http.StatusText(status)
+
+	if route.Raw != nil {
+		contentType := route.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		content := make(map[string]MediaType)
+		for _, contentType := range strings.Split(contentType, ",") {
+			content[strings.TrimSpace(contentType)] = MediaType{Schema: s.of(route.Raw)}
+		}
+		return &Response{Description: description, Content: content}
+	}
+
+	properties := map[string]*Schema{}
+	switch {
+	case route.Data != nil && route.Paginated:
+		properties["data"] = &Schema{Type: "array", Items: s.of(route.Data)}
+		properties["pagination"] = s.of(dto.PaginationResponse{})
+	case route.Data != nil:
+		properties["data"] = s.of(route.Data)
+	default:
+		properties["message"] = &Schema{Type: "string"}
+	}
+
+	envelope := &Schema{AllOf: []*Schema{
+		Ref("Response"),
+		{Type: "object", Properties: properties},
+	}}
+
+	return &Response{
+		Description: description,
+		Content:     map[string]MediaType{"application/json": {Schema: envelope}},
+	}
+}
+
+// errorResponses describe the envelope of errors and the problem details
+// served instead when the client accepts application/problem+json.
+func errorResponses() map[string]*Response {
+	problem := MediaType{Schema: Ref("Problem")}
+
+	return map[string]*Response{
+		"Error": {
+			Description: "The request failed, error holds the reason and code identifies it.",
+			Content: map[string]MediaType{
+				"application/json": {Schema: &Schema{AllOf: []*Schema{
+					Ref("Response"),
+					{Type: "object", Properties: map[string]*Schema{"error": {Type: "string"}}},
+				}}},
+				response.ProblemContentType: problem,
+			},
+		},
+		"ValidationError": {
+			Description: "The request did not pass validation, error lists the invalid fields or holds the reason.",
+			Content: map[string]MediaType{
+				"application/json": {Schema: &Schema{AllOf: []*Schema{
+					Ref("Response"),
+					{Type: "object", Properties: map[string]*Schema{"error": {OneOf: []*Schema{
+						{Type: "array", Items: Ref("FieldError")},
+						{Type: "string"},
+					}}}},
+				}}},
+				response.ProblemContentType: problem,
+			},
+		},
+	}
+}
+
+func securitySchemes() map[string]*SecurityScheme {
+	return map[string]*SecurityScheme{
+		BearerAuth: {
+			Type:         "http",
+			Scheme:       "bearer",
+			BearerFormat: "JWT",
+			Description:  "Access token from the access_token cookie set at login.",
+		},
+		CookieAuth: {
+			Type: "apiKey",
+			In:   "cookie",
+			Name: "access_token",
+		},
+		RefreshAuth: {
+			Type: "apiKey",
+			In:   "cookie",
+			Name: "refresh_token",
+		},
+	}
+}
+
+func hasUUIDParameter(parameters []Parameter) bool {
+	for _, parameter := range parameters {
+		if parameter.Schema.Format == "uuid" {
+			return true
+		}
+	}
+	return false
+}
+
+// convertPath turns gin parameters into OpenAPI ones, /groups/:id becomes
+// /groups/{id}. IDs are parsed as UUIDs by the handlers.
+func convertPath(path string) (string, []Parameter) {
+	segments := strings.Split(path, "/")
+	var parameters []Parameter
+
+	for i, segment := range segments {
+		if segment == "" || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+
+		name := segment[1:]
+		segments[i] = "{" + name + "}"
+
+		schema := &Schema{Type: "string"}
+		if name == "id" || strings.HasSuffix(name, "_id") {
+			schema.Format = "uuid"
+		}
+		parameters = append(parameters, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+
+	return strings.Join(segments, "/"), parameters
+}
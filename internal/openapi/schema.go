@@ -0,0 +1,349 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/validation"
+	"github.com/google/uuid"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	uuidType       = reflect.TypeOf(uuid.UUID{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	errorCodeType  = reflect.TypeOf(errs.Code(""))
+	fileType       = reflect.TypeOf(multipart.FileHeader{})
+)
+
+// schemas turns Go types into schemas, adding named structs to the
+// components so they are described once and referenced.
+type schemas struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemas() *schemas {
+	return &schemas{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// of returns the schema of the type of value, which may be a nil pointer.
+func (s *schemas) of(value any) *Schema {
+	return s.schema(reflect.TypeOf(value))
+}
+
+func (s *schemas) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case rawMessageType:
+		return &Schema{}
+	case fileType:
+		return &Schema{Type: "string", Format: "binary"}
+	case errorCodeType:
+		if _, ok := s.components["ErrorCode"]; !ok {
+			s.components["ErrorCode"] = errorCodeSchema()
+		}
+		return Ref("ErrorCode")
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer"}
+	case reflect.Int32, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t, "json")
+		}
+		return Ref(s.component(t))
+	default:
+		// Interfaces accept any value
+		return &Schema{}
+	}
+}
+
+// component registers a named struct and returns its component name. The
+// name is reserved before the fields are described, so recursive types end
+// in a reference.
+func (s *schemas) component(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	// Generic instantiations are named like PaginatedResult[...]
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i]
+	}
+	if _, taken := s.components[name]; taken {
+		name = packageName(t) + name
+	}
+
+	s.names[t] = name
+	s.components[name] = &Schema{}
+	*s.components[name] = *s.object(t, "json")
+
+	return name
+}
+
+func packageName(t reflect.Type) string {
+	path := t.PkgPath()
+	name := path[strings.LastIndexByte(path, '/')+1:]
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// object describes the fields of a struct named by tag, flattening embedded
+// structs the way encoding/json does.
+func (s *schemas) object(t reflect.Type, tag string) *Schema {
+	object := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for _, field := range fields(t, tag) {
+		property := s.schema(field.Type)
+		if field.required(property) {
+			object.Required = append(object.Required, field.name)
+		}
+		object.Properties[field.name] = property
+	}
+
+	return object
+}
+
+// form describes a multipart form, named by its form tags. Other fields
+// than files and plain values are sent as JSON.
+func (s *schemas) form(value any) *Schema {
+	t := reflect.TypeOf(value)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return s.object(t, "form")
+}
+
+// parameters describes the fields of a query struct, named by their form tags.
+func (s *schemas) parameters(value any) []Parameter {
+	t := reflect.TypeOf(value)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var parameters []Parameter
+	for _, field := range fields(t, "form") {
+		schema := s.schema(field.Type)
+		parameter := Parameter{
+			Name:     field.name,
+			In:       "query",
+			Required: field.required(schema),
+			Schema:   schema,
+		}
+
+		// Maps are sent as filter[key]=value
+		if field.Type.Kind() == reflect.Map {
+			parameter.Style = "deepObject"
+			parameter.Explode = true
+		}
+		parameters = append(parameters, parameter)
+	}
+
+	return parameters
+}
+
+type structField struct {
+	reflect.StructField
+	name string
+}
+
+func fields(t reflect.Type, tag string) []structField {
+	var result []structField
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+
+		embedded := field.Type
+		if embedded.Kind() == reflect.Pointer {
+			embedded = embedded.Elem()
+		}
+		if field.Anonymous && name == "" && embedded.Kind() == reflect.Struct {
+			result = append(result, fields(embedded, tag)...)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		result = append(result, structField{StructField: field, name: name})
+	}
+
+	return result
+}
+
+// required applies the binding rules of the field to schema and reports
+// whether the field is required. Rules after "dive" apply to the elements.
+func (f structField) required(schema *Schema) bool {
+	rules := f.Tag.Get("binding")
+	if rules == "" {
+		return false
+	}
+
+	own, elements, _ := strings.Cut(rules, ",dive")
+	required := applyRules(schema, f.Type, own)
+
+	if elements != "" && schema.Items != nil {
+		applyRules(schema.Items, f.Type.Elem(), strings.TrimPrefix(elements, ","))
+	}
+	if elements != "" && schema.AdditionalProperties != nil {
+		applyRules(schema.AdditionalProperties, f.Type.Elem(), strings.TrimPrefix(elements, ","))
+	}
+
+	return required
+}
+
+func applyRules(schema *Schema, t reflect.Type, rules string) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// References cannot carry constraints next to them
+	if schema.Ref != "" {
+		return strings.Contains(","+rules+",", ",required,")
+	}
+
+	required := false
+	for _, rule := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "required":
+			required = true
+		case "min", "max", "len":
+			limit, err := strconv.Atoi(param)
+			if err != nil {
+				continue
+			}
+			if name != "max" {
+				setMin(schema, limit)
+			}
+			if name != "min" {
+				setMax(schema, limit)
+			}
+		case "gt", "gte", "lt", "lte":
+			limit, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			switch name {
+			case "gt":
+				schema.ExclusiveMinimum = &limit
+			case "gte":
+				schema.Minimum = &limit
+			case "lt":
+				schema.ExclusiveMaximum = &limit
+			case "lte":
+				schema.Maximum = &limit
+			}
+		case "email":
+			schema.Format = "email"
+		case "url", "uri", "http_url":
+			schema.Format = "uri"
+		case "uuid", "uuid4", "uuid_param":
+			schema.Format = "uuid"
+		case "username":
+			schema.Pattern = validation.UsernamePattern
+		case "password_strength":
+			schema.Description = "Must satisfy the password policy."
+		case "unique_email", "unique_username":
+			schema.Description = "Must not be taken by another user."
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				schema.Enum = append(schema.Enum, enumValue(t, value))
+			}
+		case "eqfield":
+			schema.Description = fmt.Sprintf("Must equal %s.", param)
+		}
+	}
+
+	return required
+}
+
+func setMin(schema *Schema, limit int) {
+	switch schema.Type {
+	case "string":
+		schema.MinLength = &limit
+	case "array":
+		schema.MinItems = &limit
+	case "integer", "number":
+		value := float64(limit)
+		schema.Minimum = &value
+	}
+}
+
+func setMax(schema *Schema, limit int) {
+	switch schema.Type {
+	case "string":
+		schema.MaxLength = &limit
+	case "array":
+		schema.MaxItems = &limit
+	case "integer", "number":
+		value := float64(limit)
+		schema.Maximum = &value
+	}
+}
+
+func enumValue(t reflect.Type, value string) any {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if number, err := strconv.Atoi(value); err == nil {
+			return number
+		}
+	}
+
+	return value
+}
+
+// errorCodeSchema enumerates the catalog, so clients can generate constants.
+func errorCodeSchema() *Schema {
+	schema := &Schema{Type: "string", Description: "Stable error code, see /problems."}
+	for _, info := range errs.Codes() {
+		schema.Enum = append(schema.Enum, string(info.Code))
+	}
+
+	return schema
+}
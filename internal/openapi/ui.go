@@ -0,0 +1,18 @@
+package openapi
+
+import (
+	_ "embed"
+	"io/fs"
+
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// SwaggerUI is a page rendering /openapi.json with Swagger UI.
+//
+//go:embed swagger.html
+var SwaggerUI []byte
+
+// SwaggerUIAssets holds the stylesheet and scripts of Swagger UI. They are
+// embedded by the swaggo/files module, so go.sum pins their content and the
+// page loads nothing from third parties.
+var SwaggerUIAssets fs.FS = swaggerFiles.FS
@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/Alfian57/belajar-golang/internal/constants"
+	"github.com/Alfian57/belajar-golang/internal/database"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/internal/tenant"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EffectiveGroup is a group a user belongs to directly (depth 0) or through
+// one of its descendant groups (depth > 0).
+type EffectiveGroup struct {
+	model.Group
+	Depth int `json:"depth"`
+}
+
+type GroupRepository struct {
+	db *gorm.DB
+}
+
+func NewGroupRepository() *GroupRepository {
+	return &GroupRepository{db: database.DB}
+}
+
+// GetAll retrieves the groups of the current tenant ordered by name
+func (r *GroupRepository) GetAll(ctx context.Context) ([]model.Group, error) {
+	var groups []model.Group
+
+	err := r.db.WithContext(ctx).
+		Scopes(organizationScope(ctx, "groups")).
+		Order("name ASC").
+		Find(&groups).Error
+
+	return groups, err
+}
+
+func (r *GroupRepository) GetByID(ctx context.Context, id string) (model.Group, error) {
+	var group model.Group
+
+	err := r.db.WithContext(ctx).Scopes(organizationScope(ctx, "groups")).First(&group, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return group, errs.ErrGroupNotFound
+		}
+		return group, err
+	}
+
+	return group, nil
+}
+
+func (r *GroupRepository) GetByName(ctx context.Context, name string) (model.Group, error) {
+	var group model.Group
+
+	err := r.db.WithContext(ctx).Scopes(organizationScope(ctx, "groups")).First(&group, "name = ?", name).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return group, errs.ErrGroupNotFound
+		}
+		return group, err
+	}
+
+	return group, nil
+}
+
+// Create adds a group below its parent. The nesting is checked in the same
+// transaction, see checkNesting.
+func (r *GroupRepository) Create(ctx context.Context, group *model.Group) error {
+	group.ID = uuid.New()
+	if organization, ok := tenant.FromContext(ctx); ok {
+		group.OrganizationID = &organization.ID
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkNesting(ctx, tx, group, 1); err != nil {
+			return err
+		}
+
+		return tx.Create(group).Error
+	})
+}
+
+// Update saves the name, parent and role of a group. A move is checked in
+// the same transaction, see checkNesting.
+func (r *GroupRepository) Update(ctx context.Context, group *model.Group) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if group.ParentID != nil {
+			height, err := subtreeHeight(tx, group.ID)
+			if err != nil {
+				return err
+			}
+			if err := checkNesting(ctx, tx, group, height); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(group).Select("name", "parent_id", "role").Updates(group).Error
+	})
+}
+
+func (r *GroupRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Scopes(organizationScope(ctx, "groups")).Delete(&model.Group{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.ErrGroupNotFound
+	}
+
+	return nil
+}
+
+// checkNesting rejects placing a group of the given height below its parent
+// when the parent is the group or one of its descendants, or when the
+// hierarchy would get deeper than constants.MaxGroupDepth. The groups of the
+// tenant are locked first, so concurrent moves, such as A below B and B below
+// A, are checked one after the other.
+func checkNesting(ctx context.Context, tx *gorm.DB, group *model.Group, height int) error {
+	if group.ParentID == nil {
+		return nil
+	}
+
+	var locked []uuid.UUID
+	err := tx.Model(&model.Group{}).
+		Scopes(organizationScope(ctx, "groups")).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Order("id").
+		Pluck("id", &locked).Error
+	if err != nil {
+		return err
+	}
+
+	// The parent and its ancestors, one more than the limit so a too deep
+	// chain is noticed
+	var ancestors []uuid.UUID
+	err = tx.Raw(`
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, 1 AS level FROM groups WHERE id = ?
+			UNION ALL
+			SELECT groups.id, groups.parent_id, ancestors.level + 1
+			FROM groups
+			JOIN ancestors ON groups.id = ancestors.parent_id
+			WHERE ancestors.level <= ?
+		)
+		SELECT id FROM ancestors`, *group.ParentID, constants.MaxGroupDepth).
+		Scan(&ancestors).Error
+	if err != nil {
+		return err
+	}
+
+	if len(ancestors) == 0 {
+		fieldError := errs.NewFieldError("parent_id", errs.FieldNotFound, "parent group not found")
+		return errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+
+	if *group.ParentID == group.ID {
+		fieldError := errs.NewFieldError("parent_id", errs.FieldCycle, "group cannot be its own parent")
+		return errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+	if slices.Contains(ancestors, group.ID) {
+		fieldError := errs.NewFieldError("parent_id", errs.FieldCycle, "group cannot be nested below its own descendant")
+		return errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+
+	if len(ancestors)+height > constants.MaxGroupDepth {
+		message := fmt.Sprintf("groups can be nested at most %d levels deep", constants.MaxGroupDepth)
+		fieldError := errs.NewFieldError("parent_id", errs.FieldTooDeep, message).WithParam(strconv.Itoa(constants.MaxGroupDepth))
+		return errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+
+	return nil
+}
+
+// subtreeHeight returns the number of levels of the group and the groups
+// nested below it, at most one more than constants.MaxGroupDepth.
+func subtreeHeight(tx *gorm.DB, id uuid.UUID) (int, error) {
+	var height int
+
+	err := tx.Raw(`
+		WITH RECURSIVE descendants AS (
+			SELECT id, 1 AS level FROM groups WHERE id = ?
+			UNION ALL
+			SELECT groups.id, descendants.level + 1
+			FROM groups
+			JOIN descendants ON groups.parent_id = descendants.id
+			WHERE descendants.level <= ?
+		)
+		SELECT COALESCE(MAX(level), 1) FROM descendants`, id, constants.MaxGroupDepth).
+		Scan(&height).Error
+
+	return height, err
+}
+
+// GetEffectiveGroups returns the groups a user belongs to, including every
+// ancestor of a group the user is a direct member of
+func (r *GroupRepository) GetEffectiveGroups(ctx context.Context, userID uuid.UUID) ([]EffectiveGroup, error) {
+	var groups []EffectiveGroup
+
+	query := r.db.WithContext(ctx).
+		Table("(?) AS groups", r.effectiveGroupsQuery(userID)).
+		Scopes(organizationScope(ctx, "groups")).
+		Order("depth ASC, name ASC")
+
+	err := query.Find(&groups).Error
+	return groups, err
+}
+
+// HasGlobalRole reports whether one of the user's effective global groups grants the role
+func (r *GroupRepository) HasGlobalRole(ctx context.Context, userID uuid.UUID, role string) (bool, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Table("(?) AS groups", r.effectiveGroupsQuery(userID)).
+		Where("groups.organization_id IS NULL AND groups.role = ?", role).
+		Count(&count).Error
+
+	return count > 0, err
+}
+
+func (r *GroupRepository) GetMembers(ctx context.Context, groupID uuid.UUID) ([]model.GroupMember, error) {
+	var members []model.GroupMember
+
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("group_id = ?", groupID).
+		Order("created_at ASC").
+		Find(&members).Error
+
+	return members, err
+}
+
+// AddMember adds the user to the group, ignoring existing memberships
+func (r *GroupRepository) AddMember(ctx context.Context, member *model.GroupMember) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(member).Error
+}
+
+func (r *GroupRepository) RemoveMember(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&model.GroupMember{}, "group_id = ? AND user_id = ?", groupID, userID)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.ErrGroupMemberNotFound
+	}
+
+	return nil
+}
+
+func (r *GroupRepository) GetPermissions(ctx context.Context, groupIDs []uuid.UUID) ([]string, error) {
+	var permissions []string
+	if len(groupIDs) == 0 {
+		return permissions, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&model.GroupPermission{}).
+		Distinct("permission").
+		Where("group_id IN ?", groupIDs).
+		Order("permission ASC").
+		Pluck("permission", &permissions).Error
+
+	return permissions, err
+}
+
+// SetPermissions replaces the permissions granted to a group
+func (r *GroupRepository) SetPermissions(ctx context.Context, groupID uuid.UUID, permissions []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.GroupPermission{}, "group_id = ?", groupID).Error; err != nil {
+			return err
+		}
+
+		if len(permissions) == 0 {
+			return nil
+		}
+
+		grants := make([]model.GroupPermission, len(permissions))
+		for i, permission := range permissions {
+			grants[i] = model.GroupPermission{GroupID: groupID, Permission: permission}
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&grants).Error
+	})
+}
+
+func (r *GroupRepository) effectiveGroupsQuery(userID uuid.UUID) *gorm.DB {
+	return r.db.Raw(`
+		WITH RECURSIVE effective_groups AS (
+			SELECT groups.*, 0 AS depth
+			FROM groups
+			JOIN group_members ON group_members.group_id = groups.id
+			WHERE group_members.user_id = ?
+			UNION ALL
+			SELECT parent.*, effective_groups.depth + 1
+			FROM groups parent
+			JOIN effective_groups ON effective_groups.parent_id = parent.id
+			WHERE effective_groups.depth < ?
+		)
+		SELECT DISTINCT ON (id) * FROM effective_groups ORDER BY id, depth`, userID, constants.MaxGroupDepth)
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/database"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"gorm.io/gorm"
+)
+
+type ImpersonationSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewImpersonationSessionRepository() *ImpersonationSessionRepository {
+	return &ImpersonationSessionRepository{db: database.DB}
+}
+
+// Create stores a session. The ID is set by the caller because it is embedded in the token.
+func (r *ImpersonationSessionRepository) Create(ctx context.Context, session *model.ImpersonationSession) error {
+	err := r.db.WithContext(ctx).Create(session).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *ImpersonationSessionRepository) GetByID(ctx context.Context, id string) (model.ImpersonationSession, error) {
+	var session model.ImpersonationSession
+
+	err := r.db.WithContext(ctx).First(&session, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return session, errs.ErrImpersonationSessionNotFound
+		}
+		return session, err
+	}
+
+	return session, nil
+}
+
+func (r *ImpersonationSessionRepository) End(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.ImpersonationSession{}).
+		Where("id = ? AND ended_at IS NULL", id).
+		Update("ended_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.ErrImpersonationSessionNotFound
+	}
+
+	return nil
+}
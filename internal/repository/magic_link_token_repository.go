@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/database"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MagicLinkTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewMagicLinkTokenRepository() *MagicLinkTokenRepository {
+	return &MagicLinkTokenRepository{db: database.DB}
+}
+
+func (r *MagicLinkTokenRepository) Create(ctx context.Context, token *model.MagicLinkToken) error {
+	token.ID = uuid.New()
+
+	err := r.db.WithContext(ctx).Create(token).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *MagicLinkTokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (model.MagicLinkToken, error) {
+	var token model.MagicLinkToken
+
+	err := r.db.WithContext(ctx).First(&token, "token_hash = ?", tokenHash).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return token, errs.ErrMagicLinkInvalid
+		}
+		return token, err
+	}
+
+	return token, nil
+}
+
+// CountRecentByEmail returns how many links were issued to an address since the given time
+func (r *MagicLinkTokenRepository) CountRecentByEmail(ctx context.Context, email string, since time.Time) (int64, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&model.MagicLinkToken{}).
+		Where("email = ? AND created_at >= ?", email, since).
+		Count(&count).Error
+
+	return count, err
+}
+
+// MarkUsed consumes the token. Only the first caller succeeds, so a link can
+// never be redeemed twice even under concurrent requests.
+func (r *MagicLinkTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.MagicLinkToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.ErrMagicLinkInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/database"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrganizationInvitationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationInvitationRepository() *OrganizationInvitationRepository {
+	return &OrganizationInvitationRepository{db: database.DB}
+}
+
+func (r *OrganizationInvitationRepository) Create(ctx context.Context, invitation *model.OrganizationInvitation) error {
+	invitation.ID = uuid.New()
+
+	err := r.db.WithContext(ctx).Create(invitation).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *OrganizationInvitationRepository) GetByTokenHash(ctx context.Context, tokenHash string) (model.OrganizationInvitation, error) {
+	var invitation model.OrganizationInvitation
+
+	err := r.db.WithContext(ctx).Preload("Organization").First(&invitation, "token_hash = ?", tokenHash).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return invitation, errs.ErrInvitationNotFound
+		}
+		return invitation, err
+	}
+
+	return invitation, nil
+}
+
+// GetPendingByOrganizationID retrieves unanswered, unexpired invitations of an organization
+func (r *OrganizationInvitationRepository) GetPendingByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]model.OrganizationInvitation, error) {
+	var invitations []model.OrganizationInvitation
+
+	err := r.db.WithContext(ctx).
+		Where("organization_id = ? AND status = ? AND expires_at > ?", organizationID, model.InvitationStatusPending, time.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error
+
+	return invitations, err
+}
+
+// RevokePendingByEmail revokes earlier pending invitations so only the newest link works
+func (r *OrganizationInvitationRepository) RevokePendingByEmail(ctx context.Context, organizationID uuid.UUID, email string) error {
+	return r.db.WithContext(ctx).
+		Model(&model.OrganizationInvitation{}).
+		Where("organization_id = ? AND email = ? AND status = ?", organizationID, email, model.InvitationStatusPending).
+		Updates(map[string]any{"status": model.InvitationStatusRevoked, "responded_at": time.Now()}).Error
+}
+
+// UpdateStatus answers a pending invitation. Only the first answer is recorded.
+func (r *OrganizationInvitationRepository) UpdateStatus(ctx context.Context, organizationID uuid.UUID, id uuid.UUID, status string) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.OrganizationInvitation{}).
+		Where("id = ? AND organization_id = ? AND status = ?", id, organizationID, model.InvitationStatusPending).
+		Updates(map[string]any{"status": status, "responded_at": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.ErrInvitationNotFound
+	}
+
+	return nil
+}
+
+// Accept answers a pending invitation as accepted and adds the user to the
+// organization with the invited role, in one transaction. A user who is
+// already a member keeps their role.
+func (r *OrganizationInvitationRepository) Accept(ctx context.Context, invitation model.OrganizationInvitation, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.OrganizationInvitation{}).
+			Where("id = ? AND organization_id = ? AND status = ?", invitation.ID, invitation.OrganizationID, model.InvitationStatusPending).
+			Updates(map[string]any{"status": model.InvitationStatusAccepted, "responded_at": time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errs.ErrInvitationNotFound
+		}
+
+		member := &model.OrganizationMember{
+			ID:             uuid.New(),
+			OrganizationID: invitation.OrganizationID,
+			UserID:         userID,
+			Role:           invitation.Role,
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(member).Error
+	})
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Alfian57/belajar-golang/internal/database"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrganizationMemberRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationMemberRepository() *OrganizationMemberRepository {
+	return &OrganizationMemberRepository{db: database.DB}
+}
+
+func (r *OrganizationMemberRepository) Create(ctx context.Context, member *model.OrganizationMember) error {
+	member.ID = uuid.New()
+
+	err := r.db.WithContext(ctx).Create(member).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *OrganizationMemberRepository) Get(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID) (model.OrganizationMember, error) {
+	var member model.OrganizationMember
+
+	err := r.db.WithContext(ctx).First(&member, "organization_id = ? AND user_id = ?", organizationID, userID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return member, errs.ErrOrganizationMemberNotFound
+		}
+		return member, err
+	}
+
+	return member, nil
+}
+
+// GetAllByOrganizationID retrieves the members of an organization with their user data
+func (r *OrganizationMemberRepository) GetAllByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]model.OrganizationMember, error) {
+	var members []model.OrganizationMember
+
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("organization_id = ?", organizationID).
+		Order("created_at ASC").
+		Find(&members).Error
+
+	return members, err
+}
+
+// UpdateRole saves the role of a member. Demoting the last owner fails with
+// ErrLastOrganizationOwner.
+func (r *OrganizationMemberRepository) UpdateRole(ctx context.Context, member *model.OrganizationMember) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if member.Role != model.OrganizationRoleOwner {
+			if err := ensureAnotherOwner(tx, member.OrganizationID, member.UserID); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(member).Select("role").Updates(member).Error
+	})
+}
+
+// Delete removes a member. Removing the last owner fails with
+// ErrLastOrganizationOwner.
+func (r *OrganizationMemberRepository) Delete(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureAnotherOwner(tx, organizationID, userID); err != nil {
+			return err
+		}
+
+		result := tx.Delete(&model.OrganizationMember{}, "organization_id = ? AND user_id = ?", organizationID, userID)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errs.ErrOrganizationMemberNotFound
+		}
+
+		return nil
+	})
+}
+
+// ensureAnotherOwner fails with ErrLastOrganizationOwner when the user is the
+// only owner of the organization. The owner rows stay locked until the
+// transaction ends, so two owners demoting each other cannot both succeed.
+func ensureAnotherOwner(tx *gorm.DB, organizationID uuid.UUID, userID uuid.UUID) error {
+	var owners []uuid.UUID
+
+	err := tx.Model(&model.OrganizationMember{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("organization_id = ? AND role = ?", organizationID, model.OrganizationRoleOwner).
+		Order("user_id").
+		Pluck("user_id", &owners).Error
+	if err != nil {
+		return err
+	}
+
+	if len(owners) == 1 && owners[0] == userID {
+		return errs.ErrLastOrganizationOwner
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Alfian57/belajar-golang/internal/database"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type OrganizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository() *OrganizationRepository {
+	return &OrganizationRepository{db: database.DB}
+}
+
+// CreateWithOwner creates the organization and its first owner in one transaction
+func (r *OrganizationRepository) CreateWithOwner(ctx context.Context, organization *model.Organization, ownerID uuid.UUID) error {
+	organization.ID = uuid.New()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(organization).Error; err != nil {
+			return err
+		}
+
+		owner := &model.OrganizationMember{
+			ID:             uuid.New(),
+			OrganizationID: organization.ID,
+			UserID:         ownerID,
+			Role:           model.OrganizationRoleOwner,
+		}
+		return tx.Create(owner).Error
+	})
+}
+
+func (r *OrganizationRepository) GetByID(ctx context.Context, id string) (model.Organization, error) {
+	var organization model.Organization
+
+	err := r.db.WithContext(ctx).First(&organization, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return organization, errs.ErrOrganizationNotFound
+		}
+		return organization, err
+	}
+
+	return organization, nil
+}
+
+func (r *OrganizationRepository) GetBySlug(ctx context.Context, slug string) (model.Organization, error) {
+	var organization model.Organization
+
+	err := r.db.WithContext(ctx).First(&organization, "slug = ?", slug).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return organization, errs.ErrOrganizationNotFound
+		}
+		return organization, err
+	}
+
+	return organization, nil
+}
+
+// GetAllByUserID retrieves the organizations the user is a member of
+func (r *OrganizationRepository) GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]model.Organization, error) {
+	var organizations []model.Organization
+
+	err := r.db.WithContext(ctx).
+		Joins("JOIN organization_members ON organization_members.organization_id = organizations.id").
+		Where("organization_members.user_id = ?", userID).
+		Order("organizations.name ASC").
+		Find(&organizations).Error
+
+	return organizations, err
+}
+
+func (r *OrganizationRepository) Update(ctx context.Context, organization *model.Organization) error {
+	err := r.db.WithContext(ctx).Model(organization).Select("name", "slug").Updates(organization).Error
+	return err
+}
+
+func (r *OrganizationRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&model.Organization{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.ErrOrganizationNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Alfian57/belajar-golang/internal/database"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PasswordHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordHistoryRepository() *PasswordHistoryRepository {
+	return &PasswordHistoryRepository{db: database.DB}
+}
+
+func (r *PasswordHistoryRepository) Create(ctx context.Context, history *model.PasswordHistory) error {
+	history.ID = uuid.New()
+
+	err := r.db.WithContext(ctx).Create(history).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetLatestByUserID returns the most recent password hashes of a user, newest first
+func (r *PasswordHistoryRepository) GetLatestByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]model.PasswordHistory, error) {
+	var histories []model.PasswordHistory
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&histories).Error
+
+	return histories, err
+}
+
+// DeleteAllExceptLatest keeps only the newest entries of a user's history
+func (r *PasswordHistoryRepository) DeleteAllExceptLatest(ctx context.Context, userID uuid.UUID, keep int) error {
+	latest := r.db.Model(&model.PasswordHistory{}).
+		Select("id").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(keep)
+
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND id NOT IN (?)", userID, latest).
+		Delete(&model.PasswordHistory{}).Error
+}
@@ -36,7 +36,7 @@ func (r *RefreshTokenRepository) GetByTokenHash(ctx context.Context, token strin
 	err := r.db.WithContext(ctx).First(&refreshToken, "token_hash = ?", token).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return refreshToken, errs.ErrTokenNotFound
+			return refreshToken, errs.ErrRefreshTokenNotFound
 		}
 		return refreshToken, err
 	}
@@ -53,3 +53,7 @@ func (r *RefreshTokenRepository) DeleteByTokenHash(ctx context.Context, refreshT
 
 	return result.Error
 }
+
+func (r *RefreshTokenRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&model.RefreshToken{}, "user_id = ?", userID).Error
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Alfian57/belajar-golang/internal/listing"
+	"github.com/Alfian57/belajar-golang/internal/tenant"
+	"gorm.io/gorm"
+)
+
+// organizationUserScope limits a users query to members of the organization
+// resolved for the request. Without a tenant the query stays global.
+func organizationUserScope(ctx context.Context) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		organization, ok := tenant.FromContext(ctx)
+		if !ok {
+			return db
+		}
+
+		members := db.Session(&gorm.Session{NewDB: true}).
+			Table("organization_members").
+			Select("user_id").
+			Where("organization_id = ?", organization.ID)
+
+		return db.Where("users.id IN (?)", members)
+	}
+}
+
+// organizationScope limits a query on a table with an organization_id column
+// to the organization resolved for the request. Without a tenant only global
+// rows, those without an organization, are returned.
+func organizationScope(ctx context.Context, table string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		organization, ok := tenant.FromContext(ctx)
+		if !ok {
+			return db.Where(table + ".organization_id IS NULL")
+		}
+
+		return db.Where(table+".organization_id = ?", organization.ID)
+	}
+}
+
+// userSearchScope matches users whose username or email contains search,
+// ignoring case. The trigram indexes on both columns serve the pattern.
+func userSearchScope(search string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if search == "" {
+			return db
+		}
+
+		pattern := "%" + listing.EscapeLike(search) + "%"
+		return db.Where("username ILIKE ? OR email ILIKE ?", pattern, pattern)
+	}
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Alfian57/belajar-golang/internal/database"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type UserImportJobRepository struct {
+	db *gorm.DB
+}
+
+func NewUserImportJobRepository() *UserImportJobRepository {
+	return &UserImportJobRepository{db: database.DB}
+}
+
+func (r *UserImportJobRepository) Create(ctx context.Context, job *model.UserImportJob) error {
+	job.ID = uuid.New()
+
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+func (r *UserImportJobRepository) GetByID(ctx context.Context, id uuid.UUID) (model.UserImportJob, error) {
+	var job model.UserImportJob
+
+	err := r.db.WithContext(ctx).First(&job, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return job, errs.ErrImportJobNotFound
+		}
+		return job, err
+	}
+
+	return job, nil
+}
+
+// Update saves the status, counts and rows of a job.
+func (r *UserImportJobRepository) Update(ctx context.Context, job *model.UserImportJob) error {
+	return r.db.WithContext(ctx).Model(job).
+		Select("status", "processed_rows", "succeeded_rows", "failed_rows", "preview", "failures", "error", "finished_at").
+		Updates(job).Error
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Alfian57/belajar-golang/internal/database"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserPermissionRepository struct {
+	db *gorm.DB
+}
+
+func NewUserPermissionRepository() *UserPermissionRepository {
+	return &UserPermissionRepository{db: database.DB}
+}
+
+func (r *UserPermissionRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	var permissions []string
+
+	err := r.db.WithContext(ctx).
+		Model(&model.UserPermission{}).
+		Where("user_id = ?", userID).
+		Order("permission ASC").
+		Pluck("permission", &permissions).Error
+
+	return permissions, err
+}
+
+// SetPermissions replaces the permissions granted directly to a user
+func (r *UserPermissionRepository) SetPermissions(ctx context.Context, userID uuid.UUID, permissions []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.UserPermission{}, "user_id = ?", userID).Error; err != nil {
+			return err
+		}
+
+		if len(permissions) == 0 {
+			return nil
+		}
+
+		grants := make([]model.UserPermission, len(permissions))
+		for i, permission := range permissions {
+			grants[i] = model.UserPermission{UserID: userID, Permission: permission}
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&grants).Error
+	})
+}
@@ -3,9 +3,13 @@ package repository
 import (
 	"context"
 	"errors"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/Alfian57/belajar-golang/internal/database"
 	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/listing"
 	"github.com/Alfian57/belajar-golang/internal/logger"
 	"github.com/Alfian57/belajar-golang/internal/model"
 	"github.com/google/uuid"
@@ -20,21 +24,15 @@ func NewUserRepository() *UserRepository {
 	return &UserRepository{db: database.DB}
 }
 
-// GetAllWithFilterPagination retrieves users with optional filters, ordering, and pagination
-func (r *UserRepository) GetAllWithFilterPagination(ctx context.Context, search string, orderBy string, orderType string, limit int, offset int) ([]model.User, error) {
+// GetAllWithFilterPagination retrieves users with optional filters, ordering, and pagination.
+// When the request resolved an organization, only its members are returned.
+func (r *UserRepository) GetAllWithFilterPagination(ctx context.Context, search string, list listing.Query, limit int, offset int) ([]model.User, error) {
 	var users []model.User
 
-	query := r.db.WithContext(ctx)
+	query := r.db.WithContext(ctx).Scopes(organizationUserScope(ctx), userSearchScope(search), list.Where)
 
-	// Apply search filter
-	if search != "" {
-		query = query.Where("username LIKE ?", "%"+search+"%")
-	}
-
-	// Apply ordering
-	if orderBy != "" && orderType != "" {
-		query = query.Order(orderBy + " " + orderType)
-	}
+	// Apply ordering, the ID breaks ties so pages do not overlap
+	query = query.Scopes(list.Order)
 
 	// Apply limit and offset
 	if limit > 0 {
@@ -48,6 +46,78 @@ func (r *UserRepository) GetAllWithFilterPagination(ctx context.Context, search
 	return users, err
 }
 
+// GetAllWithFilterKeyset retrieves the users after the keyset in the order
+// of list, or before it when it is backward, in which case they come back
+// in reverse order. Unlike OFFSET it skips rows through the index and is
+// not shifted by concurrent inserts.
+func (r *UserRepository) GetAllWithFilterKeyset(ctx context.Context, search string, list listing.Query, keyset listing.Keyset, limit int) ([]model.User, error) {
+	var users []model.User
+
+	query := r.db.WithContext(ctx).Scopes(organizationUserScope(ctx), userSearchScope(search), list.Where)
+
+	query = query.Scopes(list.Seek(keyset))
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	err := query.Find(&users).Error
+	return users, err
+}
+
+// UserMatch is a user found by Search, with the relevance of the match.
+type UserMatch struct {
+	model.User
+	Rank float64
+}
+
+// Search ranks users by how well they match a to_tsquery prefix query, text
+// by trigram word similarity above threshold, or both. An empty query or
+// text leaves that half out. Both halves use the GIN indexes on users.
+func (r *UserRepository) Search(ctx context.Context, query string, text string, threshold float64, limit int) ([]UserMatch, error) {
+	var matches []UserMatch
+
+	var conditions, ranks []string
+	var conditionArgs, rankArgs []any
+	if query != "" {
+		conditions = append(conditions, "search_vector @@ to_tsquery('simple', ?)")
+		conditionArgs = append(conditionArgs, query)
+		ranks = append(ranks, "ts_rank_cd(search_vector, to_tsquery('simple', ?))")
+		rankArgs = append(rankArgs, query)
+	}
+	if text != "" {
+		conditions = append(conditions, "? <% username", "? <% email")
+		conditionArgs = append(conditionArgs, text, text)
+		ranks = append(ranks, "GREATEST(word_similarity(?, username), word_similarity(?, email))")
+		rankArgs = append(rankArgs, text, text)
+	}
+	if len(conditions) == 0 {
+		return matches, nil
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The <% operator reads its threshold from a setting, local to
+		// the transaction
+		if text != "" {
+			err := tx.Exec("SELECT set_config('pg_trgm.word_similarity_threshold', ?, true)", strconv.FormatFloat(threshold, 'f', -1, 64)).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&model.User{}).
+			Scopes(organizationUserScope(ctx)).
+			Select("users.*, "+strings.Join(ranks, " + ")+" AS rank", rankArgs...).
+			Where(strings.Join(conditions, " OR "), conditionArgs...).
+			Order("rank DESC").
+			Order("username").
+			Limit(limit).
+			Find(&matches).Error
+	})
+
+	return matches, err
+}
+
 // GetAll retrieves all users without any filters
 func (r *UserRepository) GetAll(ctx context.Context) ([]model.User, error) {
 	var users []model.User
@@ -56,15 +126,10 @@ func (r *UserRepository) GetAll(ctx context.Context) ([]model.User, error) {
 }
 
 // CountWithFilter returns the total number of users matching the search criteria
-func (r *UserRepository) CountWithFilter(ctx context.Context, search string) (int64, error) {
+func (r *UserRepository) CountWithFilter(ctx context.Context, search string, list listing.Query) (int64, error) {
 	var count int64
 
-	query := r.db.WithContext(ctx).Model(&model.User{})
-
-	// Apply search filter
-	if search != "" {
-		query = query.Where("username LIKE ?", "%"+search+"%")
-	}
+	query := r.db.WithContext(ctx).Model(&model.User{}).Scopes(organizationUserScope(ctx), userSearchScope(search), list.Where)
 
 	err := query.Count(&count).Error
 	if err != nil {
@@ -85,10 +150,23 @@ func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
 	user.ID = uuid.New()
 
 	err := r.db.WithContext(ctx).Create(user).Error
-	logger.Log.Debug(err)
+	logger.FromContext(ctx).Debug(err)
 	return err
 }
 
+// CreateMany creates users in one transaction, chunkSize rows per INSERT. An
+// email or username taken meanwhile is returned as a validation error.
+func (r *UserRepository) CreateMany(ctx context.Context, users []model.User, chunkSize int) error {
+	for i := range users {
+		users[i].ID = uuid.New()
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(&users, chunkSize).Error
+	})
+	return takenError(err)
+}
+
 func (r *UserRepository) GetByID(ctx context.Context, id string) (model.User, error) {
 	var user model.User
 
@@ -103,6 +181,13 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (model.User, er
 	return user, nil
 }
 
+// GetByIDs retrieves the users with the given IDs, missing ones are left out.
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]model.User, error) {
+	var users []model.User
+	err := r.db.WithContext(ctx).Find(&users, "id IN ?", ids).Error
+	return users, err
+}
+
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (model.User, error) {
 	var user model.User
 
@@ -136,8 +221,52 @@ func (r *UserRepository) Update(ctx context.Context, user *model.User) error {
 	return err
 }
 
+// UpdateMany updates the email and username of users in one transaction,
+// returning taken values like CreateMany.
+func (r *UserRepository) UpdateMany(ctx context.Context, users []model.User) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range users {
+			if err := tx.Model(&users[i]).Select("email", "username").Updates(&users[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return takenError(err)
+}
+
+// uniqueUserFields maps the unique constraints of users to their field.
+var uniqueUserFields = map[string]string{
+	"users_email_unique":    "email",
+	"users_username_unique": "username",
+}
+
+// takenError reports a value taken between validation and the write, such
+// as by a concurrent request, with the field error of the unique_* rules.
+func takenError(err error) error {
+	constraint, ok := violatedConstraint(err, uniqueViolation)
+	if !ok {
+		return err
+	}
+	field, ok := uniqueUserFields[constraint]
+	if !ok {
+		return err
+	}
+
+	fieldError := errs.NewFieldError(field, errs.FieldTaken, field+" already exists")
+	return errs.NewValidationError([]errs.FieldError{fieldError})
+}
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, user *model.User) error {
+	err := r.db.WithContext(ctx).Model(user).Select("password").Updates(user).Error
+	return err
+}
+
 func (r *UserRepository) Delete(ctx context.Context, id string) error {
 	result := r.db.WithContext(ctx).Delete(&model.User{}, "id = ?", id)
+	if _, ok := violatedConstraint(result.Error, foreignKeyViolation); ok {
+		return errs.ErrUserReferenced
+	}
 	if result.Error != nil {
 		return result.Error
 	}
@@ -148,3 +277,21 @@ func (r *UserRepository) Delete(ctx context.Context, id string) error {
 
 	return nil
 }
+
+// DeleteMany deletes the users with the given IDs in one transaction,
+// chunkSize IDs per DELETE. Like Delete it returns ErrUserReferenced when a
+// user cannot be deleted.
+func (r *UserRepository) DeleteMany(ctx context.Context, ids []uuid.UUID, chunkSize int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for chunk := range slices.Chunk(ids, chunkSize) {
+			if err := tx.Delete(&model.User{}, "id IN ?", chunk).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if _, ok := violatedConstraint(err, foreignKeyViolation); ok {
+		return errs.ErrUserReferenced
+	}
+	return err
+}
@@ -1,22 +1,31 @@
 package response
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/Alfian57/belajar-golang/internal/dto"
 	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/i18n"
+	"github.com/Alfian57/belajar-golang/internal/validation"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
 	"github.com/go-playground/validator/v10"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Response struct {
-	Success    bool   `json:"success"`
-	Message    string `json:"message,omitempty"`
-	Data       any    `json:"data,omitempty"`
-	Error      any    `json:"error,omitempty"`
-	Pagination any    `json:"pagination,omitempty"`
+	Success    bool      `json:"success"`
+	Message    string    `json:"message,omitempty"`
+	Data       any       `json:"data,omitempty"`
+	Code       errs.Code `json:"code,omitempty"`
+	Error      any       `json:"error,omitempty"`
+	Pagination any       `json:"pagination,omitempty"`
 }
 
 func WritePaginatedResponse[T any](ctx *gin.Context, statusCode int, result dto.PaginatedResult[T]) {
@@ -44,67 +53,156 @@ func WriteMessageResponse(ctx *gin.Context, statusCode int, message string) {
 	})
 }
 
+// WriteBulkResponse renders the error of each failed item like
+// WriteErrorResponse does. Atomic operations with failed items wrote
+// nothing and are answered with 422 and the bulk_failed code.
+func WriteBulkResponse(ctx *gin.Context, result dto.BulkResult) {
+	for i, item := range result.Items {
+		if item.Err == nil {
+			continue
+		}
+		result.Items[i].Code, result.Items[i].Message, result.Items[i].Errors = Describe(ctx.Request.Context(), item.Err)
+	}
+
+	ctx.Header("Content-Type", "application/json")
+	if result.Atomic() && result.Failed > 0 {
+		message := i18n.T(ctx.Request.Context(), "errors."+string(errs.CodeBulkFailed), "bulk operation failed, nothing was written", nil)
+		ctx.JSON(http.StatusUnprocessableEntity, Response{
+			Success: false,
+			Code:    errs.CodeBulkFailed,
+			Error:   message,
+			Data:    result,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    result,
+	})
+}
+
+// ProblemContentType is the media type of RFC 9457 problem details. Clients
+// that list it in Accept get errors in that shape instead of the envelope.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 9457 problem details object. Type points at the catalog
+// entry of Code, Errors holds the field errors of validation failures.
+type Problem struct {
+	Type      string            `json:"type"`
+	Title     string            `json:"title"`
+	Status    int               `json:"status"`
+	Detail    string            `json:"detail,omitempty"`
+	Instance  string            `json:"instance,omitempty"`
+	Code      errs.Code         `json:"code"`
+	RequestID string            `json:"request_id,omitempty"`
+	Errors    []errs.FieldError `json:"errors,omitempty"`
+}
+
+// ProblemType returns the type URI of a code, served by /problems/{code}.
+func ProblemType(code errs.Code) string {
+	return "/problems/" + string(code)
+}
+
 // Improved error response handler
 func WriteErrorResponse(ctx *gin.Context, err error) {
+	status, code, message, fieldErrors := classify(ctx.Request.Context(), err)
+	message = i18n.T(ctx.Request.Context(), "errors."+string(code), message, nil)
+
+	if ctx.NegotiateFormat(gin.MIMEJSON, ProblemContentType) == ProblemContentType {
+		title := http.StatusText(status)
+		if info, ok := errs.Lookup(code); ok {
+			title = info.Title
+		}
+
+		ctx.Header("Content-Type", ProblemContentType)
+		ctx.Render(status, render.JSON{Data: Problem{
+			Type:      ProblemType(code),
+			Title:     title,
+			Status:    status,
+			Detail:    message,
+			Instance:  ctx.Request.URL.Path,
+			Code:      code,
+			RequestID: ctx.GetString("request_id"),
+			Errors:    fieldErrors,
+		}})
+		return
+	}
+
 	ctx.Header("Content-Type", "application/json")
 
+	var body any = message
+	if fieldErrors != nil {
+		body = fieldErrors
+	}
+	ctx.JSON(status, Response{
+		Success: false,
+		Code:    code,
+		Error:   body,
+	})
+}
+
+// Describe returns the code, translated message and field errors clients get
+// for err, for errors reported inside a response rather than as one, like
+// the failed items of bulk operations.
+func Describe(ctx context.Context, err error) (errs.Code, string, []errs.FieldError) {
+	_, code, message, fieldErrors := classify(ctx, err)
+	return code, i18n.T(ctx, "errors."+string(code), message, nil), fieldErrors
+}
+
+// classify maps an error to its status, code, client facing message and
+// field errors.
+func classify(ctx context.Context, err error) (int, errs.Code, string, []errs.FieldError) {
 	// Handle custom AppError
 	var appErr *errs.AppError
 	if errors.As(err, &appErr) {
-		ctx.JSON(appErr.Code, Response{
-			Success: false,
-			Error:   appErr.Message,
-		})
-		return
+		// Keep the cause of server errors on the request span, the client
+		// only sees the message
+		if appErr.Code >= http.StatusInternalServerError {
+			trace.SpanFromContext(ctx).RecordError(appErr)
+		}
+		return appErr.Code, appErr.GetErrorCode(), appErr.Message, nil
 	}
 
 	// Handle validation errors
 	var validationErr *errs.ValidationError
 	if errors.As(err, &validationErr) {
-		ctx.JSON(http.StatusUnprocessableEntity, Response{
-			Success: false,
-			Error:   validationErr.Errors,
-		})
-		return
+		return http.StatusUnprocessableEntity, errs.CodeValidationFailed, "validation failed", translateFieldErrors(ctx, validationErr.Errors)
 	}
 
 	// Handle gin validation errors
 	var ginValidationErr validator.ValidationErrors
 	if errors.As(err, &ginValidationErr) {
-		fieldErrors := make([]errs.FieldError, len(ginValidationErr))
-		for i, fe := range ginValidationErr {
-			fieldErrors[i] = errs.FieldError{
-				Field: toSnakeCase(fe.Field()),
-				Error: validationMessage(fe),
+		fieldErrors := make([]errs.FieldError, 0, len(ginValidationErr))
+		for _, fe := range ginValidationErr {
+			if explained := validation.Explain(fe); explained != nil {
+				fieldErrors = append(fieldErrors, translateFieldErrors(ctx, explained)...)
+				continue
 			}
+			fieldErrors = append(fieldErrors, validationError(ctx, fe))
 		}
-		ctx.JSON(http.StatusUnprocessableEntity, Response{
-			Success: false,
-			Error:   fieldErrors,
-		})
-		return
+		return http.StatusUnprocessableEntity, errs.CodeValidationFailed, "validation failed", fieldErrors
+	}
+
+	// Handle request bodies and parameters that cannot be decoded
+	if isMalformed(err) {
+		return http.StatusBadRequest, errs.CodeBadRequest, "malformed request", nil
 	}
 
 	// Default error response
-	ctx.JSON(http.StatusInternalServerError, Response{
-		Success: false,
-		Error:   "internal server error",
-	})
+	trace.SpanFromContext(ctx).RecordError(err)
+	return http.StatusInternalServerError, errs.CodeInternal, "internal server error", nil
 }
 
-func validationMessage(fe validator.FieldError) string {
-	switch fe.Tag() {
-	case "required":
-		return toSnakeCase(fe.Field()) + " is required"
-	case "min":
-		return toSnakeCase(fe.Field()) + " must be at least " + fe.Param() + " characters"
-	case "max":
-		return toSnakeCase(fe.Field()) + " must be at most " + fe.Param() + " characters"
-	case "eqfield":
-		return toSnakeCase(fe.Field()) + " must be equal to " + toSnakeCase(fe.Param())
-	default:
-		return toSnakeCase(fe.Field()) + " is invalid"
-	}
+func isMalformed(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var numErr *strconv.NumError
+	return errors.As(err, &syntaxErr) ||
+		errors.As(err, &typeErr) ||
+		errors.As(err, &numErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
 }
 
 func toSnakeCase(str string) string {
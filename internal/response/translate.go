@@ -0,0 +1,87 @@
+package response
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/i18n"
+	"github.com/Alfian57/belajar-golang/internal/validation"
+	"github.com/go-playground/validator/v10"
+)
+
+// translateFieldErrors localizes the field errors returned by services by
+// their code, the messages of services are the English ones.
+func translateFieldErrors(ctx context.Context, fieldErrors []errs.FieldError) []errs.FieldError {
+	// Copy, the slice belongs to the error
+	translated := make([]errs.FieldError, len(fieldErrors))
+	for i, fieldError := range fieldErrors {
+		if fieldError.Code != "" {
+			fieldError.Error = i18n.T(ctx, "validation."+fieldError.Code, fieldError.Error, map[string]string{
+				"field": fieldLabel(ctx, fieldError.Field),
+				"param": fieldError.Param,
+			})
+		}
+		translated[i] = fieldError
+	}
+
+	return translated
+}
+
+// validationError builds the field error of a failed validator tag with the
+// message of the tag, specific to the kind of the field when the bundle has
+// one, e.g. validation.min.items for slices.
+func validationError(ctx context.Context, fe validator.FieldError) errs.FieldError {
+	field := toSnakeCase(fe.Field())
+	fieldError := errs.FieldError{Field: field, Code: validation.Code(fe), Param: fe.Param()}
+
+	key := "validation." + fieldError.Code
+	switch {
+	case i18n.Defined(key + "." + kindOf(fe.Kind())):
+		key += "." + kindOf(fe.Kind())
+	case !i18n.Defined(key):
+		key = "validation.default"
+	}
+
+	fieldError.Error = i18n.T(ctx, key, "", map[string]string{
+		"field": fieldLabel(ctx, field),
+		"param": validationParam(ctx, fe),
+	})
+
+	return fieldError
+}
+
+// validationParam formats the parameter of a tag for messages. Tags comparing
+// fields name another field, oneof lists values.
+func validationParam(ctx context.Context, fe validator.FieldError) string {
+	tag := fe.Tag()
+	switch {
+	case strings.HasSuffix(tag, "field"), strings.HasPrefix(tag, "required_with"), tag == "excluded_with":
+		return fieldLabel(ctx, toSnakeCase(fe.Param()))
+	case tag == "oneof":
+		return strings.Join(strings.Fields(fe.Param()), ", ")
+	default:
+		return fe.Param()
+	}
+}
+
+// fieldLabel returns the name of a field in the locale of ctx.
+func fieldLabel(ctx context.Context, field string) string {
+	return i18n.T(ctx, "fields."+field, field, nil)
+}
+
+func kindOf(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,105 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/Alfian57/belajar-golang/internal/config"
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/health"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/internal/openapi"
+	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/Alfian57/belajar-golang/internal/utils/spreadsheet"
+)
+
+var apiInfo = openapi.Info{
+	Title:   "belajar-golang API",
+	Version: "1.0.0",
+}
+
+// Document builds the OpenAPI document of the routes. Every route registered
+// in NewRouter must be described in Routes, `make openapi-check` fails
+// otherwise.
+func Document() (*openapi.Document, error) {
+	return openapi.Generate(apiInfo, Routes())
+}
+
+// Routes describes the routes registered in NewRouter and RegisterV1Route.
+func Routes() []openapi.Route {
+	const v1 = "/api/v1"
+
+	return []openapi.Route{
+		// Operations
+		{Method: http.MethodGet, Path: "/healthz", ID: "live", Tag: "operations", Summary: "Liveness probe", Raw: health.Report{}},
+		{Method: http.MethodGet, Path: "/readyz", ID: "ready", Tag: "operations", Summary: "Readiness probe, 503 while a check fails or the server is shutting down", Raw: health.Report{}},
+		{Method: http.MethodGet, Path: "/metrics", ID: "metrics", Tag: "operations", Summary: "Prometheus metrics, behind METRICS_TOKEN when it is set", Security: []openapi.SecurityRequirement{{openapi.BearerAuth: {}}}, Raw: "", ContentType: "text/plain"},
+		{Method: http.MethodGet, Path: "/openapi.json", ID: "openapi", Tag: "operations", Summary: "This document", Raw: map[string]any{}},
+		{Method: http.MethodGet, Path: "/docs", ID: "docs", Tag: "operations", Summary: "Swagger UI", Raw: "", ContentType: "text/html"},
+		{Method: http.MethodGet, Path: "/docs/assets/:file", ID: "docsAsset", Tag: "operations", Summary: "Stylesheet or script of Swagger UI", Raw: "", ContentType: "text/css, text/javascript", Errors: []int{http.StatusNotFound}},
+		{Method: http.MethodGet, Path: "/problems", ID: "listProblems", Tag: "operations", Summary: "Catalog of error codes", Raw: []errs.CodeInfo{}},
+		{Method: http.MethodGet, Path: "/problems/:code", ID: "getProblem", Tag: "operations", Summary: "Catalog entry of an error code, the type of problem responses", Raw: errs.CodeInfo{}, Errors: []int{http.StatusNotFound}},
+
+		// Authentication
+		{Method: http.MethodPost, Path: v1 + "/login", ID: "login", Tag: "auth", Summary: "Log in, setting the access_token and refresh_token cookies", Body: dto.LoginRequest{}},
+		{Method: http.MethodPost, Path: v1 + "/register", ID: "register", Tag: "auth", Summary: "Register a user", Body: dto.RegisterRequest{}, Status: http.StatusCreated},
+		{Method: http.MethodPost, Path: v1 + "/magic-link", ID: "requestMagicLink", Tag: "auth", Summary: "Email a sign-in link", Body: dto.MagicLinkRequest{}},
+		{Method: http.MethodGet, Path: v1 + "/magic-link/verify", ID: "verifyMagicLink", Tag: "auth", Summary: "Log in with a sign-in link", Query: dto.MagicLinkVerifyRequest{}},
+		{Method: http.MethodPost, Path: v1 + "/refresh", ID: "refresh", Tag: "auth", Summary: "Rotate the access and refresh tokens", Security: openapi.RefreshCookie, Errors: []int{http.StatusForbidden}},
+		{Method: http.MethodPost, Path: v1 + "/logout", ID: "logout", Tag: "auth", Summary: "Log out and revoke the refresh token", Security: openapi.RefreshCookie, Errors: []int{http.StatusForbidden}},
+		{Method: http.MethodPut, Path: v1 + "/password", ID: "changePassword", Tag: "auth", Summary: "Change the password, not allowed while impersonating", Security: openapi.Authenticated, Body: dto.ChangePasswordRequest{}, Errors: []int{http.StatusForbidden}},
+		{Method: http.MethodDelete, Path: v1 + "/impersonation", ID: "stopImpersonation", Tag: "impersonation", Summary: "End the current impersonation session", Security: openapi.Authenticated, Errors: []int{http.StatusNotFound}},
+
+		// Organizations
+		{Method: http.MethodGet, Path: v1 + "/organizations/", ID: "listMyOrganizations", Tag: "organizations", Summary: "List the organizations of the current user", Security: openapi.Authenticated, Data: []model.Organization{}},
+		{Method: http.MethodPost, Path: v1 + "/organizations/", ID: "createOrganization", Tag: "organizations", Summary: "Create an organization owned by the current user", Security: openapi.Authenticated, Body: dto.CreateOrganizationRequest{}, Status: http.StatusCreated, Data: model.Organization{}, Errors: []int{http.StatusForbidden}},
+		{Method: http.MethodGet, Path: v1 + "/organizations/:organization", ID: "getOrganization", Tag: "organizations", Summary: "Get an organization by ID or slug", Security: openapi.Authenticated, Data: model.Organization{}, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: http.MethodPut, Path: v1 + "/organizations/:organization", ID: "updateOrganization", Tag: "organizations", Summary: "Update an organization, for owners and admins", Security: openapi.Authenticated, Body: dto.UpdateOrganizationRequest{}, Data: model.Organization{}, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: http.MethodDelete, Path: v1 + "/organizations/:organization", ID: "deleteOrganization", Tag: "organizations", Summary: "Delete an organization, for owners", Security: openapi.Authenticated, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: http.MethodGet, Path: v1 + "/organizations/:organization/members", ID: "listOrganizationMembers", Tag: "organizations", Summary: "List the members of an organization", Security: openapi.Authenticated, Data: []model.OrganizationMember{}, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: http.MethodPost, Path: v1 + "/organizations/:organization/leave", ID: "leaveOrganization", Tag: "organizations", Summary: "Leave an organization", Security: openapi.Authenticated, Errors: []int{http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity}},
+		{Method: http.MethodPut, Path: v1 + "/organizations/:organization/members/:user_id", ID: "updateOrganizationMember", Tag: "organizations", Summary: "Change the role of a member", Security: openapi.Authenticated, Body: dto.UpdateOrganizationMemberRequest{}, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: http.MethodDelete, Path: v1 + "/organizations/:organization/members/:user_id", ID: "removeOrganizationMember", Tag: "organizations", Summary: "Remove a member", Security: openapi.Authenticated, Errors: []int{http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity}},
+		{Method: http.MethodGet, Path: v1 + "/organizations/:organization/invitations", ID: "listInvitations", Tag: "organizations", Summary: "List pending invitations", Security: openapi.Authenticated, Data: []model.OrganizationInvitation{}, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: http.MethodPost, Path: v1 + "/organizations/:organization/invitations", ID: "createInvitation", Tag: "organizations", Summary: "Invite someone by email", Security: openapi.Authenticated, Body: dto.CreateInvitationRequest{}, Status: http.StatusCreated, Data: model.OrganizationInvitation{}, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: http.MethodDelete, Path: v1 + "/organizations/:organization/invitations/:invitation_id", ID: "revokeInvitation", Tag: "organizations", Summary: "Revoke an invitation", Security: openapi.Authenticated, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+
+		// Invitations
+		{Method: http.MethodGet, Path: v1 + "/invitations/:token", ID: "getInvitation", Tag: "invitations", Summary: "Get an invitation by its token", Data: model.OrganizationInvitation{}, Errors: []int{http.StatusNotFound}},
+		{Method: http.MethodPost, Path: v1 + "/invitations/:token/accept", ID: "acceptInvitation", Tag: "invitations", Summary: "Accept an invitation as the current user", Security: openapi.Authenticated, Data: model.Organization{}, Errors: []int{http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity}},
+		{Method: http.MethodPost, Path: v1 + "/invitations/:token/decline", ID: "declineInvitation", Tag: "invitations", Summary: "Decline an invitation", Errors: []int{http.StatusNotFound, http.StatusUnprocessableEntity}},
+
+		// Administration
+		{Method: http.MethodGet, Path: v1 + "/admin/users/", ID: "listUsers", Tag: "users", Summary: "List users, limited to the members of the organization for organization admins", Security: openapi.Authenticated, Query: dto.GetUsersFilter{}, Data: model.User{}, Paginated: true, Errors: []int{http.StatusForbidden}},
+		{Method: http.MethodGet, Path: v1 + "/admin/users/search", ID: "searchUsers", Tag: "users", Summary: "Search users by username and email, best matches first, tolerating typos", Security: openapi.Authenticated, Query: dto.SearchUsersRequest{}, Data: []dto.UserSearchResult{}, Errors: []int{http.StatusForbidden}},
+		{Method: http.MethodGet, Path: v1 + "/admin/users/export", ID: "exportUsers", Tag: "users", Summary: "Download the users of a listing as a CSV or XLSX file", Security: openapi.Authenticated, Query: dto.ExportUsersRequest{}, Raw: "", ContentType: spreadsheet.ContentType(spreadsheet.CSV) + ", " + spreadsheet.ContentType(spreadsheet.XLSX), Errors: []int{http.StatusForbidden}},
+		{Method: http.MethodPost, Path: v1 + "/admin/users/", ID: "createUser", Tag: "users", Summary: "Create a user", Security: openapi.Authenticated, Body: dto.CreateUserRequest{}, Status: http.StatusCreated, Errors: []int{http.StatusForbidden}},
+		{Method: http.MethodPost, Path: v1 + "/admin/users/bulk", ID: "bulkCreateUsers", Tag: "users", Summary: "Create many users, all or none in atomic mode, with a result per user", Security: openapi.Authenticated, Body: dto.BulkCreateUsersRequest{}, Data: dto.BulkResult{}, Errors: []int{http.StatusForbidden}},
+		{Method: http.MethodPut, Path: v1 + "/admin/users/bulk", ID: "bulkUpdateUsers", Tag: "users", Summary: "Update many users by ID, all or none in atomic mode, with a result per user", Security: openapi.Authenticated, Body: dto.BulkUpdateUsersRequest{}, Data: dto.BulkResult{}, Errors: []int{http.StatusForbidden}},
+		{Method: http.MethodDelete, Path: v1 + "/admin/users/bulk", ID: "bulkDeleteUsers", Tag: "users", Summary: "Delete many users by ID, all or none in atomic mode, with a result per user", Security: openapi.Authenticated, Body: dto.BulkDeleteUsersRequest{}, Data: dto.BulkResult{}, Errors: []int{http.StatusForbidden, http.StatusConflict}},
+		{Method: http.MethodPost, Path: v1 + "/admin/users/import", ID: "importUsers", Tag: "users", Summary: "Import users from a CSV or XLSX file, with a dry run to preview the outcome of each row", Description: "Files of up to IMPORT_SYNC_ROWS rows are imported before the response, which is 200 with the finished job. Larger files are imported by a background job, answered with 202 and the pending job to poll.", Security: openapi.Authenticated, Body: dto.ImportUsersRequest{}, Upload: true, Data: model.UserImportJob{}, Errors: []int{http.StatusForbidden}},
+		{Method: http.MethodGet, Path: v1 + "/admin/users/import/:id", ID: "getUserImportJob", Tag: "users", Summary: "Get an import job with its progress and failed rows", Security: openapi.Authenticated, Data: model.UserImportJob{}, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: http.MethodGet, Path: v1 + "/admin/users/:id", ID: "getUser", Tag: "users", Summary: "Get a user", Security: openapi.Authenticated, Data: model.User{}, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: http.MethodPut, Path: v1 + "/admin/users/:id", ID: "updateUser", Tag: "users", Summary: "Update a user", Security: openapi.Authenticated, Body: dto.UpdateUserRequest{}, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: http.MethodDelete, Path: v1 + "/admin/users/:id", ID: "deleteUser", Tag: "users", Summary: "Delete a user", Description: "Users that took part in an impersonation session cannot be deleted, so the audit trail is kept. Ban them instead.", Security: openapi.Authenticated, Errors: []int{http.StatusForbidden, http.StatusNotFound, http.StatusConflict}},
+		{Method: http.MethodGet, Path: v1 + "/admin/users/:id/groups", ID: "listUserGroups", Tag: "groups", Summary: "List the groups a user belongs to, with inherited ones", Security: openapi.Authenticated, Data: []repository.EffectiveGroup{}, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: http.MethodGet, Path: v1 + "/admin/users/:id/permissions", ID: "getUserPermissions", Tag: "groups", Summary: "Get the effective roles and permissions of a user", Description: "Within an organization only the roles and permissions granted by its groups are listed, the role and direct permissions of the user are global.", Security: openapi.Authenticated, Data: dto.EffectivePermissions{}, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: http.MethodPut, Path: v1 + "/admin/users/:id/permissions", ID: "setUserPermissions", Tag: "groups", Summary: "Replace the direct permissions of a user, which are global", Security: openapi.Authenticated, Body: dto.SetPermissionsRequest{}, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: http.MethodPost, Path: v1 + "/admin/users/:id/impersonate", ID: "startImpersonation", Tag: "impersonation", Summary: "Start acting as a user", Security: openapi.Authenticated, Body: dto.ImpersonateRequest{}, Status: http.StatusCreated, Data: dto.ImpersonationResponse{}, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: http.MethodGet, Path: v1 + "/admin/config", ID: "getConfig", Tag: "operations", Summary: "Show the effective configuration with secrets redacted", Security: openapi.Authenticated, Data: []config.Setting{}, Errors: []int{http.StatusForbidden}},
+		{Method: http.MethodGet, Path: v1 + "/admin/log-levels", ID: "getLogLevels", Tag: "operations", Summary: "Show the log levels", Security: openapi.Authenticated, Data: logger.Levels{}, Errors: []int{http.StatusForbidden}},
+		{Method: http.MethodPut, Path: v1 + "/admin/log-levels", ID: "updateLogLevels", Tag: "operations", Summary: "Change log levels until the next restart or config reload", Security: openapi.Authenticated, Body: dto.UpdateLogLevelsRequest{}, Data: logger.Levels{}, Errors: []int{http.StatusForbidden}},
+
+		// Groups
+		{Method: http.MethodGet, Path: v1 + "/admin/groups/", ID: "listGroups", Tag: "groups", Summary: "List groups", Security: openapi.Authenticated, Data: []model.Group{}, Errors: []int{http.StatusForbidden}},
+		{Method: http.MethodPost, Path: v1 + "/admin/groups/", ID: "createGroup", Tag: "groups", Summary: "Create a group", Security: openapi.Authenticated, Body: dto.CreateGroupRequest{}, Status: http.StatusCreated, Data: model.Group{}, Errors: []int{http.StatusForbidden}},
+		{Method: http.MethodGet, Path: v1 + "/admin/groups/:id", ID: "getGroup", Tag: "groups", Summary: "Get a group", Security: openapi.Authenticated, Data: model.Group{}, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: http.MethodPut, Path: v1 + "/admin/groups/:id", ID: "updateGroup", Tag: "groups", Summary: "Update a group", Security: openapi.Authenticated, Body: dto.UpdateGroupRequest{}, Data: model.Group{}, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: http.MethodDelete, Path: v1 + "/admin/groups/:id", ID: "deleteGroup", Tag: "groups", Summary: "Delete a group", Security: openapi.Authenticated, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: http.MethodGet, Path: v1 + "/admin/groups/:id/members", ID: "listGroupMembers", Tag: "groups", Summary: "List the members of a group", Security: openapi.Authenticated, Data: []model.GroupMember{}, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: http.MethodPost, Path: v1 + "/admin/groups/:id/members", ID: "addGroupMember", Tag: "groups", Summary: "Add a member to a group", Security: openapi.Authenticated, Body: dto.AddGroupMemberRequest{}, Status: http.StatusCreated, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: http.MethodDelete, Path: v1 + "/admin/groups/:id/members/:user_id", ID: "removeGroupMember", Tag: "groups", Summary: "Remove a member from a group", Security: openapi.Authenticated, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: http.MethodPut, Path: v1 + "/admin/groups/:id/permissions", ID: "setGroupPermissions", Tag: "groups", Summary: "Replace the permissions of a group", Security: openapi.Authenticated, Body: dto.SetPermissionsRequest{}, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+	}
+}
@@ -0,0 +1,44 @@
+package router_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/Alfian57/belajar-golang/internal/router"
+	"github.com/gin-gonic/gin"
+)
+
+// TestRoutesDocumented fails when a registered route is not described in
+// router.Routes, or a described route is not registered.
+func TestRoutesDocumented(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	registered := make(map[string]bool)
+	for _, route := range router.NewRouter().Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	described := make(map[string]bool)
+	for _, route := range router.Routes() {
+		described[route.Key()] = true
+	}
+
+	for _, key := range difference(registered, described) {
+		t.Errorf("undocumented route: %s", key)
+	}
+	for _, key := range difference(described, registered) {
+		t.Errorf("documented route is not registered: %s", key)
+	}
+}
+
+func difference(a, b map[string]bool) []string {
+	var keys []string
+	for key := range a {
+		if !b[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -1,11 +1,43 @@
 package router
 
 import (
+	"fmt"
+
+	"github.com/Alfian57/belajar-golang/internal/di"
+	"github.com/Alfian57/belajar-golang/internal/handler"
+	"github.com/Alfian57/belajar-golang/internal/metrics"
+	"github.com/Alfian57/belajar-golang/internal/middleware"
 	"github.com/gin-gonic/gin"
 )
 
-func NewRouter() *gin.Engine {
+// NewRouter registers the routes behind the given global middleware. Gin
+// builds each route's handler chain when it is registered, so middleware must
+// be added before the routes.
+func NewRouter(globalMiddleware ...gin.HandlerFunc) *gin.Engine {
 	router := gin.New()
+	// Let values of the request context, such as the trace span, be found
+	// through the *gin.Context handlers pass to services
+	router.ContextWithFallback = true
+	router.Use(globalMiddleware...)
+
+	healthHandler := di.InitializeHealthHandler()
+	router.GET("/healthz", healthHandler.Live)
+	router.GET("/readyz", healthHandler.Ready)
+	router.GET("/metrics", middleware.MetricsAuthMiddleware(), gin.WrapH(metrics.Handler()))
+
+	document, err := Document()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to generate OpenAPI document: %v", err))
+	}
+	docsHandler, err := handler.NewDocsHandler(document)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to encode OpenAPI document: %v", err))
+	}
+	router.GET("/openapi.json", docsHandler.Spec)
+	router.GET("/docs", docsHandler.UI)
+	router.GET("/docs/assets/:file", docsHandler.Asset)
+	router.GET("/problems", docsHandler.Problems)
+	router.GET("/problems/:code", docsHandler.Problem)
 
 	api := router.Group("api")
 
@@ -3,6 +3,7 @@ package router
 import (
 	"github.com/Alfian57/belajar-golang/internal/di"
 	"github.com/Alfian57/belajar-golang/internal/middleware"
+	"github.com/Alfian57/belajar-golang/internal/model"
 	"github.com/gin-gonic/gin"
 )
 
@@ -10,20 +11,98 @@ func RegisterV1Route(router *gin.RouterGroup) {
 
 	authHandler := di.InitializeAuthHandler()
 	userHandler := di.InitializeUserHandler()
+	userImportHandler := di.InitializeUserImportHandler()
+	impersonationHandler := di.InitializeImpersonationHandler()
+	organizationHandler := di.InitializeOrganizationHandler()
+	groupHandler := di.InitializeGroupHandler()
+	configHandler := di.InitializeConfigHandler()
+	logHandler := di.InitializeLogHandler()
 
 	router.POST("/login", authHandler.Login)
 	router.POST("/register", authHandler.Register)
-	router.POST("/refresh", middleware.AuthMiddleware(), authHandler.Refresh)
-	router.POST("/logout", middleware.AuthMiddleware(), authHandler.Logout)
+	router.POST("/magic-link", authHandler.RequestMagicLink)
+	router.GET("/magic-link/verify", authHandler.VerifyMagicLink)
 
-	admin := router.Group("admin", middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	// Impersonation is for looking at the account of a member, changes need
+	// their own session. Only ending the impersonation is allowed
+	noImpersonation := middleware.NoImpersonationMiddleware()
+
+	router.POST("/refresh", middleware.AuthMiddleware(), noImpersonation, authHandler.Refresh)
+	router.POST("/logout", middleware.AuthMiddleware(), noImpersonation, authHandler.Logout)
+
+	router.PUT("/password", middleware.AuthMiddleware(), noImpersonation, authHandler.ChangePassword)
+	router.DELETE("/impersonation", middleware.AuthMiddleware(), impersonationHandler.Stop)
+
+	organizations := router.Group("organizations", middleware.AuthMiddleware())
+	{
+		organizations.GET("/", organizationHandler.GetMyOrganizations)
+		organizations.POST("/", noImpersonation, organizationHandler.CreateOrganization)
+
+		organization := organizations.Group("/:organization", middleware.TenantMiddleware())
+		organization.GET("", organizationHandler.GetOrganization)
+		organization.GET("/members", organizationHandler.GetMembers)
+		organization.POST("/leave", noImpersonation, organizationHandler.Leave)
+		organization.DELETE("", middleware.OrganizationRoleMiddleware(model.OrganizationRoleOwner), noImpersonation, organizationHandler.DeleteOrganization)
+
+		manage := organization.Group("", middleware.OrganizationRoleMiddleware(model.OrganizationRoleOwner, model.OrganizationRoleAdmin))
+		manage.PUT("", noImpersonation, organizationHandler.UpdateOrganization)
+		manage.PUT("/members/:user_id", noImpersonation, organizationHandler.UpdateMember)
+		manage.DELETE("/members/:user_id", noImpersonation, organizationHandler.RemoveMember)
+		manage.GET("/invitations", organizationHandler.GetInvitations)
+		manage.POST("/invitations", noImpersonation, organizationHandler.CreateInvitation)
+		manage.DELETE("/invitations/:invitation_id", noImpersonation, organizationHandler.RevokeInvitation)
+	}
+
+	invitations := router.Group("invitations")
+	{
+		invitations.GET("/:token", organizationHandler.GetInvitation)
+		invitations.POST("/:token/accept", middleware.AuthMiddleware(), noImpersonation, organizationHandler.AcceptInvitation)
+		invitations.POST("/:token/decline", organizationHandler.DeclineInvitation)
+	}
+
+	// Admin routes resolve an optional organization, so organization admins
+	// get a view limited to their own members
+	admin := router.Group("admin", middleware.AuthMiddleware(), middleware.TenantMiddleware())
+
+	organizationAdmin := middleware.OrganizationRoleMiddleware(model.OrganizationRoleOwner, model.OrganizationRoleAdmin)
 
 	users := admin.Group("users")
 	{
-		users.GET("/", userHandler.GetAllUsers)
-		users.POST("/", userHandler.CreateUser)
-		users.GET("/:id", userHandler.GetUserByID)
-		users.PUT("/:id", userHandler.UpdateUser)
-		users.DELETE("/:id", userHandler.DeleteUser)
+		users.GET("/", organizationAdmin, userHandler.GetAllUsers)
+		users.GET("/search", organizationAdmin, userHandler.SearchUsers)
+		users.GET("/export", organizationAdmin, userHandler.ExportUsers)
+		users.GET("/:id/groups", organizationAdmin, groupHandler.GetUserGroups)
+		users.GET("/:id/permissions", organizationAdmin, groupHandler.GetUserPermissions)
+
+		globalAdmin := users.Group("", middleware.AdminMiddleware())
+		globalAdmin.POST("/", noImpersonation, userHandler.CreateUser)
+		globalAdmin.POST("/bulk", noImpersonation, userHandler.BulkCreateUsers)
+		globalAdmin.PUT("/bulk", noImpersonation, userHandler.BulkUpdateUsers)
+		globalAdmin.DELETE("/bulk", noImpersonation, userHandler.BulkDeleteUsers)
+		globalAdmin.POST("/import", noImpersonation, userImportHandler.Import)
+		globalAdmin.GET("/import/:id", userImportHandler.GetJob)
+		globalAdmin.GET("/:id", userHandler.GetUserByID)
+		globalAdmin.PUT("/:id", noImpersonation, userHandler.UpdateUser)
+		globalAdmin.DELETE("/:id", noImpersonation, userHandler.DeleteUser)
+		// Direct permissions are global, so only global admins grant them
+		globalAdmin.PUT("/:id/permissions", noImpersonation, groupHandler.SetUserPermissions)
+		globalAdmin.POST("/:id/impersonate", noImpersonation, impersonationHandler.Start)
+	}
+
+	admin.GET("/config", middleware.AdminMiddleware(), configHandler.GetConfig)
+	admin.GET("/log-levels", middleware.AdminMiddleware(), logHandler.GetLevels)
+	admin.PUT("/log-levels", middleware.AdminMiddleware(), noImpersonation, logHandler.UpdateLevels)
+
+	groups := admin.Group("groups", organizationAdmin)
+	{
+		groups.GET("/", groupHandler.GetAllGroups)
+		groups.POST("/", noImpersonation, groupHandler.CreateGroup)
+		groups.GET("/:id", groupHandler.GetGroupByID)
+		groups.PUT("/:id", noImpersonation, groupHandler.UpdateGroup)
+		groups.DELETE("/:id", noImpersonation, groupHandler.DeleteGroup)
+		groups.GET("/:id/members", groupHandler.GetMembers)
+		groups.POST("/:id/members", noImpersonation, groupHandler.AddMember)
+		groups.DELETE("/:id/members/:user_id", noImpersonation, groupHandler.RemoveMember)
+		groups.PUT("/:id/permissions", noImpersonation, groupHandler.SetGroupPermissions)
 	}
 }
@@ -2,78 +2,66 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 
+	"github.com/Alfian57/belajar-golang/internal/config"
+	"github.com/Alfian57/belajar-golang/internal/constants"
 	"github.com/Alfian57/belajar-golang/internal/dto"
 	errs "github.com/Alfian57/belajar-golang/internal/errors"
 	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/mailer"
+	"github.com/Alfian57/belajar-golang/internal/metrics"
 	"github.com/Alfian57/belajar-golang/internal/model"
 	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/Alfian57/belajar-golang/internal/tracing"
 	"github.com/Alfian57/belajar-golang/internal/utils/jwt"
+	"github.com/Alfian57/belajar-golang/internal/utils/token"
+	"github.com/Alfian57/belajar-golang/internal/validation"
 )
 
 type AuthService struct {
-	userRepository         *repository.UserRepository
-	refreshTokenRepository *repository.RefreshTokenRepository
+	userRepository           *repository.UserRepository
+	refreshTokenRepository   *repository.RefreshTokenRepository
+	magicLinkTokenRepository *repository.MagicLinkTokenRepository
+	passwordService          *PasswordService
 }
 
-func NewAuthService(userRepository *repository.UserRepository, refreshTokenRepository *repository.RefreshTokenRepository) *AuthService {
+func NewAuthService(userRepository *repository.UserRepository, refreshTokenRepository *repository.RefreshTokenRepository, magicLinkTokenRepository *repository.MagicLinkTokenRepository, passwordService *PasswordService) *AuthService {
 	return &AuthService{
-		userRepository:         userRepository,
-		refreshTokenRepository: refreshTokenRepository,
+		userRepository:           userRepository,
+		refreshTokenRepository:   refreshTokenRepository,
+		magicLinkTokenRepository: magicLinkTokenRepository,
+		passwordService:          passwordService,
 	}
 }
 
 // Login authenticates a user using username and password.
 // It generates access and refresh tokens upon successful authentication.
-func (s *AuthService) Login(ctx context.Context, req dto.LoginRequest) (dto.Credentials, error) {
+func (s *AuthService) Login(ctx context.Context, req dto.LoginRequest) (credentials dto.Credentials, err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
-
-	credentials := dto.Credentials{}
+	ctx, span := tracing.Start(ctx, "AuthService.Login")
+	defer span.End()
+	defer func() { metrics.ObserveLogin(metrics.LoginMethodPassword, err) }()
 
 	// Get user by username
 	user, err := s.userRepository.GetByUsername(ctx, req.Username)
 	if err != nil {
 		if err == errs.ErrUserNotFound {
-			return credentials, errs.NewAppError(http.StatusUnauthorized, "username or password is incorrect", err)
+			return dto.Credentials{}, errs.ErrInvalidCredentials.Wrap(err)
 		}
-		return credentials, errs.NewAppError(http.StatusInternalServerError, "failed to get user", err)
+		return dto.Credentials{}, errs.NewAppError(http.StatusInternalServerError, "failed to get user", err)
 	}
 
 	// Check password
-	if err := user.CheckHashedPassword(req.Password); err != nil {
-		return credentials, errs.NewAppError(http.StatusUnauthorized, "username or password is incorrect", err)
-	}
-
-	// Create access token
-	accessToken, err := jwt.CreateAccessToken(user)
-	if err != nil {
-		return credentials, errs.NewAppError(http.StatusInternalServerError, "failed to create access token", err)
+	if err := s.passwordService.Verify(ctx, user, req.Password); err != nil {
+		return dto.Credentials{}, errs.ErrInvalidCredentials.Wrap(err)
 	}
 
-	// Create refresh token
-	refreshToken, err := jwt.CreateRefreshToken(user)
-	if err != nil {
-		return credentials, errs.NewAppError(http.StatusInternalServerError, "failed to create refresh token", err)
-	}
-
-	// Save refresh token to repository
-	rt := &model.RefreshToken{
-		UserID:    user.ID,
-		TokenHash: refreshToken,
-		ExpiresAt: time.Now().Add(7 * 24 * time.Hour).Unix(),
-	}
-	if err := s.refreshTokenRepository.Create(ctx, rt); err != nil {
-		logger.Log.Info("error bwang", err)
-		return credentials, errs.NewAppError(http.StatusInternalServerError, "failed to save refresh token", err)
-	}
-
-	credentials.AccessToken = accessToken
-	credentials.RefreshToken = refreshToken
-
-	return credentials, nil
+	return s.issueCredentials(ctx, user)
 }
 
 // Register creates a new user with the provided registration details.
@@ -81,29 +69,12 @@ func (s *AuthService) Login(ctx context.Context, req dto.LoginRequest) (dto.Cred
 func (s *AuthService) Register(ctx context.Context, request dto.RegisterRequest) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
+	ctx, span := tracing.Start(ctx, "AuthService.Register")
+	defer span.End()
 
-	// Unique email validation
-	_, err := s.userRepository.GetByEmail(ctx, request.Email)
-	if err != nil && err != errs.ErrUserNotFound {
-		logger.Log.Errorw("failed to check existing email", "email", request.Email, "error", err)
-		return errs.NewAppError(500, "failed to validate email", err)
-	}
-	if err == nil {
-		logger.Log.Infow("email already exists", "email", request.Username)
-		fieldError := errs.NewFieldError("email", "email already exists")
-		return errs.NewValidationError([]errs.FieldError{fieldError})
-	}
-
-	// Unique username validation
-	_, err = s.userRepository.GetByUsername(ctx, request.Username)
-	if err != nil && err != errs.ErrUserNotFound {
-		logger.Log.Errorw("failed to check existing username", "username", request.Username, "error", err)
-		return errs.NewAppError(500, "failed to validate username", err)
-	}
-	if err == nil {
-		logger.Log.Infow("username already exists", "username", request.Username)
-		fieldError := errs.NewFieldError("username", "username already exists")
-		return errs.NewValidationError([]errs.FieldError{fieldError})
+	// Unique email and username validation
+	if err := validation.Struct(ctx, request); err != nil {
+		return err
 	}
 
 	// Password processing
@@ -112,35 +83,82 @@ func (s *AuthService) Register(ctx context.Context, request dto.RegisterRequest)
 		Username: request.Username,
 		Role:     model.UserRoleMember,
 	}
-	err = user.SetHashedPassword(request.Password)
+	if err := s.passwordService.Validate(ctx, user, request.Password); err != nil {
+		return err
+	}
+	err := s.passwordService.Hash(ctx, &user, request.Password)
 	if err != nil {
-		logger.Log.Errorw("failed to hash password", "error", err)
+		logger.FromContext(ctx).Named(logger.ComponentAuth).Errorw("failed to hash password", "error", err)
 		return errs.NewAppError(500, "failed to process password", err)
 	}
 
 	// Create user
 	if err := s.userRepository.Create(ctx, &user); err != nil {
-		logger.Log.Errorw("failed to create user", "username", request.Username, "error", err)
+		logger.FromContext(ctx).Named(logger.ComponentAuth).Errorw("failed to create user", "username", request.Username, "error", err)
 		return errs.NewAppError(500, "failed to create user", err)
 	}
 
-	logger.Log.Infow("user registered successfully", "username", request.Username)
+	// Password history is best effort once the user exists
+	s.passwordService.Record(ctx, user)
+
+	metrics.Registrations.Inc()
+	logger.FromContext(ctx).Named(logger.ComponentAuth).Infow("user registered successfully", "username", request.Username)
+	return nil
+}
+
+// ChangePassword replaces the password of the given user after verifying the current one.
+// All refresh tokens of the user are revoked so other sessions must log in again.
+func (s *AuthService) ChangePassword(ctx context.Context, user model.User, request dto.ChangePasswordRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "AuthService.ChangePassword")
+	defer span.End()
+
+	// Verify current password
+	if err := s.passwordService.Verify(ctx, user, request.CurrentPassword); err != nil {
+		fieldError := errs.NewFieldError("current_password", errs.FieldIncorrect, "current password is incorrect")
+		return errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+
+	if err := s.passwordService.Validate(ctx, user, request.Password); err != nil {
+		return err
+	}
+
+	// Password processing
+	if err := s.passwordService.Hash(ctx, &user, request.Password); err != nil {
+		logger.FromContext(ctx).Named(logger.ComponentAuth).Errorw("failed to hash password", "error", err)
+		return errs.NewAppError(500, "failed to process password", err)
+	}
+
+	if err := s.userRepository.UpdatePassword(ctx, &user); err != nil {
+		logger.FromContext(ctx).Named(logger.ComponentAuth).Errorw("failed to update password", "id", user.ID, "error", err)
+		return errs.NewAppError(500, "failed to update password", err)
+	}
+
+	s.passwordService.Record(ctx, user)
+
+	if err := s.refreshTokenRepository.DeleteByUserID(ctx, user.ID); err != nil {
+		logger.FromContext(ctx).Named(logger.ComponentAuth).Errorw("failed to revoke refresh tokens", "id", user.ID, "error", err)
+	}
+
+	logger.FromContext(ctx).Named(logger.ComponentAuth).Infow("password changed successfully", "id", user.ID)
 	return nil
 }
 
 // Refresh generates new access and refresh tokens using a valid refresh token.
 // It deletes the old refresh token and saves the new one.
-func (s *AuthService) Refresh(ctx context.Context, refreshTokenParam string) (dto.Credentials, error) {
+func (s *AuthService) Refresh(ctx context.Context, refreshTokenParam string) (credentials dto.Credentials, err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
-
-	credentials := dto.Credentials{}
+	ctx, span := tracing.Start(ctx, "AuthService.Refresh")
+	defer span.End()
+	defer func() { metrics.ObserveRefresh(err) }()
 
 	// Get refresh token from repository
 	refreshToken, err := s.refreshTokenRepository.GetByTokenHash(ctx, refreshTokenParam)
 	if err != nil {
 		if err == errs.ErrRefreshTokenNotFound {
-			return credentials, errs.NewAppError(http.StatusUnauthorized, "refresh token not valid", err)
+			return credentials, errs.ErrRefreshTokenNotFound.Wrap(err)
 		}
 		return credentials, errs.NewAppError(http.StatusInternalServerError, "failed to get refresh token", err)
 	}
@@ -157,41 +175,170 @@ func (s *AuthService) Refresh(ctx context.Context, refreshTokenParam string) (dt
 		return credentials, errs.NewAppError(http.StatusInternalServerError, "failed to delete refresh token", err)
 	}
 
-	// Create new access token
-	newAccessToken, err := jwt.CreateAccessToken(user)
+	return s.issueCredentials(ctx, user)
+}
+
+// Logout logs out a user by invalidating the provided refresh token.
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "AuthService.Logout")
+	defer span.End()
+
+	// Delete the refresh token from the repository
+	err := s.refreshTokenRepository.DeleteByTokenHash(ctx, refreshToken)
+
+	return err
+}
+
+// RequestMagicLink emails a single-use sign-in link when the address belongs to a user.
+// The link is issued in the background, and unknown, rate-limited and failed
+// requests are only logged, so neither the response nor its timing tells
+// whether an account exists.
+func (s *AuthService) RequestMagicLink(ctx context.Context, request dto.MagicLinkRequest, fingerprint string, verifyURL string) {
+	go s.issueMagicLink(detach(ctx), request.Email, fingerprint, verifyURL)
+}
+
+// issueMagicLink saves and emails a sign-in link for the user with the given
+// email, unless the address is unknown or has reached the rate limit.
+func (s *AuthService) issueMagicLink(ctx context.Context, email string, fingerprint string, verifyURL string) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "AuthService.issueMagicLink")
+	defer span.End()
+
+	log := logger.FromContext(ctx).Named(logger.ComponentAuth)
+
+	user, err := s.userRepository.GetByEmail(ctx, email)
+	if err != nil {
+		if err == errs.ErrUserNotFound {
+			log.Infow("magic link requested for unknown email")
+			return
+		}
+		log.Errorw("failed to get user by email", "error", err)
+		return
+	}
+
+	// Rate limit per address
+	limits := config.Current().RateLimit
+	count, err := s.magicLinkTokenRepository.CountRecentByEmail(ctx, user.Email, time.Now().Add(-limits.MagicLinkWindow))
+	if err != nil {
+		log.Errorw("failed to count magic links", "id", user.ID, "error", err)
+		return
+	}
+	if count >= int64(limits.MagicLinkRequests) {
+		log.Infow("magic link rate limit reached", "id", user.ID)
+		return
+	}
+
+	plainToken, err := token.Generate()
+	if err != nil {
+		log.Errorw("failed to generate magic link token", "error", err)
+		return
+	}
+
+	magicLink := &model.MagicLinkToken{
+		UserID:      user.ID,
+		Email:       user.Email,
+		TokenHash:   token.Hash(plainToken),
+		Fingerprint: fingerprint,
+		ExpiresAt:   time.Now().Add(constants.MagicLinkTTL),
+	}
+	if err := s.magicLinkTokenRepository.Create(ctx, magicLink); err != nil {
+		log.Errorw("failed to save magic link token", "id", user.ID, "error", err)
+		return
+	}
+
+	message := mailer.Message{
+		To:      user.Email,
+		Subject: "Your sign-in link",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to sign in. It expires in %d minutes and can only be used once.\n\n%s?token=%s\n\nIf you did not request this, you can ignore this email.\n",
+			user.Username, int(constants.MagicLinkTTL.Minutes()), verifyURL, url.QueryEscape(plainToken),
+		),
+	}
+	if err := mailer.Send(ctx, message); err != nil {
+		log.Errorw("failed to send magic link", "id", user.ID, "error", err)
+		return
+	}
+
+	log.Infow("magic link issued", "id", user.ID)
+}
+
+// LoginWithMagicLink redeems a sign-in link and issues the same credentials as Login.
+// The link must be unused, unexpired and opened from the browser that requested it.
+func (s *AuthService) LoginWithMagicLink(ctx context.Context, plainToken string, fingerprint string) (credentials dto.Credentials, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "AuthService.LoginWithMagicLink")
+	defer span.End()
+	defer func() { metrics.ObserveLogin(metrics.LoginMethodMagicLink, err) }()
+
+	magicLink, err := s.magicLinkTokenRepository.GetByTokenHash(ctx, token.Hash(plainToken))
+	if err != nil {
+		if err == errs.ErrMagicLinkInvalid {
+			return dto.Credentials{}, err
+		}
+		return dto.Credentials{}, errs.NewAppError(http.StatusInternalServerError, "failed to get sign-in link", err)
+	}
+
+	if magicLink.UsedAt != nil || time.Now().After(magicLink.ExpiresAt) {
+		return dto.Credentials{}, errs.ErrMagicLinkInvalid
+	}
+
+	if magicLink.Fingerprint != "" && magicLink.Fingerprint != fingerprint {
+		logger.FromContext(ctx).Named(logger.ComponentAuth).Infow("magic link opened from a different browser", "id", magicLink.UserID)
+		return dto.Credentials{}, errs.ErrMagicLinkInvalid
+	}
+
+	if err := s.magicLinkTokenRepository.MarkUsed(ctx, magicLink.ID); err != nil {
+		if err == errs.ErrMagicLinkInvalid {
+			return dto.Credentials{}, err
+		}
+		return dto.Credentials{}, errs.NewAppError(http.StatusInternalServerError, "failed to redeem sign-in link", err)
+	}
+
+	user, err := s.userRepository.GetByID(ctx, magicLink.UserID.String())
+	if err != nil {
+		if err == errs.ErrUserNotFound {
+			return dto.Credentials{}, errs.ErrMagicLinkInvalid
+		}
+		return dto.Credentials{}, errs.NewAppError(http.StatusInternalServerError, "failed to get user", err)
+	}
+
+	logger.FromContext(ctx).Named(logger.ComponentAuth).Infow("user logged in with magic link", "id", user.ID)
+	return s.issueCredentials(ctx, user)
+}
+
+// issueCredentials creates an access and refresh token pair for the user
+// and stores the refresh token.
+func (s *AuthService) issueCredentials(ctx context.Context, user model.User) (dto.Credentials, error) {
+	credentials := dto.Credentials{}
+
+	// Create access token
+	accessToken, err := jwt.CreateAccessToken(user)
 	if err != nil {
 		return credentials, errs.NewAppError(http.StatusInternalServerError, "failed to create access token", err)
 	}
 
-	// Create new refresh token
-	newRefreshToken, err := jwt.CreateRefreshToken(user)
+	// Create refresh token
+	refreshToken, err := jwt.CreateRefreshToken(user)
 	if err != nil {
 		return credentials, errs.NewAppError(http.StatusInternalServerError, "failed to create refresh token", err)
 	}
 
-	// Save new refresh token to repository
+	// Save refresh token to repository
 	rt := &model.RefreshToken{
 		UserID:    user.ID,
-		TokenHash: newRefreshToken,
-		ExpiresAt: time.Now().Add(7 * 24 * time.Hour).Unix(),
+		TokenHash: refreshToken,
+		ExpiresAt: time.Now().Add(7 * 24 * time.Hour),
 	}
 	if err := s.refreshTokenRepository.Create(ctx, rt); err != nil {
 		return credentials, errs.NewAppError(http.StatusInternalServerError, "failed to save refresh token", err)
 	}
 
-	credentials.AccessToken = newAccessToken
-	credentials.RefreshToken = newRefreshToken
+	credentials.AccessToken = accessToken
+	credentials.RefreshToken = refreshToken
 
 	return credentials, nil
 }
-
-// Logout logs out a user by invalidating the provided refresh token.
-func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
-	// Delete the refresh token from the repository
-	err := s.refreshTokenRepository.DeleteByTokenHash(ctx, refreshToken)
-
-	return err
-}
@@ -0,0 +1,449 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/Alfian57/belajar-golang/internal/tenant"
+	"github.com/Alfian57/belajar-golang/internal/tracing"
+	"github.com/google/uuid"
+)
+
+type GroupService struct {
+	groupRepository              *repository.GroupRepository
+	userRepository               *repository.UserRepository
+	userPermissionRepository     *repository.UserPermissionRepository
+	organizationMemberRepository *repository.OrganizationMemberRepository
+}
+
+func NewGroupService(
+	groupRepository *repository.GroupRepository,
+	userRepository *repository.UserRepository,
+	userPermissionRepository *repository.UserPermissionRepository,
+	organizationMemberRepository *repository.OrganizationMemberRepository,
+) *GroupService {
+	return &GroupService{
+		groupRepository:              groupRepository,
+		userRepository:               userRepository,
+		userPermissionRepository:     userPermissionRepository,
+		organizationMemberRepository: organizationMemberRepository,
+	}
+}
+
+// GetAllGroups retrieves the groups of the current tenant.
+func (s *GroupService) GetAllGroups(ctx context.Context) ([]model.Group, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "GroupService.GetAllGroups")
+	defer span.End()
+
+	groups, err := s.groupRepository.GetAll(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to retrieve groups", "error", err)
+		return nil, errs.NewAppError(500, "failed to retrieve groups", err)
+	}
+
+	return groups, nil
+}
+
+// GetGroupByID retrieves a group of the current tenant by its ID.
+func (s *GroupService) GetGroupByID(ctx context.Context, id string) (model.Group, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "GroupService.GetGroupByID")
+	defer span.End()
+
+	group, err := s.groupRepository.GetByID(ctx, id)
+	if err != nil {
+		if err == errs.ErrGroupNotFound {
+			return group, err
+		}
+		logger.FromContext(ctx).Errorw("failed to get group by ID", "id", id, "error", err)
+		return group, errs.NewAppError(500, "failed to retrieve group", err)
+	}
+
+	return group, nil
+}
+
+// CreateGroup creates a group in the current tenant, optionally below a parent group.
+func (s *GroupService) CreateGroup(ctx context.Context, request dto.CreateGroupRequest) (model.Group, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "GroupService.CreateGroup")
+	defer span.End()
+
+	if err := s.validateName(ctx, request.Name, uuid.Nil); err != nil {
+		return model.Group{}, err
+	}
+
+	if err := s.validateParent(ctx, request.ParentID); err != nil {
+		return model.Group{}, err
+	}
+
+	if err := validateGroupRole(ctx, request.Role); err != nil {
+		return model.Group{}, err
+	}
+
+	group := model.Group{
+		Name:     request.Name,
+		ParentID: request.ParentID,
+		Role:     optionalRole(request.Role),
+	}
+	if err := s.groupRepository.Create(ctx, &group); err != nil {
+		return model.Group{}, writeError(ctx, "failed to create group", err, "name", request.Name)
+	}
+
+	logger.FromContext(ctx).Infow("group created successfully", "id", group.ID)
+	return group, nil
+}
+
+// UpdateGroup renames a group, moves it to another parent or changes its role.
+// A group cannot be moved below itself or one of its descendants, nor nested
+// deeper than constants.MaxGroupDepth levels.
+func (s *GroupService) UpdateGroup(ctx context.Context, request dto.UpdateGroupRequest) (model.Group, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "GroupService.UpdateGroup")
+	defer span.End()
+
+	group, err := s.GetGroupByID(ctx, request.ID.String())
+	if err != nil {
+		return model.Group{}, err
+	}
+
+	if err := s.validateName(ctx, request.Name, group.ID); err != nil {
+		return model.Group{}, err
+	}
+
+	if err := s.validateParent(ctx, request.ParentID); err != nil {
+		return model.Group{}, err
+	}
+
+	if err := validateGroupRole(ctx, request.Role); err != nil {
+		return model.Group{}, err
+	}
+
+	group.Name = request.Name
+	group.ParentID = request.ParentID
+	group.Role = optionalRole(request.Role)
+	if err := s.groupRepository.Update(ctx, &group); err != nil {
+		return model.Group{}, writeError(ctx, "failed to update group", err, "id", group.ID)
+	}
+
+	logger.FromContext(ctx).Infow("group updated successfully", "id", group.ID)
+	return group, nil
+}
+
+// DeleteGroup deletes a group. Its child groups become top-level groups.
+func (s *GroupService) DeleteGroup(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "GroupService.DeleteGroup")
+	defer span.End()
+
+	if err := s.groupRepository.Delete(ctx, id.String()); err != nil {
+		if err == errs.ErrGroupNotFound {
+			return err
+		}
+		logger.FromContext(ctx).Errorw("failed to delete group", "id", id, "error", err)
+		return errs.NewAppError(500, "failed to delete group", err)
+	}
+
+	logger.FromContext(ctx).Infow("group deleted successfully", "id", id)
+	return nil
+}
+
+// GetMembers retrieves the direct members of a group.
+func (s *GroupService) GetMembers(ctx context.Context, groupID uuid.UUID) ([]model.GroupMember, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "GroupService.GetMembers")
+	defer span.End()
+
+	if _, err := s.GetGroupByID(ctx, groupID.String()); err != nil {
+		return nil, err
+	}
+
+	members, err := s.groupRepository.GetMembers(ctx, groupID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to retrieve group members", "group_id", groupID, "error", err)
+		return nil, errs.NewAppError(500, "failed to retrieve group members", err)
+	}
+
+	return members, nil
+}
+
+// AddMember adds a user to a group. Within an organization the user must be a member of it.
+func (s *GroupService) AddMember(ctx context.Context, groupID uuid.UUID, request dto.AddGroupMemberRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "GroupService.AddMember")
+	defer span.End()
+
+	if _, err := s.GetGroupByID(ctx, groupID.String()); err != nil {
+		return err
+	}
+
+	if err := s.ensureUserInTenant(ctx, request.UserID); err != nil {
+		return err
+	}
+
+	member := model.GroupMember{
+		GroupID: groupID,
+		UserID:  request.UserID,
+	}
+	if err := s.groupRepository.AddMember(ctx, &member); err != nil {
+		logger.FromContext(ctx).Errorw("failed to add group member", "group_id", groupID, "user_id", request.UserID, "error", err)
+		return errs.NewAppError(500, "failed to add group member", err)
+	}
+
+	logger.FromContext(ctx).Infow("group member added successfully", "group_id", groupID, "user_id", request.UserID)
+	return nil
+}
+
+// RemoveMember removes a user from a group.
+func (s *GroupService) RemoveMember(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "GroupService.RemoveMember")
+	defer span.End()
+
+	if _, err := s.GetGroupByID(ctx, groupID.String()); err != nil {
+		return err
+	}
+
+	if err := s.groupRepository.RemoveMember(ctx, groupID, userID); err != nil {
+		if err == errs.ErrGroupMemberNotFound {
+			return err
+		}
+		logger.FromContext(ctx).Errorw("failed to remove group member", "group_id", groupID, "user_id", userID, "error", err)
+		return errs.NewAppError(500, "failed to remove group member", err)
+	}
+
+	logger.FromContext(ctx).Infow("group member removed successfully", "group_id", groupID, "user_id", userID)
+	return nil
+}
+
+// SetGroupPermissions replaces the permissions granted to a group.
+func (s *GroupService) SetGroupPermissions(ctx context.Context, groupID uuid.UUID, request dto.SetPermissionsRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "GroupService.SetGroupPermissions")
+	defer span.End()
+
+	if _, err := s.GetGroupByID(ctx, groupID.String()); err != nil {
+		return err
+	}
+
+	if err := s.groupRepository.SetPermissions(ctx, groupID, request.Permissions); err != nil {
+		logger.FromContext(ctx).Errorw("failed to set group permissions", "group_id", groupID, "error", err)
+		return errs.NewAppError(500, "failed to set group permissions", err)
+	}
+
+	return nil
+}
+
+// SetUserPermissions replaces the permissions granted directly to a user.
+func (s *GroupService) SetUserPermissions(ctx context.Context, userID uuid.UUID, request dto.SetPermissionsRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "GroupService.SetUserPermissions")
+	defer span.End()
+
+	if err := s.ensureUserInTenant(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.userPermissionRepository.SetPermissions(ctx, userID, request.Permissions); err != nil {
+		logger.FromContext(ctx).Errorw("failed to set user permissions", "user_id", userID, "error", err)
+		return errs.NewAppError(500, "failed to set user permissions", err)
+	}
+
+	return nil
+}
+
+// GetEffectiveGroups retrieves the groups a user belongs to, including groups
+// inherited through nesting.
+func (s *GroupService) GetEffectiveGroups(ctx context.Context, userID uuid.UUID) ([]repository.EffectiveGroup, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "GroupService.GetEffectiveGroups")
+	defer span.End()
+
+	if err := s.ensureUserInTenant(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	groups, err := s.groupRepository.GetEffectiveGroups(ctx, userID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to retrieve effective groups", "user_id", userID, "error", err)
+		return nil, errs.NewAppError(500, "failed to retrieve groups", err)
+	}
+
+	return groups, nil
+}
+
+// GetEffectivePermissions combines the user's own role and permissions with
+// those granted through effective groups. The role and direct permissions of
+// a user are global, so within an organization only its groups count.
+func (s *GroupService) GetEffectivePermissions(ctx context.Context, userID uuid.UUID) (dto.EffectivePermissions, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "GroupService.GetEffectivePermissions")
+	defer span.End()
+
+	groups, err := s.GetEffectiveGroups(ctx, userID)
+	if err != nil {
+		return dto.EffectivePermissions{}, err
+	}
+
+	roles := []string{}
+	permissions := []string{}
+	if _, ok := tenant.FromContext(ctx); !ok {
+		user, err := s.userRepository.GetByID(ctx, userID.String())
+		if err != nil {
+			if err == errs.ErrUserNotFound {
+				return dto.EffectivePermissions{}, err
+			}
+			logger.FromContext(ctx).Errorw("failed to get user by ID", "id", userID, "error", err)
+			return dto.EffectivePermissions{}, errs.NewAppError(500, "failed to retrieve user", err)
+		}
+		roles = append(roles, user.Role)
+
+		permissions, err = s.userPermissionRepository.GetByUserID(ctx, userID)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to retrieve user permissions", "user_id", userID, "error", err)
+			return dto.EffectivePermissions{}, errs.NewAppError(500, "failed to retrieve permissions", err)
+		}
+	}
+
+	groupIDs := make([]uuid.UUID, len(groups))
+	for i, group := range groups {
+		groupIDs[i] = group.ID
+		if group.Role != nil && !slices.Contains(roles, *group.Role) {
+			roles = append(roles, *group.Role)
+		}
+	}
+
+	groupPermissions, err := s.groupRepository.GetPermissions(ctx, groupIDs)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to retrieve group permissions", "user_id", userID, "error", err)
+		return dto.EffectivePermissions{}, errs.NewAppError(500, "failed to retrieve permissions", err)
+	}
+
+	permissions = append(permissions, groupPermissions...)
+	slices.Sort(permissions)
+
+	return dto.EffectivePermissions{
+		Roles:       roles,
+		Permissions: slices.Compact(permissions),
+	}, nil
+}
+
+// HasGlobalRole reports whether the user holds the role, either directly or
+// through an effective global group.
+func (s *GroupService) HasGlobalRole(ctx context.Context, user model.User, role string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "GroupService.HasGlobalRole")
+	defer span.End()
+
+	if user.Role == role {
+		return true, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ok, err := s.groupRepository.HasGlobalRole(ctx, user.ID, role)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to check group role", "user_id", user.ID, "error", err)
+		return false, errs.NewAppError(500, "failed to check role", err)
+	}
+
+	return ok, nil
+}
+
+func (s *GroupService) validateName(ctx context.Context, name string, groupID uuid.UUID) error {
+	existing, err := s.groupRepository.GetByName(ctx, name)
+	if err != nil && err != errs.ErrGroupNotFound {
+		logger.FromContext(ctx).Errorw("failed to check existing group name", "name", name, "error", err)
+		return errs.NewAppError(500, "failed to validate name", err)
+	}
+	if err == nil && existing.ID != groupID {
+		fieldError := errs.NewFieldError("name", errs.FieldTaken, "name already exists")
+		return errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+
+	return nil
+}
+
+// validateParent checks that the parent exists in the same tenant. The
+// repository rejects cycles and too deep nesting while it writes the group.
+func (s *GroupService) validateParent(ctx context.Context, parentID *uuid.UUID) error {
+	if parentID == nil {
+		return nil
+	}
+
+	if _, err := s.groupRepository.GetByID(ctx, parentID.String()); err != nil {
+		if err == errs.ErrGroupNotFound {
+			fieldError := errs.NewFieldError("parent_id", errs.FieldNotFound, "parent group not found")
+			return errs.NewValidationError([]errs.FieldError{fieldError})
+		}
+		logger.FromContext(ctx).Errorw("failed to get parent group", "parent_id", parentID, "error", err)
+		return errs.NewAppError(500, "failed to validate parent group", err)
+	}
+
+	return nil
+}
+
+// ensureUserInTenant checks that the user exists and, within an organization, belongs to it.
+func (s *GroupService) ensureUserInTenant(ctx context.Context, userID uuid.UUID) error {
+	organization, ok := tenant.FromContext(ctx)
+	if !ok {
+		if _, err := s.userRepository.GetByID(ctx, userID.String()); err != nil {
+			if err == errs.ErrUserNotFound {
+				return err
+			}
+			logger.FromContext(ctx).Errorw("failed to get user by ID", "id", userID, "error", err)
+			return errs.NewAppError(500, "failed to retrieve user", err)
+		}
+		return nil
+	}
+
+	if _, err := s.organizationMemberRepository.Get(ctx, organization.ID, userID); err != nil {
+		if err == errs.ErrOrganizationMemberNotFound {
+			return errs.ErrUserNotFound
+		}
+		logger.FromContext(ctx).Errorw("failed to get organization member", "organization_id", organization.ID, "user_id", userID, "error", err)
+		return errs.NewAppError(500, "failed to retrieve user", err)
+	}
+
+	return nil
+}
+
+// validateGroupRole only allows roles on global groups, so an organization
+// group can never grant an application-wide role.
+func validateGroupRole(ctx context.Context, role string) error {
+	if role == "" {
+		return nil
+	}
+
+	if _, ok := tenant.FromContext(ctx); ok {
+		fieldError := errs.NewFieldError("role", errs.FieldGlobalOnly, "roles can only be assigned to global groups")
+		return errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+
+	return nil
+}
+
+func optionalRole(role string) *string {
+	if role == "" {
+		return nil
+	}
+	return &role
+}
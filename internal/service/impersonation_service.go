@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/constants"
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/Alfian57/belajar-golang/internal/tracing"
+	"github.com/Alfian57/belajar-golang/internal/utils/jwt"
+	"github.com/google/uuid"
+)
+
+type ImpersonationService struct {
+	userRepository                 *repository.UserRepository
+	impersonationSessionRepository *repository.ImpersonationSessionRepository
+	groupRepository                *repository.GroupRepository
+}
+
+func NewImpersonationService(userRepository *repository.UserRepository, impersonationSessionRepository *repository.ImpersonationSessionRepository, groupRepository *repository.GroupRepository) *ImpersonationService {
+	return &ImpersonationService{
+		userRepository:                 userRepository,
+		impersonationSessionRepository: impersonationSessionRepository,
+		groupRepository:                groupRepository,
+	}
+}
+
+// Start records an impersonation session and issues a time-boxed access token
+// for the target user on behalf of the actor.
+func (s *ImpersonationService) Start(ctx context.Context, actor model.User, request dto.ImpersonateRequest) (dto.ImpersonationResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "ImpersonationService.Start")
+	defer span.End()
+
+	if request.TargetID == actor.ID {
+		return dto.ImpersonationResponse{}, errs.ErrImpersonationTargetInvalid
+	}
+
+	target, err := s.userRepository.GetByID(ctx, request.TargetID.String())
+	if err != nil {
+		if err == errs.ErrUserNotFound {
+			return dto.ImpersonationResponse{}, err
+		}
+		logger.FromContext(ctx).Errorw("failed to get impersonation target", "id", request.TargetID, "error", err)
+		return dto.ImpersonationResponse{}, errs.NewAppError(500, "failed to retrieve user", err)
+	}
+
+	// Admins cannot be impersonated, so impersonation never grants more than member access
+	if target.Role == model.UserRoleAdmin {
+		return dto.ImpersonationResponse{}, errs.ErrImpersonationTargetInvalid
+	}
+	groupAdmin, err := s.groupRepository.HasGlobalRole(ctx, target.ID, model.UserRoleAdmin)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to check impersonation target role", "id", target.ID, "error", err)
+		return dto.ImpersonationResponse{}, errs.NewAppError(500, "failed to retrieve user", err)
+	}
+	if groupAdmin {
+		return dto.ImpersonationResponse{}, errs.ErrImpersonationTargetInvalid
+	}
+
+	ttl := constants.DefaultImpersonationTTL
+	if request.DurationMinutes > 0 {
+		ttl = time.Duration(request.DurationMinutes) * time.Minute
+	}
+	if ttl > constants.MaxImpersonationTTL {
+		ttl = constants.MaxImpersonationTTL
+	}
+
+	session := &model.ImpersonationSession{
+		ID:        uuid.New(),
+		ActorID:   actor.ID,
+		TargetID:  target.ID,
+		Reason:    request.Reason,
+		IPAddress: request.IPAddress,
+		UserAgent: request.UserAgent,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.impersonationSessionRepository.Create(ctx, session); err != nil {
+		logger.FromContext(ctx).Errorw("failed to save impersonation session", "actor_id", actor.ID, "target_id", target.ID, "error", err)
+		return dto.ImpersonationResponse{}, errs.NewAppError(500, "failed to start impersonation", err)
+	}
+
+	accessToken, err := jwt.CreateImpersonationToken(target, actor, session.ID, session.ExpiresAt)
+	if err != nil {
+		return dto.ImpersonationResponse{}, errs.NewAppError(500, "failed to create access token", err)
+	}
+
+	logger.FromContext(ctx).Infow("impersonation started",
+		"session_id", session.ID,
+		"actor_id", actor.ID,
+		"actor_username", actor.Username,
+		"target_id", target.ID,
+		"target_username", target.Username,
+		"reason", request.Reason,
+		"expires_at", session.ExpiresAt,
+	)
+
+	return dto.ImpersonationResponse{
+		SessionID:   session.ID,
+		AccessToken: accessToken,
+		ExpiresAt:   session.ExpiresAt,
+		User:        target,
+		Actor:       actor,
+	}, nil
+}
+
+// End terminates an impersonation session before it expires.
+func (s *ImpersonationService) End(ctx context.Context, sessionID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "ImpersonationService.End")
+	defer span.End()
+
+	if err := s.impersonationSessionRepository.End(ctx, sessionID); err != nil {
+		if err == errs.ErrImpersonationSessionNotFound {
+			return err
+		}
+		logger.FromContext(ctx).Errorw("failed to end impersonation session", "session_id", sessionID, "error", err)
+		return errs.NewAppError(500, "failed to end impersonation", err)
+	}
+
+	logger.FromContext(ctx).Infow("impersonation ended", "session_id", sessionID)
+	return nil
+}
+
+// GetActiveSession returns the session if it has neither expired nor been ended.
+func (s *ImpersonationService) GetActiveSession(ctx context.Context, sessionID string) (model.ImpersonationSession, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "ImpersonationService.GetActiveSession")
+	defer span.End()
+
+	session, err := s.impersonationSessionRepository.GetByID(ctx, sessionID)
+	if err != nil {
+		if err == errs.ErrImpersonationSessionNotFound {
+			return session, err
+		}
+		logger.FromContext(ctx).Errorw("failed to get impersonation session", "session_id", sessionID, "error", err)
+		return session, errs.NewAppError(500, "failed to retrieve impersonation session", err)
+	}
+
+	if !session.IsActive(time.Now()) {
+		return session, errs.ErrImpersonationSessionNotFound
+	}
+
+	return session, nil
+}
@@ -0,0 +1,501 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/constants"
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/mailer"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/Alfian57/belajar-golang/internal/tracing"
+	"github.com/Alfian57/belajar-golang/internal/utils/token"
+	"github.com/google/uuid"
+)
+
+var (
+	slugPattern     = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	slugInvalidChar = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+type OrganizationService struct {
+	organizationRepository           *repository.OrganizationRepository
+	organizationMemberRepository     *repository.OrganizationMemberRepository
+	organizationInvitationRepository *repository.OrganizationInvitationRepository
+	userRepository                   *repository.UserRepository
+	groupRepository                  *repository.GroupRepository
+}
+
+func NewOrganizationService(
+	organizationRepository *repository.OrganizationRepository,
+	organizationMemberRepository *repository.OrganizationMemberRepository,
+	organizationInvitationRepository *repository.OrganizationInvitationRepository,
+	userRepository *repository.UserRepository,
+	groupRepository *repository.GroupRepository,
+) *OrganizationService {
+	return &OrganizationService{
+		organizationRepository:           organizationRepository,
+		organizationMemberRepository:     organizationMemberRepository,
+		organizationInvitationRepository: organizationInvitationRepository,
+		userRepository:                   userRepository,
+		groupRepository:                  groupRepository,
+	}
+}
+
+// Resolve finds an organization by ID or slug.
+func (s *OrganizationService) Resolve(ctx context.Context, identifier string) (model.Organization, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "OrganizationService.Resolve")
+	defer span.End()
+
+	var organization model.Organization
+	var err error
+	if _, parseErr := uuid.Parse(identifier); parseErr == nil {
+		organization, err = s.organizationRepository.GetByID(ctx, identifier)
+	} else {
+		organization, err = s.organizationRepository.GetBySlug(ctx, strings.ToLower(identifier))
+	}
+
+	if err != nil {
+		if err == errs.ErrOrganizationNotFound {
+			return organization, err
+		}
+		logger.FromContext(ctx).Errorw("failed to resolve organization", "identifier", identifier, "error", err)
+		return organization, errs.NewAppError(500, "failed to retrieve organization", err)
+	}
+
+	return organization, nil
+}
+
+// GetMembership returns the user's membership in the organization.
+func (s *OrganizationService) GetMembership(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID) (model.OrganizationMember, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "OrganizationService.GetMembership")
+	defer span.End()
+
+	member, err := s.organizationMemberRepository.Get(ctx, organizationID, userID)
+	if err != nil {
+		if err == errs.ErrOrganizationMemberNotFound {
+			return member, err
+		}
+		logger.FromContext(ctx).Errorw("failed to get organization member", "organization_id", organizationID, "user_id", userID, "error", err)
+		return member, errs.NewAppError(500, "failed to retrieve organization member", err)
+	}
+
+	return member, nil
+}
+
+// GetUserOrganizations retrieves the organizations the user belongs to.
+func (s *OrganizationService) GetUserOrganizations(ctx context.Context, user model.User) ([]model.Organization, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "OrganizationService.GetUserOrganizations")
+	defer span.End()
+
+	organizations, err := s.organizationRepository.GetAllByUserID(ctx, user.ID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to retrieve organizations", "user_id", user.ID, "error", err)
+		return nil, errs.NewAppError(500, "failed to retrieve organizations", err)
+	}
+
+	return organizations, nil
+}
+
+// CreateOrganization creates an organization with the given user as its owner.
+func (s *OrganizationService) CreateOrganization(ctx context.Context, owner model.User, request dto.CreateOrganizationRequest) (model.Organization, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "OrganizationService.CreateOrganization")
+	defer span.End()
+
+	slug, err := s.availableSlug(ctx, request.Slug, request.Name, uuid.Nil)
+	if err != nil {
+		return model.Organization{}, err
+	}
+
+	organization := model.Organization{
+		Name: request.Name,
+		Slug: slug,
+	}
+	if err := s.organizationRepository.CreateWithOwner(ctx, &organization, owner.ID); err != nil {
+		logger.FromContext(ctx).Errorw("failed to create organization", "name", request.Name, "error", err)
+		return model.Organization{}, errs.NewAppError(500, "failed to create organization", err)
+	}
+
+	logger.FromContext(ctx).Infow("organization created successfully", "id", organization.ID, "owner_id", owner.ID)
+	return organization, nil
+}
+
+// UpdateOrganization renames the organization and optionally changes its slug.
+func (s *OrganizationService) UpdateOrganization(ctx context.Context, organization model.Organization, request dto.UpdateOrganizationRequest) (model.Organization, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "OrganizationService.UpdateOrganization")
+	defer span.End()
+
+	organization.Name = request.Name
+	if request.Slug != "" && request.Slug != organization.Slug {
+		slug, err := s.availableSlug(ctx, request.Slug, "", organization.ID)
+		if err != nil {
+			return model.Organization{}, err
+		}
+		organization.Slug = slug
+	}
+
+	if err := s.organizationRepository.Update(ctx, &organization); err != nil {
+		logger.FromContext(ctx).Errorw("failed to update organization", "id", organization.ID, "error", err)
+		return model.Organization{}, errs.NewAppError(500, "failed to update organization", err)
+	}
+
+	logger.FromContext(ctx).Infow("organization updated successfully", "id", organization.ID)
+	return organization, nil
+}
+
+// DeleteOrganization deletes the organization with its memberships and invitations.
+func (s *OrganizationService) DeleteOrganization(ctx context.Context, organization model.Organization) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "OrganizationService.DeleteOrganization")
+	defer span.End()
+
+	if err := s.organizationRepository.Delete(ctx, organization.ID.String()); err != nil {
+		if err == errs.ErrOrganizationNotFound {
+			return err
+		}
+		logger.FromContext(ctx).Errorw("failed to delete organization", "id", organization.ID, "error", err)
+		return errs.NewAppError(500, "failed to delete organization", err)
+	}
+
+	logger.FromContext(ctx).Infow("organization deleted successfully", "id", organization.ID)
+	return nil
+}
+
+// GetMembers retrieves all members of the organization.
+func (s *OrganizationService) GetMembers(ctx context.Context, organization model.Organization) ([]model.OrganizationMember, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "OrganizationService.GetMembers")
+	defer span.End()
+
+	members, err := s.organizationMemberRepository.GetAllByOrganizationID(ctx, organization.ID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to retrieve organization members", "organization_id", organization.ID, "error", err)
+		return nil, errs.NewAppError(500, "failed to retrieve organization members", err)
+	}
+
+	return members, nil
+}
+
+// UpdateMemberRole changes a member's role. Only owners and global admins may
+// grant or revoke the owner role, and the last owner cannot be demoted.
+func (s *OrganizationService) UpdateMemberRole(ctx context.Context, organization model.Organization, actor model.User, userID uuid.UUID, request dto.UpdateOrganizationMemberRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "OrganizationService.UpdateMemberRole")
+	defer span.End()
+
+	member, err := s.GetMembership(ctx, organization.ID, userID)
+	if err != nil {
+		return err
+	}
+
+	if member.Role == model.OrganizationRoleOwner || request.Role == model.OrganizationRoleOwner {
+		if err := s.ensureCanManageOwners(ctx, organization.ID, actor); err != nil {
+			return err
+		}
+	}
+
+	member.Role = request.Role
+	if err := s.organizationMemberRepository.UpdateRole(ctx, &member); err != nil {
+		if err == errs.ErrLastOrganizationOwner {
+			return err
+		}
+		logger.FromContext(ctx).Errorw("failed to update organization member", "organization_id", organization.ID, "user_id", userID, "error", err)
+		return errs.NewAppError(500, "failed to update organization member", err)
+	}
+
+	logger.FromContext(ctx).Infow("organization member updated successfully", "organization_id", organization.ID, "user_id", userID, "role", request.Role)
+	return nil
+}
+
+// RemoveMember removes a user from the organization. Owners can only be removed
+// by owners and global admins, and the last owner cannot leave.
+func (s *OrganizationService) RemoveMember(ctx context.Context, organization model.Organization, actor model.User, userID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "OrganizationService.RemoveMember")
+	defer span.End()
+
+	member, err := s.GetMembership(ctx, organization.ID, userID)
+	if err != nil {
+		return err
+	}
+
+	if member.Role == model.OrganizationRoleOwner {
+		if err := s.ensureCanManageOwners(ctx, organization.ID, actor); err != nil {
+			return err
+		}
+	}
+
+	if err := s.organizationMemberRepository.Delete(ctx, organization.ID, userID); err != nil {
+		if err == errs.ErrOrganizationMemberNotFound || err == errs.ErrLastOrganizationOwner {
+			return err
+		}
+		logger.FromContext(ctx).Errorw("failed to remove organization member", "organization_id", organization.ID, "user_id", userID, "error", err)
+		return errs.NewAppError(500, "failed to remove organization member", err)
+	}
+
+	logger.FromContext(ctx).Infow("organization member removed successfully", "organization_id", organization.ID, "user_id", userID)
+	return nil
+}
+
+// GetPendingInvitations retrieves invitations that have not been answered yet.
+func (s *OrganizationService) GetPendingInvitations(ctx context.Context, organization model.Organization) ([]model.OrganizationInvitation, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "OrganizationService.GetPendingInvitations")
+	defer span.End()
+
+	invitations, err := s.organizationInvitationRepository.GetPendingByOrganizationID(ctx, organization.ID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to retrieve invitations", "organization_id", organization.ID, "error", err)
+		return nil, errs.NewAppError(500, "failed to retrieve invitations", err)
+	}
+
+	return invitations, nil
+}
+
+// Invite emails an invitation to join the organization. Earlier pending
+// invitations for the same address are revoked.
+func (s *OrganizationService) Invite(ctx context.Context, organization model.Organization, inviter model.User, request dto.CreateInvitationRequest, invitationURL string) (model.OrganizationInvitation, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "OrganizationService.Invite")
+	defer span.End()
+
+	email := strings.ToLower(request.Email)
+	role := request.Role
+	if role == "" {
+		role = model.OrganizationRoleMember
+	}
+
+	// Existing members cannot be invited again
+	existingUser, err := s.userRepository.GetByEmail(ctx, email)
+	if err != nil && err != errs.ErrUserNotFound {
+		logger.FromContext(ctx).Errorw("failed to check existing email", "email", email, "error", err)
+		return model.OrganizationInvitation{}, errs.NewAppError(500, "failed to validate email", err)
+	}
+	if err == nil {
+		if _, err := s.organizationMemberRepository.Get(ctx, organization.ID, existingUser.ID); err == nil {
+			fieldError := errs.NewFieldError("email", errs.FieldAlreadyMember, "user is already a member of the organization")
+			return model.OrganizationInvitation{}, errs.NewValidationError([]errs.FieldError{fieldError})
+		}
+	}
+
+	if err := s.organizationInvitationRepository.RevokePendingByEmail(ctx, organization.ID, email); err != nil {
+		logger.FromContext(ctx).Errorw("failed to revoke previous invitations", "organization_id", organization.ID, "error", err)
+		return model.OrganizationInvitation{}, errs.NewAppError(500, "failed to create invitation", err)
+	}
+
+	plainToken, err := token.Generate()
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to generate invitation token", "error", err)
+		return model.OrganizationInvitation{}, errs.NewAppError(500, "failed to create invitation", err)
+	}
+
+	invitation := model.OrganizationInvitation{
+		OrganizationID: organization.ID,
+		Email:          email,
+		Role:           role,
+		TokenHash:      token.Hash(plainToken),
+		Status:         model.InvitationStatusPending,
+		InvitedBy:      &inviter.ID,
+		ExpiresAt:      time.Now().Add(constants.InvitationTTL),
+	}
+	if err := s.organizationInvitationRepository.Create(ctx, &invitation); err != nil {
+		logger.FromContext(ctx).Errorw("failed to create invitation", "organization_id", organization.ID, "error", err)
+		return model.OrganizationInvitation{}, errs.NewAppError(500, "failed to create invitation", err)
+	}
+
+	message := mailer.Message{
+		To:      email,
+		Subject: fmt.Sprintf("You have been invited to join %s", organization.Name),
+		Body: fmt.Sprintf(
+			"Hi,\n\n%s invited you to join %s as %s.\n\nView the invitation to accept or decline it:\n\n%s/%s\n\nThe invitation expires in %d days.\n",
+			inviter.Username, organization.Name, role, invitationURL, plainToken, int(constants.InvitationTTL.Hours()/24),
+		),
+	}
+	mailCtx := detach(ctx)
+	go func() {
+		sendCtx, cancel := context.WithTimeout(mailCtx, 30*time.Second)
+		defer cancel()
+
+		if err := mailer.Send(sendCtx, message); err != nil {
+			logger.FromContext(sendCtx).Errorw("failed to send invitation", "invitation_id", invitation.ID, "error", err)
+		}
+	}()
+
+	logger.FromContext(ctx).Infow("invitation created successfully", "organization_id", organization.ID, "invitation_id", invitation.ID)
+	return invitation, nil
+}
+
+// RevokeInvitation cancels a pending invitation of the organization.
+func (s *OrganizationService) RevokeInvitation(ctx context.Context, organization model.Organization, invitationID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "OrganizationService.RevokeInvitation")
+	defer span.End()
+
+	if err := s.organizationInvitationRepository.UpdateStatus(ctx, organization.ID, invitationID, model.InvitationStatusRevoked); err != nil {
+		if err == errs.ErrInvitationNotFound {
+			return err
+		}
+		logger.FromContext(ctx).Errorw("failed to revoke invitation", "invitation_id", invitationID, "error", err)
+		return errs.NewAppError(500, "failed to revoke invitation", err)
+	}
+
+	return nil
+}
+
+// GetInvitation retrieves an invitation by its plain token.
+func (s *OrganizationService) GetInvitation(ctx context.Context, plainToken string) (model.OrganizationInvitation, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "OrganizationService.GetInvitation")
+	defer span.End()
+
+	invitation, err := s.organizationInvitationRepository.GetByTokenHash(ctx, token.Hash(plainToken))
+	if err != nil {
+		if err == errs.ErrInvitationNotFound {
+			return invitation, err
+		}
+		logger.FromContext(ctx).Errorw("failed to get invitation", "error", err)
+		return invitation, errs.NewAppError(500, "failed to retrieve invitation", err)
+	}
+
+	return invitation, nil
+}
+
+// AcceptInvitation adds the user to the organization. The invitation must be
+// addressed to the user's email.
+func (s *OrganizationService) AcceptInvitation(ctx context.Context, user model.User, plainToken string) (model.Organization, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "OrganizationService.AcceptInvitation")
+	defer span.End()
+
+	invitation, err := s.GetInvitation(ctx, plainToken)
+	if err != nil {
+		return model.Organization{}, err
+	}
+
+	if !invitation.IsPending(time.Now()) {
+		return model.Organization{}, errs.ErrInvitationInvalid
+	}
+
+	if !strings.EqualFold(invitation.Email, user.Email) {
+		return model.Organization{}, errs.ErrInvitationNotFound
+	}
+
+	if err := s.organizationInvitationRepository.Accept(ctx, invitation, user.ID); err != nil {
+		if err == errs.ErrInvitationNotFound {
+			return model.Organization{}, errs.ErrInvitationInvalid
+		}
+		logger.FromContext(ctx).Errorw("failed to accept invitation", "invitation_id", invitation.ID, "user_id", user.ID, "error", err)
+		return model.Organization{}, errs.NewAppError(500, "failed to accept invitation", err)
+	}
+
+	logger.FromContext(ctx).Infow("invitation accepted", "invitation_id", invitation.ID, "user_id", user.ID)
+	return *invitation.Organization, nil
+}
+
+// DeclineInvitation marks the invitation as declined. Holding the token is
+// enough, so people without an account can decline too.
+func (s *OrganizationService) DeclineInvitation(ctx context.Context, plainToken string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "OrganizationService.DeclineInvitation")
+	defer span.End()
+
+	invitation, err := s.GetInvitation(ctx, plainToken)
+	if err != nil {
+		return err
+	}
+
+	if !invitation.IsPending(time.Now()) {
+		return errs.ErrInvitationInvalid
+	}
+
+	if err := s.organizationInvitationRepository.UpdateStatus(ctx, invitation.OrganizationID, invitation.ID, model.InvitationStatusDeclined); err != nil {
+		if err == errs.ErrInvitationNotFound {
+			return errs.ErrInvitationInvalid
+		}
+		logger.FromContext(ctx).Errorw("failed to decline invitation", "invitation_id", invitation.ID, "error", err)
+		return errs.NewAppError(500, "failed to decline invitation", err)
+	}
+
+	logger.FromContext(ctx).Infow("invitation declined", "invitation_id", invitation.ID)
+	return nil
+}
+
+// availableSlug validates the requested slug, or derives one from the name,
+// and checks that no other organization uses it.
+func (s *OrganizationService) availableSlug(ctx context.Context, slug string, name string, organizationID uuid.UUID) (string, error) {
+	if slug == "" {
+		slug = strings.Trim(slugInvalidChar.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	}
+
+	slug = strings.ToLower(slug)
+	if !slugPattern.MatchString(slug) {
+		fieldError := errs.NewFieldError("slug", errs.FieldSlug, "slug may only contain lowercase letters, digits and single dashes")
+		return "", errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+
+	existing, err := s.organizationRepository.GetBySlug(ctx, slug)
+	if err != nil && err != errs.ErrOrganizationNotFound {
+		logger.FromContext(ctx).Errorw("failed to check existing slug", "slug", slug, "error", err)
+		return "", errs.NewAppError(500, "failed to validate slug", err)
+	}
+	if err == nil && existing.ID != organizationID {
+		fieldError := errs.NewFieldError("slug", errs.FieldTaken, "slug already exists")
+		return "", errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+
+	return slug, nil
+}
+
+// ensureCanManageOwners allows owners of the organization and global admins,
+// by role or through a global group, to grant and revoke the owner role.
+func (s *OrganizationService) ensureCanManageOwners(ctx context.Context, organizationID uuid.UUID, actor model.User) error {
+	member, err := s.organizationMemberRepository.Get(ctx, organizationID, actor.ID)
+	switch {
+	case err == nil && member.Role == model.OrganizationRoleOwner:
+		return nil
+	case err != nil && err != errs.ErrOrganizationMemberNotFound:
+		logger.FromContext(ctx).Errorw("failed to get organization member", "organization_id", organizationID, "user_id", actor.ID, "error", err)
+		return errs.NewAppError(500, "failed to check organization role", err)
+	}
+
+	if actor.Role == model.UserRoleAdmin {
+		return nil
+	}
+
+	admin, err := s.groupRepository.HasGlobalRole(ctx, actor.ID, model.UserRoleAdmin)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to check group role", "user_id", actor.ID, "error", err)
+		return errs.NewAppError(500, "failed to check organization role", err)
+	}
+	if !admin {
+		return errs.ErrForbidden
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/Alfian57/belajar-golang/internal/tracing"
+	"github.com/Alfian57/belajar-golang/internal/utils/hash"
+	"github.com/Alfian57/belajar-golang/internal/utils/password"
+	"github.com/google/uuid"
+)
+
+type PasswordService struct {
+	passwordHistoryRepository *repository.PasswordHistoryRepository
+}
+
+func NewPasswordService(passwordHistoryRepository *repository.PasswordHistoryRepository) *PasswordService {
+	return &PasswordService{
+		passwordHistoryRepository: passwordHistoryRepository,
+	}
+}
+
+// Validate checks a new password for the given user against the password policy.
+// For existing users it also rejects the last N passwords when history is enabled.
+func (s *PasswordService) Validate(ctx context.Context, user model.User, newPassword string) error {
+	ctx, span := tracing.Start(ctx, "PasswordService.Validate")
+	defer span.End()
+
+	policy := password.Current
+
+	fieldErrors := policy.Validate(newPassword, user.Username, user.Email)
+	if len(fieldErrors) > 0 {
+		return errs.NewValidationError(fieldErrors)
+	}
+
+	if policy.HistorySize <= 0 || user.ID == uuid.Nil {
+		return nil
+	}
+
+	histories, err := s.passwordHistoryRepository.GetLatestByUserID(ctx, user.ID, policy.HistorySize)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get password history", "user_id", user.ID, "error", err)
+		return errs.NewAppError(500, "failed to validate password", err)
+	}
+
+	for _, history := range histories {
+		if hash.CheckPasswordHash(newPassword, history.PasswordHash) == nil {
+			fieldError := errs.NewFieldError("password", errs.FieldReused, "password has been used recently, choose a different one")
+			return errs.NewValidationError([]errs.FieldError{fieldError})
+		}
+	}
+
+	return nil
+}
+
+// Record stores the user's current password hash in the history and prunes
+// entries beyond the configured history size. It is best effort: callers run
+// it after the password is saved, so a failure is only logged and the
+// password is not checked against the history on its next change.
+func (s *PasswordService) Record(ctx context.Context, user model.User) {
+	ctx, span := tracing.Start(ctx, "PasswordService.Record")
+	defer span.End()
+
+	historySize := password.Current.HistorySize
+	if historySize <= 0 {
+		return
+	}
+
+	history := &model.PasswordHistory{
+		UserID:       user.ID,
+		PasswordHash: user.Password,
+	}
+	if err := s.passwordHistoryRepository.Create(ctx, history); err != nil {
+		logger.FromContext(ctx).Errorw("failed to save password history, the password will not be checked for reuse", "user_id", user.ID, "error", err)
+		return
+	}
+
+	if err := s.passwordHistoryRepository.DeleteAllExceptLatest(ctx, user.ID, historySize); err != nil {
+		logger.FromContext(ctx).Errorw("failed to prune password history", "user_id", user.ID, "error", err)
+	}
+}
+
+// Hash sets the password hash of the user. It runs in its own span because
+// bcrypt is deliberately slow and often the largest part of a request.
+func (s *PasswordService) Hash(ctx context.Context, user *model.User, plainPassword string) error {
+	_, span := tracing.Start(ctx, "PasswordService.Hash")
+	defer span.End()
+
+	return user.SetHashedPassword(plainPassword)
+}
+
+// Verify compares a plain password with the hash of the user, see Hash.
+func (s *PasswordService) Verify(ctx context.Context, user model.User, plainPassword string) error {
+	_, span := tracing.Start(ctx, "PasswordService.Verify")
+	defer span.End()
+
+	return user.CheckHashedPassword(plainPassword)
+}
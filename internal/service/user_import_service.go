@@ -0,0 +1,476 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/config"
+	"github.com/Alfian57/belajar-golang/internal/constants"
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/i18n"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/mailer"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/Alfian57/belajar-golang/internal/tracing"
+	"github.com/Alfian57/belajar-golang/internal/utils/password"
+	"github.com/Alfian57/belajar-golang/internal/utils/spreadsheet"
+	"github.com/Alfian57/belajar-golang/internal/utils/token"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// importTimeout bounds import jobs, which validate, hash and create up to
+// MaxRows users.
+const importTimeout = time.Hour
+
+// importPreviewRows is the number of rows a job keeps as a preview.
+const importPreviewRows = 10
+
+// importFields are the columns read from imported files, fields of
+// dto.CreateUserRequest.
+var importFields = []string{"email", "username", "password"}
+
+// ErrorDescriber renders an error the way clients get it, in the locale of
+// ctx. Import jobs outlive their request, so the handler passes
+// response.Describe for the rows that fail.
+type ErrorDescriber func(ctx context.Context, err error) (errs.Code, string, []errs.FieldError)
+
+type UserImportService struct {
+	userService              *UserService
+	jobRepository            *repository.UserImportJobRepository
+	magicLinkTokenRepository *repository.MagicLinkTokenRepository
+	// workers bounds the jobs running at once, sized when the first job
+	// starts since the configuration may not be loaded yet
+	workers func() chan struct{}
+}
+
+func NewUserImportService(userService *UserService, jobRepository *repository.UserImportJobRepository, magicLinkTokenRepository *repository.MagicLinkTokenRepository) *UserImportService {
+	return &UserImportService{
+		userService:              userService,
+		jobRepository:            jobRepository,
+		magicLinkTokenRepository: magicLinkTokenRepository,
+		workers: sync.OnceValue(func() chan struct{} {
+			return make(chan struct{}, config.Current().Import.Workers)
+		}),
+	}
+}
+
+// userImport is an import in progress.
+type userImport struct {
+	job      *model.UserImportJob
+	rows     []spreadsheet.Row
+	columns  map[string]int
+	generate bool
+	invite   bool
+	describe ErrorDescriber
+	// taken holds the emails and usernames of earlier valid rows, which
+	// dry runs do not create
+	taken claims
+}
+
+// invite is a created user to email a sign-in link, with the generated
+// password if any.
+type invite struct {
+	user     model.User
+	password string
+}
+
+// Import creates users from the rows of the uploaded file, each validated
+// like CreateUser. Files of up to SyncRows rows are imported before it
+// returns, larger ones by a background job the returned job reports the
+// progress of. Dry runs validate the rows without creating users.
+// signInURL is where invite emails point, describe renders row errors.
+func (s *UserImportService) Import(ctx context.Context, request dto.ImportUsersRequest, creator model.User, signInURL string, describe ErrorDescriber) (model.UserImportJob, error) {
+	ctx, cancel := context.WithTimeout(ctx, bulkTimeout)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "UserImportService.Import")
+	defer span.End()
+
+	rows, err := readImport(request)
+	if err != nil {
+		return model.UserImportJob{}, err
+	}
+
+	generate := request.Passwords == dto.ImportPasswordsGenerate
+	columns, err := mapColumns(rows[0].Cells, request.Mapping, generate)
+	if err != nil {
+		return model.UserImportJob{}, err
+	}
+
+	job := model.UserImportJob{
+		CreatedBy: &creator.ID,
+		Filename:  request.File.Filename,
+		DryRun:    request.DryRun,
+		Status:    model.ImportStatusPending,
+		TotalRows: len(rows) - 1,
+		Preview:   []model.ImportRow{},
+		Failures:  []model.ImportRow{},
+	}
+	imp := &userImport{
+		rows:     rows[1:],
+		columns:  columns,
+		generate: generate,
+		invite:   request.Invite && !request.DryRun,
+		describe: describe,
+		taken:    claims{},
+	}
+
+	if job.TotalRows > config.Current().Import.SyncRows {
+		if err := s.jobRepository.Create(ctx, &job); err != nil {
+			logger.FromContext(ctx).Errorw("failed to create import job", "error", err)
+			return model.UserImportJob{}, errs.NewAppError(500, "failed to import users", err)
+		}
+
+		background := job
+		imp.job = &background
+		go s.runJob(detach(ctx), imp, signInURL)
+
+		logger.FromContext(ctx).Infow("import job started", "job_id", job.ID, "rows", job.TotalRows)
+		return job, nil
+	}
+
+	imp.job = &job
+	job.Status = model.ImportStatusRunning
+	invites, err := s.run(ctx, imp, nil)
+	if err != nil {
+		return model.UserImportJob{}, err
+	}
+
+	finished := time.Now()
+	job.Status = model.ImportStatusCompleted
+	job.FinishedAt = &finished
+	if err := s.jobRepository.Create(ctx, &job); err != nil {
+		logger.FromContext(ctx).Errorw("failed to save import job", "error", err)
+		return model.UserImportJob{}, errs.NewAppError(500, "failed to import users", err)
+	}
+
+	// The users exist, mail them without holding the response
+	if len(invites) > 0 {
+		go s.sendInvites(detach(ctx), invites, signInURL)
+	}
+
+	logger.FromContext(ctx).Infow("users imported", "job_id", job.ID, "dry_run", job.DryRun, "succeeded", job.SucceededRows, "failed", job.FailedRows)
+	return job, nil
+}
+
+// GetJob returns an import job with its progress.
+func (s *UserImportService) GetJob(ctx context.Context, id uuid.UUID) (model.UserImportJob, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "UserImportService.GetJob")
+	defer span.End()
+
+	job, err := s.jobRepository.GetByID(ctx, id)
+	if err != nil {
+		if err == errs.ErrImportJobNotFound {
+			return model.UserImportJob{}, err
+		}
+		logger.FromContext(ctx).Errorw("failed to get import job", "job_id", id, "error", err)
+		return model.UserImportJob{}, errs.NewAppError(500, "failed to get import job", err)
+	}
+
+	return job, nil
+}
+
+// detach returns a context for work that outlives the request, with the
+// logger, locale and trace of ctx. Nothing else is kept: ctx may wrap the
+// *gin.Context of the request, which gin reuses for the next request once
+// the handler returns.
+func detach(ctx context.Context) context.Context {
+	background := logger.WithContext(context.Background(), logger.FromContext(ctx))
+	background = i18n.WithLocale(background, i18n.FromContext(ctx))
+	return trace.ContextWithSpanContext(background, trace.SpanContextFromContext(ctx))
+}
+
+// runJob runs an import in the background once a worker is free, saving
+// its progress after each chunk of rows.
+func (s *UserImportService) runJob(ctx context.Context, imp *userImport, signInURL string) {
+	workers := s.workers()
+	workers <- struct{}{}
+	defer func() { <-workers }()
+
+	ctx, cancel := context.WithTimeout(ctx, importTimeout)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "UserImportService.runJob")
+	defer span.End()
+
+	job := imp.job
+	job.Status = model.ImportStatusRunning
+	s.saveJob(ctx, job)
+
+	invites, err := s.run(ctx, imp, func() { s.saveJob(ctx, job) })
+
+	// Users created before a failure are invited all the same
+	s.sendInvites(context.WithoutCancel(ctx), invites, signInURL)
+
+	finished := time.Now()
+	job.Status = model.ImportStatusCompleted
+	job.FinishedAt = &finished
+	if err != nil {
+		job.Status = model.ImportStatusFailed
+		_, job.Error, _ = imp.describe(ctx, err)
+		logger.FromContext(ctx).Errorw("import job failed", "job_id", job.ID, "error", err)
+	}
+
+	// The job is saved even when it ran out of time
+	saveCtx, cancelSave := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancelSave()
+	s.saveJob(saveCtx, job)
+
+	logger.FromContext(ctx).Infow("import job finished", "job_id", job.ID, "status", job.Status, "succeeded", job.SucceededRows, "failed", job.FailedRows)
+}
+
+// saveJob saves the progress of a job. Failures are logged, the import
+// goes on without them.
+func (s *UserImportService) saveJob(ctx context.Context, job *model.UserImportJob) {
+	if err := s.jobRepository.Update(ctx, job); err != nil {
+		logger.FromContext(ctx).Errorw("failed to save import job", "job_id", job.ID, "error", err)
+	}
+}
+
+// run validates the rows a chunk at a time and, unless the job is a dry
+// run, creates the valid users, recording the outcome of each row in the
+// job. progress is called after each chunk. It returns the users to invite.
+func (s *UserImportService) run(ctx context.Context, imp *userImport, progress func()) ([]invite, error) {
+	job := imp.job
+
+	var invites []invite
+	for chunk := range slices.Chunk(imp.rows, config.Current().Bulk.ChunkSize) {
+		requests, err := imp.requests(chunk)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to generate passwords", "error", err)
+			return invites, errs.NewAppError(500, "failed to generate passwords", err)
+		}
+
+		result := dto.NewBulkResult(dto.BulkModeBestEffort, len(requests))
+		indexes, users, err := s.userService.validateNewUsers(ctx, &result, imp.taken, requests)
+		if err != nil {
+			return invites, err
+		}
+		if !job.DryRun {
+			if err := s.userService.createUsers(ctx, &result, indexes, users, requests); err != nil {
+				return invites, err
+			}
+		}
+
+		for i, item := range result.Items {
+			row := model.ImportRow{
+				Number:   chunk[i].Number,
+				Email:    requests[i].Email,
+				Username: requests[i].Username,
+				ID:       item.ID,
+			}
+			switch {
+			case item.Err != nil:
+				row.Status = model.ImportRowFailed
+				row.Code, row.Message, row.Errors = imp.describe(ctx, item.Err)
+				job.Failures = append(job.Failures, row)
+			case job.DryRun:
+				row.Status = model.ImportRowValid
+			default:
+				row.Status = model.ImportRowCreated
+				if imp.invite {
+					user := model.User{ID: item.ID, Email: row.Email, Username: row.Username}
+					invite := invite{user: user}
+					if imp.generate {
+						invite.password = requests[i].Password
+					}
+					invites = append(invites, invite)
+				}
+			}
+
+			if len(job.Preview) < importPreviewRows {
+				job.Preview = append(job.Preview, row)
+			}
+		}
+
+		job.ProcessedRows += len(chunk)
+		job.SucceededRows += len(chunk) - result.Failed
+		job.FailedRows += result.Failed
+		if progress != nil {
+			progress()
+		}
+	}
+
+	return invites, nil
+}
+
+// requests reads the create request of each row. Generated passwords
+// replace the password column.
+func (imp *userImport) requests(rows []spreadsheet.Row) ([]dto.CreateUserRequest, error) {
+	requests := make([]dto.CreateUserRequest, len(rows))
+	for i, row := range rows {
+		request := dto.CreateUserRequest{
+			Email:    strings.TrimSpace(imp.cell(row, "email")),
+			Username: strings.TrimSpace(imp.cell(row, "username")),
+			Password: imp.cell(row, "password"),
+		}
+		if imp.generate {
+			generated, err := password.Current.Generate()
+			if err != nil {
+				return nil, err
+			}
+			request.Password = generated
+		}
+
+		// Files have no confirmation column
+		request.PasswordConfirmation = request.Password
+		requests[i] = request
+	}
+
+	return requests, nil
+}
+
+// cell returns the value of field in row, empty for rows shorter than the
+// header.
+func (imp *userImport) cell(row spreadsheet.Row, field string) string {
+	index, ok := imp.columns[field]
+	if !ok || index >= len(row.Cells) {
+		return ""
+	}
+	return row.Cells[index]
+}
+
+// readImport checks the size and format of the uploaded file and reads its
+// rows, the first being the header.
+func readImport(request dto.ImportUsersRequest) ([]spreadsheet.Row, error) {
+	limits := config.Current().Import
+	if request.File.Size > limits.MaxFileSize {
+		return nil, ImportFileTooLarge()
+	}
+
+	format := request.Format
+	if format == "" {
+		format = spreadsheet.FormatOf(request.File.Filename)
+	}
+	if format == "" {
+		fieldError := errs.NewFieldError("format", errs.FieldInvalid, "format is required for files without a .csv or .xlsx extension")
+		return nil, errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+
+	file, err := request.File.Open()
+	if err != nil {
+		return nil, errs.NewAppError(500, "failed to read file", err)
+	}
+	defer file.Close()
+
+	// One more row for the header
+	rows, err := spreadsheet.Read(file, format, limits.MaxRows+1)
+	if errors.Is(err, spreadsheet.ErrTooManyRows) {
+		message := fmt.Sprintf("file must have at most %d rows", limits.MaxRows)
+		fieldError := errs.NewFieldError("file", errs.FieldTooMany, message).WithParam(strconv.Itoa(limits.MaxRows))
+		return nil, errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+	if err != nil {
+		fieldError := errs.NewFieldError("file", errs.FieldInvalid, "file is not a valid "+format+" file")
+		return nil, errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+	if len(rows) == 0 {
+		fieldError := errs.NewFieldError("file", errs.FieldInvalid, "file has no header row")
+		return nil, errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+
+	return rows, nil
+}
+
+// ImportFileTooLarge is the error of uploads larger than MaxFileSize.
+func ImportFileTooLarge() error {
+	maxSize := config.Current().Import.MaxFileSize
+	message := fmt.Sprintf("file must be at most %d bytes", maxSize)
+	fieldError := errs.NewFieldError("file", errs.FieldTooLarge, message).WithParam(strconv.FormatInt(maxSize, 10))
+	return errs.NewValidationError([]errs.FieldError{fieldError})
+}
+
+// mapColumns returns the column of each import field in header, found by
+// the header mapping names or else by the field name, in any case. The
+// password column is not read when passwords are generated.
+func mapColumns(header []string, mapping map[string]string, generate bool) (map[string]int, error) {
+	var fieldErrors []errs.FieldError
+	for _, field := range slices.Sorted(maps.Keys(mapping)) {
+		if !slices.Contains(importFields, field) {
+			message := fmt.Sprintf("mapping has unknown field %s, expected one of %s", field, strings.Join(importFields, ", "))
+			fieldErrors = append(fieldErrors, errs.NewFieldError("mapping", errs.FieldUnknown, message).WithParam(field))
+		}
+	}
+
+	columns := make(map[string]int, len(importFields))
+	for _, field := range importFields {
+		if field == "password" && generate {
+			continue
+		}
+
+		name, ok := mapping[field]
+		if !ok {
+			name = field
+		}
+		index := slices.IndexFunc(header, func(cell string) bool {
+			return strings.EqualFold(strings.TrimSpace(cell), strings.TrimSpace(name))
+		})
+		if index < 0 {
+			message := fmt.Sprintf("column %s of %s is not in the header row", name, field)
+			fieldErrors = append(fieldErrors, errs.NewFieldError("mapping."+field, errs.FieldNotFound, message).WithParam(name))
+			continue
+		}
+		columns[field] = index
+	}
+
+	if len(fieldErrors) > 0 {
+		return nil, errs.NewValidationError(fieldErrors)
+	}
+	return columns, nil
+}
+
+// sendInvites emails each invited user a single-use sign-in link that lasts
+// as long as an invitation, with their generated password if any. Failures
+// are logged, the users exist either way.
+func (s *UserImportService) sendInvites(ctx context.Context, invites []invite, signInURL string) {
+	for _, invite := range invites {
+		if err := s.sendInvite(ctx, invite, signInURL); err != nil {
+			logger.FromContext(ctx).Errorw("failed to send import invite", "id", invite.user.ID, "error", err)
+		}
+	}
+}
+
+func (s *UserImportService) sendInvite(ctx context.Context, invite invite, signInURL string) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	plainToken, err := token.Generate()
+	if err != nil {
+		return err
+	}
+
+	magicLink := &model.MagicLinkToken{
+		UserID:    invite.user.ID,
+		Email:     invite.user.Email,
+		TokenHash: token.Hash(plainToken),
+		ExpiresAt: time.Now().Add(constants.InvitationTTL),
+	}
+	if err := s.magicLinkTokenRepository.Create(ctx, magicLink); err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"Hi %s,\n\nAn account was created for you. Use the link below to sign in. It expires in %d days and can only be used once.\n\n%s?token=%s\n",
+		invite.user.Username, int(constants.InvitationTTL.Hours()/24), signInURL, url.QueryEscape(plainToken),
+	)
+	if invite.password != "" {
+		body += fmt.Sprintf("\nYour password is %s, change it once you are signed in.\n", invite.password)
+	}
+
+	return mailer.Send(ctx, mailer.Message{
+		To:      invite.user.Email,
+		Subject: "Your account is ready",
+		Body:    body,
+	})
+}
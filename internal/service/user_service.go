@@ -2,23 +2,41 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/Alfian57/belajar-golang/internal/config"
 	"github.com/Alfian57/belajar-golang/internal/dto"
 	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/listing"
 	"github.com/Alfian57/belajar-golang/internal/logger"
 	"github.com/Alfian57/belajar-golang/internal/model"
 	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/Alfian57/belajar-golang/internal/tracing"
+	"github.com/Alfian57/belajar-golang/internal/utils/cursor"
+	"github.com/Alfian57/belajar-golang/internal/utils/search"
+	"github.com/Alfian57/belajar-golang/internal/utils/spreadsheet"
+	"github.com/Alfian57/belajar-golang/internal/validation"
 	"github.com/google/uuid"
 )
 
 type UserService struct {
-	userRepository *repository.UserRepository
+	userRepository  *repository.UserRepository
+	passwordService *PasswordService
 }
 
-func NewUserService(r *repository.UserRepository) *UserService {
+func NewUserService(r *repository.UserRepository, passwordService *PasswordService) *UserService {
 	return &UserService{
-		userRepository: r,
+		userRepository:  r,
+		passwordService: passwordService,
 	}
 }
 
@@ -27,36 +45,94 @@ func NewUserService(r *repository.UserRepository) *UserService {
 func (s *UserService) GetAllUsers(ctx context.Context, query dto.GetUsersFilter) (dto.PaginatedResult[model.User], error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
+	ctx, span := tracing.Start(ctx, "UserService.GetAllUsers")
+	defer span.End()
 
-	// Set default ordering
-	orderBy := query.OrderBy
-	if orderBy == "" {
-		orderBy = "created_at"
+	sort := requestedSort(query)
+
+	// The cursor keeps the sort it was created with
+	var position cursor.Cursor
+	if query.Cursor != "" {
+		var err error
+		position, err = cursor.Decode(query.Cursor)
+		if err != nil {
+			return dto.PaginatedResult[model.User]{}, invalidCursor()
+		}
+		sort = position.Sort
 	}
-	orderType := query.OrderType
-	if orderType != "ASC" && orderType != "DESC" {
-		orderType = "ASC"
+
+	list, err := dto.UserListing.Parse(query.Filter, sort, query.Fields)
+	if err != nil {
+		return dto.PaginatedResult[model.User]{}, err
 	}
 
 	// Validate pagination parameters (SetDefaults should be called before this)
 	limit := query.PaginationRequest.Limit
-	offset := query.PaginationRequest.GetOffset()
 
-	users, err := s.userRepository.GetAllWithFilterPagination(ctx, query.Search, orderBy, orderType, limit, offset)
-	if err != nil {
-		logger.Log.Errorw("failed to retrieve users", "error", err)
-		return dto.PaginatedResult[model.User]{}, errs.NewAppError(500, "failed to retrieve users", err)
+	// One extra row tells whether there is another page without counting
+	var users []model.User
+	if query.Cursor != "" {
+		keyset, err := list.Keyset(position.Values, position.Backward)
+		if err != nil {
+			return dto.PaginatedResult[model.User]{}, invalidCursor()
+		}
+
+		users, err = s.userRepository.GetAllWithFilterKeyset(ctx, query.Search, list, keyset, limit+1)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to retrieve users", "error", err)
+			return dto.PaginatedResult[model.User]{}, errs.NewAppError(500, "failed to retrieve users", err)
+		}
+	} else {
+		users, err = s.userRepository.GetAllWithFilterPagination(ctx, query.Search, list, limit+1, query.PaginationRequest.GetOffset())
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to retrieve users", "error", err)
+			return dto.PaginatedResult[model.User]{}, errs.NewAppError(500, "failed to retrieve users", err)
+		}
+	}
+
+	hasMore := len(users) > limit
+	if hasMore {
+		users = users[:limit]
+	}
+	if position.Backward {
+		slices.Reverse(users)
+	}
+
+	// Create pagination response, a backward page was reached going back
+	// from the next one
+	pagination := dto.PaginationResponse{
+		Page:    query.Page,
+		Limit:   limit,
+		HasNext: hasMore,
+		HasPrev: query.Page > 1,
+	}
+	if query.Cursor != "" {
+		pagination = dto.PaginationResponse{
+			Limit:   limit,
+			HasNext: hasMore || position.Backward,
+			HasPrev: hasMore || !position.Backward,
+		}
 	}
 
 	// Count total users for pagination
-	count, err := s.userRepository.CountWithFilter(ctx, query.Search)
-	if err != nil {
-		logger.Log.Errorw("failed to count users", "error", err)
+	if query.CountTotal() {
+		count, err := s.userRepository.CountWithFilter(ctx, query.Search, list)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to count users", "error", err)
+			return dto.PaginatedResult[model.User]{}, errs.NewAppError(500, "failed to retrieve users", err)
+		}
+		if query.Cursor == "" {
+			pagination = dto.NewPaginationResponse(query.Page, limit, count)
+		} else {
+			pagination = pagination.WithTotal(count)
+		}
+	}
+
+	if err := setCursors(&pagination, users, list); err != nil {
+		logger.FromContext(ctx).Errorw("failed to encode cursors", "error", err)
 		return dto.PaginatedResult[model.User]{}, errs.NewAppError(500, "failed to retrieve users", err)
 	}
 
-	// Create pagination response
-	pagination := dto.NewPaginationResponse(query.Page, query.Limit, count)
 	result := dto.PaginatedResult[model.User]{
 		Data:       users,
 		Pagination: pagination,
@@ -65,34 +141,202 @@ func (s *UserService) GetAllUsers(ctx context.Context, query dto.GetUsersFilter)
 	return result, nil
 }
 
+// setCursors points the next cursor after the last user and the previous
+// cursor before the first one.
+func setCursors(pagination *dto.PaginationResponse, users []model.User, list listing.Query) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	if pagination.HasNext {
+		values, err := list.Position(users[len(users)-1])
+		if err != nil {
+			return err
+		}
+		pagination.NextCursor, err = cursor.Encode(cursor.Cursor{Sort: list.Sorting(), Values: values})
+		if err != nil {
+			return err
+		}
+	}
+	if pagination.HasPrev {
+		values, err := list.Position(users[0])
+		if err != nil {
+			return err
+		}
+		pagination.PrevCursor, err = cursor.Encode(cursor.Cursor{Sort: list.Sorting(), Values: values, Backward: true})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// requestedSort returns the sort of a listing, which falls back to the
+// older order_by and order_type.
+func requestedSort(query dto.GetUsersFilter) string {
+	if query.Sort != "" || query.OrderBy == "" {
+		return query.Sort
+	}
+
+	if strings.EqualFold(query.OrderType, "DESC") {
+		return "-" + query.OrderBy
+	}
+	return query.OrderBy
+}
+
+func invalidCursor() error {
+	fieldError := errs.NewFieldError("cursor", errs.FieldInvalid, "cursor is invalid")
+	return errs.NewValidationError([]errs.FieldError{fieldError})
+}
+
+// exportTimeout bounds exports, which read every matching user.
+const exportTimeout = 10 * time.Minute
+
+// exportBatchSize is the number of users an export reads per query.
+const exportBatchSize = 500
+
+// ExportUsers writes the users matching the request to w as a CSV or XLSX
+// file, a header row naming the fields and a row per user. Users are read in
+// keyset batches and written as they come, so memory does not grow with
+// the number of users. Nothing is written when the request is invalid.
+func (s *UserService) ExportUsers(ctx context.Context, request dto.ExportUsersRequest, w io.Writer) error {
+	ctx, cancel := context.WithTimeout(ctx, exportTimeout)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "UserService.ExportUsers")
+	defer span.End()
+
+	list, err := dto.UserListing.Parse(request.Filter, requestedSort(request.GetUsersFilter), request.Fields)
+	if err != nil {
+		return err
+	}
+
+	fields := request.Fields
+	if fields == "" {
+		names := make([]string, len(dto.UserListing.Fields))
+		for i, field := range dto.UserListing.Fields {
+			names[i] = field.Name
+		}
+		fields = strings.Join(names, ",")
+	}
+	writer, err := spreadsheet.NewWriter(w, request.Format)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to start export", "error", err)
+		return errs.NewAppError(500, "failed to export users", err)
+	}
+
+	count, err := s.writeUsers(ctx, writer, request.Search, list, fields)
+	if err != nil {
+		writer.Discard()
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	logger.FromContext(ctx).Infow("users exported", "format", request.Format, "count", count)
+	return nil
+}
+
+// writeUsers writes a header row and the fields of the users matching
+// search and list, batch after batch in the order of list.
+func (s *UserService) writeUsers(ctx context.Context, writer spreadsheet.Writer, search string, list listing.Query, fields string) (int, error) {
+	columns := strings.Split(fields, ",")
+	if err := writer.Write(columns); err != nil {
+		return 0, fmt.Errorf("failed to write export: %w", err)
+	}
+
+	count := 0
+	var last *model.User
+	for {
+		users, err := s.exportBatch(ctx, search, list, last)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to retrieve exported users", "error", err)
+			return count, errs.NewAppError(500, "failed to export users", err)
+		}
+
+		rows, err := listing.Select(users, fields)
+		if err != nil {
+			return count, fmt.Errorf("failed to select exported fields: %w", err)
+		}
+		for _, row := range rows {
+			cells := make([]string, len(columns))
+			for i, column := range columns {
+				if value := row[column]; value != nil {
+					cells[i] = fmt.Sprint(value)
+				}
+			}
+			if err := writer.Write(cells); err != nil {
+				return count, fmt.Errorf("failed to write export: %w", err)
+			}
+		}
+		count += len(users)
+
+		if len(users) < exportBatchSize {
+			return count, nil
+		}
+		last = &users[len(users)-1]
+	}
+}
+
+// exportBatch returns the users after last in the order of list, like the
+// next cursor of a listing does, or the first ones without last.
+func (s *UserService) exportBatch(ctx context.Context, search string, list listing.Query, last *model.User) ([]model.User, error) {
+	if last == nil {
+		return s.userRepository.GetAllWithFilterPagination(ctx, search, list, exportBatchSize, 0)
+	}
+
+	values, err := list.Position(*last)
+	if err != nil {
+		return nil, err
+	}
+	keyset, err := list.Keyset(values, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.userRepository.GetAllWithFilterKeyset(ctx, search, list, keyset, exportBatchSize)
+}
+
 // CreateUser creates a new user with the provided request data.
 // It checks for existing usernames and hashes the password before saving.
 func (s *UserService) CreateUser(ctx context.Context, request dto.CreateUserRequest) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
+	ctx, span := tracing.Start(ctx, "UserService.CreateUser")
+	defer span.End()
 
-	// Check if email already exists
-	_, err := s.userRepository.GetByEmail(ctx, request.Email)
-	if err != nil && err != errs.ErrUserNotFound {
-		logger.Log.Errorw("failed to check existing email", "email", request.Email, "error", err)
-		return errs.NewAppError(500, "failed to validate email", err)
+	user, err := s.newUser(ctx, request)
+	if err != nil {
+		return err
 	}
-	if err == nil {
-		logger.Log.Infow("email already exists", "email", request.Email)
-		fieldError := errs.NewFieldError("email", "email already exists")
-		return errs.NewValidationError([]errs.FieldError{fieldError})
+
+	// Password processing
+	err = s.passwordService.Hash(ctx, &user, request.Password)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to hash password", "error", err)
+		return errs.NewAppError(500, "failed to process password", err)
 	}
 
-	// Check if username already exists
-	_, err = s.userRepository.GetByUsername(ctx, request.Username)
-	if err != nil && err != errs.ErrUserNotFound {
-		logger.Log.Errorw("failed to check existing username", "username", request.Username, "error", err)
-		return errs.NewAppError(500, "failed to validate username", err)
+	// Set user email and role
+	if err := s.userRepository.Create(ctx, &user); err != nil {
+		logger.FromContext(ctx).Errorw("failed to create user", "username", request.Username, "error", err)
+		return errs.NewAppError(500, "failed to create user", err)
 	}
-	if err == nil {
-		logger.Log.Infow("username already exists", "username", request.Username)
-		fieldError := errs.NewFieldError("username", "username already exists")
-		return errs.NewValidationError([]errs.FieldError{fieldError})
+
+	// Password history is best effort once the user exists
+	s.passwordService.Record(ctx, user)
+
+	logger.FromContext(ctx).Infow("user created successfully", "username", request.Username)
+	return nil
+}
+
+// newUser validates a create request, including the uniqueness of email and
+// username and the password policy, and returns the member to create with
+// the password not hashed yet.
+func (s *UserService) newUser(ctx context.Context, request dto.CreateUserRequest) (model.User, error) {
+	// Check if email and username already exist
+	if err := validation.Struct(ctx, request); err != nil {
+		return model.User{}, err
 	}
 
 	user := model.User{
@@ -100,28 +344,63 @@ func (s *UserService) CreateUser(ctx context.Context, request dto.CreateUserRequ
 		Username: request.Username,
 		Role:     model.UserRoleMember, // Default role
 	}
+	if err := s.passwordService.Validate(ctx, user, request.Password); err != nil {
+		return model.User{}, err
+	}
 
-	// Password processing
-	err = user.SetHashedPassword(request.Password)
+	return user, nil
+}
+
+// SearchUsers finds users by username and email, best matches first, with
+// the matched fragments of both fields.
+func (s *UserService) SearchUsers(ctx context.Context, request dto.SearchUsersRequest) ([]dto.UserSearchResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "UserService.SearchUsers")
+	defer span.End()
+
+	results := []dto.UserSearchResult{}
+	terms := search.Terms(request.Query)
+	if len(terms) == 0 {
+		return results, nil
+	}
+
+	// Full-text matches words being typed by prefix, fuzzy matching
+	// tolerates typos, auto combines both
+	var query, text string
+	if request.Mode != "fuzzy" {
+		query = search.PrefixQuery(terms)
+	}
+	if request.Mode != "fulltext" {
+		text = strings.Join(terms, " ")
+	}
+
+	matches, err := s.userRepository.Search(ctx, query, text, config.Current().Search.FuzzyThreshold, request.Limit)
 	if err != nil {
-		logger.Log.Errorw("failed to hash password", "error", err)
-		return errs.NewAppError(500, "failed to process password", err)
+		logger.FromContext(ctx).Errorw("failed to search users", "error", err)
+		return nil, errs.NewAppError(500, "failed to search users", err)
 	}
 
-	// Set user email and role
-	if err := s.userRepository.Create(ctx, &user); err != nil {
-		logger.Log.Errorw("failed to create user", "username", request.Username, "error", err)
-		return errs.NewAppError(500, "failed to create user", err)
+	for _, match := range matches {
+		results = append(results, dto.UserSearchResult{
+			User: match.User,
+			Rank: match.Rank,
+			Highlights: map[string][]search.Fragment{
+				"username": search.Highlight(match.Username, terms, text != ""),
+				"email":    search.Highlight(match.Email, terms, text != ""),
+			},
+		})
 	}
 
-	logger.Log.Infow("user created successfully", "username", request.Username)
-	return nil
+	return results, nil
 }
 
 // GetUserByID retrieves a user by their ID.
 func (s *UserService) GetUserByID(ctx context.Context, id string) (model.User, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
+	ctx, span := tracing.Start(ctx, "UserService.GetUserByID")
+	defer span.End()
 
 	// Check if the user exists
 	user, err := s.userRepository.GetByID(ctx, id)
@@ -129,7 +408,7 @@ func (s *UserService) GetUserByID(ctx context.Context, id string) (model.User, e
 		if err == errs.ErrUserNotFound {
 			return model.User{}, err
 		}
-		logger.Log.Errorw("failed to get user by ID", "id", id, "error", err)
+		logger.FromContext(ctx).Errorw("failed to get user by ID", "id", id, "error", err)
 		return model.User{}, errs.NewAppError(500, "failed to retrieve user", err)
 	}
 	return user, nil
@@ -138,6 +417,8 @@ func (s *UserService) GetUserByID(ctx context.Context, id string) (model.User, e
 func (s *UserService) UpdateUser(ctx context.Context, request dto.UpdateUserRequest) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
+	ctx, span := tracing.Start(ctx, "UserService.UpdateUser")
+	defer span.End()
 
 	// Validate user existence
 	_, err := s.userRepository.GetByID(ctx, request.ID.String())
@@ -146,30 +427,13 @@ func (s *UserService) UpdateUser(ctx context.Context, request dto.UpdateUserRequ
 			return err
 		}
 
-		logger.Log.Errorw("failed to check user existence for update", "id", request.ID, "error", err)
+		logger.FromContext(ctx).Errorw("failed to check user existence for update", "id", request.ID, "error", err)
 		return errs.NewAppError(500, "failed to validate user", err)
 	}
 
-	// Check if email already exists
-	existingUser, err := s.userRepository.GetByEmail(ctx, request.Email)
-	if err != nil && err != errs.ErrUserNotFound {
-		logger.Log.Errorw("failed to check email availability", "email", request.Email, "error", err)
-		return errs.NewAppError(500, "failed to validate email", err)
-	}
-	if err == nil && existingUser.ID != request.ID {
-		fieldError := errs.NewFieldError("email", "email already exists")
-		return errs.NewValidationError([]errs.FieldError{fieldError})
-	}
-
-	// Check if username already exists
-	existingUser, err = s.userRepository.GetByUsername(ctx, request.Username)
-	if err != nil && err != errs.ErrUserNotFound {
-		logger.Log.Errorw("failed to check username availability", "username", request.Username, "error", err)
-		return errs.NewAppError(500, "failed to validate username", err)
-	}
-	if err == nil && existingUser.ID != request.ID {
-		fieldError := errs.NewFieldError("username", "username already exists")
-		return errs.NewValidationError([]errs.FieldError{fieldError})
+	// Check if email and username are taken by another user
+	if err := validation.Struct(ctx, request); err != nil {
+		return err
 	}
 
 	// Prepare user data for update
@@ -181,11 +445,11 @@ func (s *UserService) UpdateUser(ctx context.Context, request dto.UpdateUserRequ
 
 	// Update user password if provided
 	if err := s.userRepository.Update(ctx, &user); err != nil {
-		logger.Log.Errorw("failed to update user", "id", request.ID, "error", err)
+		logger.FromContext(ctx).Errorw("failed to update user", "id", request.ID, "error", err)
 		return errs.NewAppError(500, "failed to update user", err)
 	}
 
-	logger.Log.Infow("user updated successfully", "id", request.ID)
+	logger.FromContext(ctx).Infow("user updated successfully", "id", request.ID)
 	return nil
 }
 
@@ -194,16 +458,385 @@ func (s *UserService) UpdateUser(ctx context.Context, request dto.UpdateUserRequ
 func (s *UserService) DeleteUser(ctx context.Context, id uuid.UUID) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
+	ctx, span := tracing.Start(ctx, "UserService.DeleteUser")
+	defer span.End()
 
 	// Check if the user exists
 	if err := s.userRepository.Delete(ctx, id.String()); err != nil {
-		if err == errs.ErrUserNotFound {
+		if err == errs.ErrUserNotFound || err == errs.ErrUserReferenced {
 			return err
 		}
-		logger.Log.Errorw("failed to delete user", "id", id, "error", err)
+		logger.FromContext(ctx).Errorw("failed to delete user", "id", id, "error", err)
 		return errs.NewAppError(500, "failed to delete user", err)
 	}
 
-	logger.Log.Infow("user deleted successfully", "id", id)
+	logger.FromContext(ctx).Infow("user deleted successfully", "id", id)
+	return nil
+}
+
+// bulkTimeout bounds bulk operations, which validate every item against the
+// database and hash a password per created user.
+const bulkTimeout = 2 * time.Minute
+
+// BulkCreateUsers creates the users of the request, validated like
+// CreateUser. Atomic requests create every user or none, best effort ones
+// create the valid users and report the others.
+func (s *UserService) BulkCreateUsers(ctx context.Context, request dto.BulkCreateUsersRequest) (dto.BulkResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, bulkTimeout)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "UserService.BulkCreateUsers")
+	defer span.End()
+
+	if err := checkBulkSize("users", len(request.Users)); err != nil {
+		return dto.BulkResult{}, err
+	}
+	result := dto.NewBulkResult(request.Mode, len(request.Users))
+
+	indexes, users, err := s.validateNewUsers(ctx, &result, claims{}, request.Users)
+	if err != nil {
+		return dto.BulkResult{}, err
+	}
+	if result.Atomic() && result.Failed > 0 {
+		result.Skip()
+		return result, nil
+	}
+
+	if err := s.createUsers(ctx, &result, indexes, users, request.Users); err != nil {
+		return dto.BulkResult{}, err
+	}
+
+	logger.FromContext(ctx).Infow("users created in bulk", "mode", result.Mode, "succeeded", result.Succeeded, "failed", result.Failed)
+	return result, nil
+}
+
+// validateNewUsers validates requests like newUser, failing the items whose
+// email or username is in taken, and claims the values of the valid ones.
+// It returns the users to create with their indexes in requests.
+func (s *UserService) validateNewUsers(ctx context.Context, result *dto.BulkResult, taken claims, requests []dto.CreateUserRequest) ([]int, []model.User, error) {
+	var indexes []int
+	var users []model.User
+	for i, item := range requests {
+		values := map[string]string{"email": item.Email, "username": item.Username}
+		if err := taken.check(values); err != nil {
+			result.Fail(i, err)
+			continue
+		}
+
+		user, err := s.newUser(ctx, item)
+		if isServerError(err) {
+			return nil, nil, err
+		}
+		if err != nil {
+			result.Fail(i, err)
+			continue
+		}
+
+		taken.claim(values)
+		indexes = append(indexes, i)
+		users = append(users, user)
+	}
+
+	return indexes, users, nil
+}
+
+// createUsers hashes the passwords of users, validated by validateNewUsers,
+// and creates them.
+func (s *UserService) createUsers(ctx context.Context, result *dto.BulkResult, indexes []int, users []model.User, requests []dto.CreateUserRequest) error {
+	// Hashing is the slow part, keep the users whose password hashed
+	hashErrs := s.hashPasswords(ctx, users, indexes, requests)
+	hashed, hashedIndexes := users[:0], indexes[:0]
+	for j, err := range hashErrs {
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to hash password", "index", indexes[j], "error", err)
+			result.Fail(indexes[j], errs.NewAppError(500, "failed to process password", err))
+			continue
+		}
+		hashed = append(hashed, users[j])
+		hashedIndexes = append(hashedIndexes, indexes[j])
+	}
+	if result.Atomic() && result.Failed > 0 {
+		result.Skip()
+		return nil
+	}
+
+	chunkSize := config.Current().Bulk.ChunkSize
+	return bulkWrite(ctx, result, hashedIndexes, hashed, "failed to create users", func(chunk []model.User) error {
+		return s.userRepository.CreateMany(ctx, chunk, chunkSize)
+	}, func(i int, user model.User) {
+		result.Succeed(i, user.ID, dto.BulkStatusCreated)
+
+		// Password history is best effort once the user exists
+		s.passwordService.Record(ctx, user)
+	})
+}
+
+// BulkUpdateUsers updates the users of the request by the ID of each item,
+// validated like UpdateUser.
+func (s *UserService) BulkUpdateUsers(ctx context.Context, request dto.BulkUpdateUsersRequest) (dto.BulkResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, bulkTimeout)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "UserService.BulkUpdateUsers")
+	defer span.End()
+
+	if err := checkBulkSize("users", len(request.Users)); err != nil {
+		return dto.BulkResult{}, err
+	}
+	result := dto.NewBulkResult(request.Mode, len(request.Users))
+
+	ids := make([]uuid.UUID, len(request.Users))
+	for i, item := range request.Users {
+		ids[i] = item.ID
+	}
+	existing, err := s.existingUsers(ctx, ids)
+	if err != nil {
+		return dto.BulkResult{}, err
+	}
+
+	taken := claims{}
+	var indexes []int
+	var users []model.User
+	for i, item := range request.Users {
+		if err := checkBulkID(taken, existing, item.ID); err != nil {
+			result.Fail(i, err)
+			continue
+		}
+
+		// Check if email and username are taken by another user
+		values := map[string]string{"email": item.Email, "username": item.Username}
+		if err := taken.check(values); err != nil {
+			result.Fail(i, err)
+			continue
+		}
+		err := validation.Struct(ctx, item)
+		if isServerError(err) {
+			return dto.BulkResult{}, err
+		}
+		if err != nil {
+			result.Fail(i, err)
+			continue
+		}
+
+		taken.claim(values)
+		indexes = append(indexes, i)
+		users = append(users, model.User{ID: item.ID, Email: item.Email, Username: item.Username})
+	}
+	if result.Atomic() && result.Failed > 0 {
+		result.Skip()
+		return result, nil
+	}
+
+	err = bulkWrite(ctx, &result, indexes, users, "failed to update users", func(chunk []model.User) error {
+		return s.userRepository.UpdateMany(ctx, chunk)
+	}, func(i int, user model.User) {
+		result.Succeed(i, user.ID, dto.BulkStatusUpdated)
+	})
+	if err != nil {
+		return dto.BulkResult{}, err
+	}
+
+	logger.FromContext(ctx).Infow("users updated in bulk", "mode", result.Mode, "succeeded", result.Succeeded, "failed", result.Failed)
+	return result, nil
+}
+
+// BulkDeleteUsers deletes the users with the IDs of the request.
+func (s *UserService) BulkDeleteUsers(ctx context.Context, request dto.BulkDeleteUsersRequest) (dto.BulkResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, bulkTimeout)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "UserService.BulkDeleteUsers")
+	defer span.End()
+
+	if err := checkBulkSize("ids", len(request.IDs)); err != nil {
+		return dto.BulkResult{}, err
+	}
+	result := dto.NewBulkResult(request.Mode, len(request.IDs))
+
+	existing, err := s.existingUsers(ctx, request.IDs)
+	if err != nil {
+		return dto.BulkResult{}, err
+	}
+
+	taken := claims{}
+	var indexes []int
+	var ids []uuid.UUID
+	for i, id := range request.IDs {
+		if err := checkBulkID(taken, existing, id); err != nil {
+			result.Fail(i, err)
+			continue
+		}
+		indexes = append(indexes, i)
+		ids = append(ids, id)
+	}
+	if result.Atomic() && result.Failed > 0 {
+		result.Skip()
+		return result, nil
+	}
+
+	chunkSize := config.Current().Bulk.ChunkSize
+	err = bulkWrite(ctx, &result, indexes, ids, "failed to delete users", func(chunk []uuid.UUID) error {
+		return s.userRepository.DeleteMany(ctx, chunk, chunkSize)
+	}, func(i int, id uuid.UUID) {
+		result.Succeed(i, id, dto.BulkStatusDeleted)
+	})
+	if err != nil {
+		return dto.BulkResult{}, err
+	}
+
+	logger.FromContext(ctx).Infow("users deleted in bulk", "mode", result.Mode, "succeeded", result.Succeeded, "failed", result.Failed)
+	return result, nil
+}
+
+// existingUsers returns the IDs of ids that belong to a user.
+func (s *UserService) existingUsers(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	users, err := s.userRepository.GetByIDs(ctx, ids)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to check user existence", "error", err)
+		return nil, errs.NewAppError(500, "failed to validate users", err)
+	}
+
+	existing := make(map[uuid.UUID]bool, len(users))
+	for _, user := range users {
+		existing[user.ID] = true
+	}
+	return existing, nil
+}
+
+// checkBulkID checks that an item targets a user no earlier item did, and
+// claims it.
+func checkBulkID(taken claims, existing map[uuid.UUID]bool, id uuid.UUID) error {
+	if id == uuid.Nil {
+		fieldError := errs.NewFieldError("id", "required", "id is required")
+		return errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+
+	values := map[string]string{"id": id.String()}
+	if err := taken.check(values); err != nil {
+		return err
+	}
+	if !existing[id] {
+		return errs.ErrUserNotFound
+	}
+
+	taken.claim(values)
+	return nil
+}
+
+// hashPasswords hashes the password of each user on every CPU, bcrypt being
+// slow by design. indexes are the positions of users in requests. It
+// returns the error of each user.
+func (s *UserService) hashPasswords(ctx context.Context, users []model.User, indexes []int, requests []dto.CreateUserRequest) []error {
+	hashErrs := make([]error, len(users))
+	workers := make(chan struct{}, runtime.GOMAXPROCS(0))
+
+	var wg sync.WaitGroup
+	for j := range users {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-workers }()
+			hashErrs[j] = s.passwordService.Hash(ctx, &users[j], requests[indexes[j]].Password)
+		}()
+	}
+	wg.Wait()
+
+	return hashErrs
+}
+
+// checkBulkSize rejects bulk requests with more items than configured.
+func checkBulkSize(field string, size int) error {
+	maxSize := config.Current().Bulk.MaxSize
+	if size <= maxSize {
+		return nil
+	}
+
+	message := fmt.Sprintf("%s must have at most %d items", field, maxSize)
+	fieldError := errs.NewFieldError(field, errs.FieldTooMany, message).WithParam(strconv.Itoa(maxSize))
+	return errs.NewValidationError([]errs.FieldError{fieldError})
+}
+
+// claims holds the values earlier items of a bulk request use, which the
+// uniqueness checks against the database cannot see yet.
+type claims map[string]bool
+
+// check reports the fields whose value an earlier item claimed.
+func (c claims) check(values map[string]string) error {
+	var fieldErrors []errs.FieldError
+	for _, field := range slices.Sorted(maps.Keys(values)) {
+		if c[field+"\x00"+values[field]] {
+			fieldErrors = append(fieldErrors, errs.NewFieldError(field, errs.FieldDuplicate, field+" is used by another item of the request"))
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return errs.NewValidationError(fieldErrors)
+	}
 	return nil
 }
+
+func (c claims) claim(values map[string]string) {
+	for field, value := range values {
+		c[field+"\x00"+value] = true
+	}
+}
+
+// bulkWrite writes items, by their indexes in the result, in one call for
+// atomic results, failing the whole operation on error. Best effort results
+// are written a chunk at a time, a chunk that fails is retried item by item
+// so only the items at fault fail. written is called for each item written.
+func bulkWrite[T any](ctx context.Context, result *dto.BulkResult, indexes []int, items []T, message string, write func([]T) error, written func(i int, item T)) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	if result.Atomic() {
+		if err := write(items); err != nil {
+			return writeError(ctx, message, err)
+		}
+		for j, item := range items {
+			written(indexes[j], item)
+		}
+		return nil
+	}
+
+	chunkSize := config.Current().Bulk.ChunkSize
+	for start := 0; start < len(items); start += chunkSize {
+		end := min(start+chunkSize, len(items))
+		if write(items[start:end]) == nil {
+			for j := start; j < end; j++ {
+				written(indexes[j], items[j])
+			}
+			continue
+		}
+
+		for j := start; j < end; j++ {
+			if err := write(items[j : j+1]); err != nil {
+				result.Fail(indexes[j], writeError(ctx, message, err, "index", indexes[j]))
+				continue
+			}
+			written(indexes[j], items[j])
+		}
+	}
+
+	return nil
+}
+
+// writeError returns the error of a failed bulk write. Errors the repository
+// translated for the client, such as ErrUserReferenced or a taken email, are
+// kept, others are logged and become server errors.
+func writeError(ctx context.Context, message string, err error, keysAndValues ...any) error {
+	var appErr *errs.AppError
+	var validationErr *errs.ValidationError
+	if errors.As(err, &appErr) || errors.As(err, &validationErr) {
+		return err
+	}
+
+	logger.FromContext(ctx).Errorw(message, append(keysAndValues, "error", err)...)
+	return errs.NewAppError(500, message, err)
+}
+
+// isServerError reports whether err is an AppError the client is not at
+// fault for, which ends a bulk operation rather than failing one item.
+func isServerError(err error) bool {
+	var appErr *errs.AppError
+	return errors.As(err, &appErr) && appErr.Code >= 500
+}
@@ -0,0 +1,27 @@
+package tenant
+
+import (
+	"context"
+
+	"github.com/Alfian57/belajar-golang/internal/model"
+)
+
+// Context keys set by the tenant middleware. They are plain strings so they
+// can be read back through gin.Context, which is passed down as context.Context.
+const (
+	OrganizationKey = "organization"
+	RoleKey         = "organization_role"
+)
+
+// FromContext returns the organization resolved for the current request.
+func FromContext(ctx context.Context) (model.Organization, bool) {
+	organization, ok := ctx.Value(OrganizationKey).(model.Organization)
+	return organization, ok
+}
+
+// RoleFromContext returns the current user's role in the resolved organization.
+// It is empty when the user is not a member, for example a global admin.
+func RoleFromContext(ctx context.Context) string {
+	role, _ := ctx.Value(RoleKey).(string)
+	return role
+}
@@ -0,0 +1,54 @@
+package tracing
+
+import (
+	"net/http"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Transport wraps an http.RoundTripper so outgoing requests get a client
+// span and carry the traceparent header to the next service.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// NewTransport wraps base, or http.DefaultTransport when base is nil.
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Base: base}
+}
+
+func (t *Transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	ctx, span := Tracer().Start(request.Context(), "HTTP "+request.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(request.Method),
+			semconv.ServerAddress(request.URL.Hostname()),
+		),
+	)
+	defer span.End()
+
+	// RoundTrip must not modify the caller's request
+	request = request.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(request.Header))
+
+	response, err := t.Base.RoundTrip(request)
+	if err != nil {
+		RecordError(span, err)
+		return nil, err
+	}
+
+	span.SetAttributes(semconv.HTTPResponseStatusCode(response.StatusCode))
+	if response.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, strconv.Itoa(response.StatusCode))
+	}
+
+	return response, nil
+}
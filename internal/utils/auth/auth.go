@@ -5,6 +5,8 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// GetCurrentUser returns the effective user of the request. During
+// impersonation this is the impersonated user, not the admin.
 func GetCurrentUser(ctx *gin.Context) (model.User, bool) {
 	u, exists := ctx.Get("user")
 	if !exists {
@@ -13,3 +15,25 @@ func GetCurrentUser(ctx *gin.Context) (model.User, bool) {
 	user, ok := u.(model.User)
 	return user, ok
 }
+
+// GetCurrentActor returns the user who is really making the request.
+// Outside impersonation it is the same as GetCurrentUser.
+func GetCurrentActor(ctx *gin.Context) (model.User, bool) {
+	a, exists := ctx.Get("actor")
+	if !exists {
+		return GetCurrentUser(ctx)
+	}
+	actor, ok := a.(model.User)
+	return actor, ok
+}
+
+// IsImpersonating reports whether the request uses an impersonation token.
+func IsImpersonating(ctx *gin.Context) bool {
+	_, exists := ctx.Get("impersonation_session_id")
+	return exists
+}
+
+// GetImpersonationSessionID returns the session ID of an impersonation token.
+func GetImpersonationSessionID(ctx *gin.Context) (string, bool) {
+	return ctx.GetString("impersonation_session_id"), IsImpersonating(ctx)
+}
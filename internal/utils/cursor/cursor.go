@@ -0,0 +1,71 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/Alfian57/belajar-golang/internal/config"
+)
+
+// ErrInvalid is returned for cursors that are malformed or were not signed
+// by this server.
+var ErrInvalid = errors.New("cursor: invalid")
+
+// Cursor positions a keyset query next to a row, identified by its values
+// of the sorted fields followed by its ID as a tie-breaker.
+type Cursor struct {
+	// Sort is the sort the cursor was created with, e.g. -created_at.
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+	// Backward pages towards the start of the order, for previous pages.
+	Backward bool `json:"b,omitempty"`
+}
+
+// Encode returns the cursor as an opaque, signed, URL-safe string.
+func Encode(c Cursor) (string, error) {
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(sign(payload)), nil
+}
+
+// Decode verifies and parses a cursor returned by Encode.
+func Decode(encoded string) (Cursor, error) {
+	rawPayload, rawSignature, ok := strings.Cut(encoded, ".")
+	if !ok {
+		return Cursor{}, ErrInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(rawPayload)
+	if err != nil {
+		return Cursor{}, ErrInvalid
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(rawSignature)
+	if err != nil || !hmac.Equal(signature, sign(payload)) {
+		return Cursor{}, ErrInvalid
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return Cursor{}, ErrInvalid
+	}
+
+	return c, nil
+}
+
+// sign uses a key derived from the access token secret, so cursors are
+// invalidated with it and cannot be used as tokens.
+func sign(payload []byte) []byte {
+	key := hmac.New(sha256.New, []byte(config.Current().JWT.AccessTokenSecret))
+	key.Write([]byte("pagination cursor"))
+
+	mac := hmac.New(sha256.New, key.Sum(nil))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/Alfian57/belajar-golang/internal/model"
 	"github.com/golang-jwt/jwt/v5"
 	golangJwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 func CreateAccessToken(user model.User) (string, error) {
@@ -18,8 +19,7 @@ func CreateAccessToken(user model.User) (string, error) {
 		"exp":      time.Now().Add(time.Minute * 15).Unix(),
 	})
 
-	secret := config.GetEnv("ACCESS_TOKEN_SECRET", "secret")
-	secretByte := []byte(secret)
+	secretByte := []byte(config.Current().JWT.AccessTokenSecret)
 
 	tokenString, err := token.SignedString(secretByte)
 	return tokenString, err
@@ -32,38 +32,94 @@ func CreateRefreshToken(user model.User) (string, error) {
 		"exp": time.Now().Add(time.Hour * 24 * 7).Unix(),
 	})
 
-	secret := config.GetEnv("REFRESH_TOKEN_SECRET", "secret")
-	secretByte := []byte(secret)
+	secretByte := []byte(config.Current().JWT.RefreshTokenSecret)
 
 	tokenString, err := token.SignedString(secretByte)
 	return tokenString, err
 }
 
-func ValidateAccessToken(tokenString string) (string, error) {
+// CreateImpersonationToken creates a short-lived access token for the target user.
+// The real actor is recorded in the act claim and the session ID in the sid claim.
+func CreateImpersonationToken(target model.User, actor model.User, sessionID uuid.UUID, expiresAt time.Time) (string, error) {
+
+	token := golangJwt.NewWithClaims(golangJwt.SigningMethodHS256, golangJwt.MapClaims{
+		"id":       target.ID,
+		"username": target.Username,
+		"exp":      expiresAt.Unix(),
+		"sid":      sessionID,
+		"act": map[string]any{
+			"id":       actor.ID,
+			"username": actor.Username,
+		},
+	})
+
+	secretByte := []byte(config.Current().JWT.AccessTokenSecret)
+
+	tokenString, err := token.SignedString(secretByte)
+	return tokenString, err
+}
+
+// AccessClaims holds the identities carried by an access token.
+// ActorID and SessionID are only set for impersonation tokens.
+type AccessClaims struct {
+	UserID    string
+	ActorID   string
+	SessionID string
+}
+
+func (c AccessClaims) IsImpersonation() bool {
+	return c.ActorID != ""
+}
+
+func ParseAccessToken(tokenString string) (AccessClaims, error) {
 	token, err := jwt.Parse(tokenString, func(token *golangJwt.Token) (any, error) {
-		secret := config.GetEnv("ACCESS_TOKEN_SECRET", "secret")
-		secretByte := []byte(secret)
+		secretByte := []byte(config.Current().JWT.AccessTokenSecret)
 		return secretByte, nil
 	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 
 	if err != nil {
-		return "", err
+		return AccessClaims{}, err
 	}
 
-	if claims, ok := token.Claims.(golangJwt.MapClaims); ok {
-		if id, ok := claims["id"].(string); ok {
-			return id, nil
+	claims, ok := token.Claims.(golangJwt.MapClaims)
+	if !ok {
+		return AccessClaims{}, errs.ErrInvalidTokenClaims
+	}
+
+	id, ok := claims["id"].(string)
+	if !ok {
+		return AccessClaims{}, errs.ErrInvalidTokenClaims
+	}
+	accessClaims := AccessClaims{UserID: id}
+
+	if act, ok := claims["act"].(map[string]any); ok {
+		actorID, ok := act["id"].(string)
+		if !ok {
+			return AccessClaims{}, errs.ErrInvalidTokenClaims
 		}
-		return "", errs.ErrInvalidTokenClaims
+		sessionID, ok := claims["sid"].(string)
+		if !ok {
+			return AccessClaims{}, errs.ErrInvalidTokenClaims
+		}
+		accessClaims.ActorID = actorID
+		accessClaims.SessionID = sessionID
 	}
 
-	return "", err
+	return accessClaims, nil
+}
+
+func ValidateAccessToken(tokenString string) (string, error) {
+	claims, err := ParseAccessToken(tokenString)
+	if err != nil {
+		return "", err
+	}
+
+	return claims.UserID, nil
 }
 
 func GetUserID(tokenString string) (string, error) {
 	token, err := jwt.Parse(tokenString, func(token *golangJwt.Token) (any, error) {
-		secret := config.GetEnv("ACCESS_TOKEN_SECRET", "secret")
-		secretByte := []byte(secret)
+		secretByte := []byte(config.Current().JWT.AccessTokenSecret)
 		return secretByte, nil
 	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 
@@ -0,0 +1,97 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"strings"
+)
+
+// prefixLength is the number of hex characters of the SHA-1 hash used as the
+// bucket key, the same split used by k-anonymity range APIs.
+const prefixLength = 5
+
+// Blocklist is an offline breached-password list indexed by SHA-1 hash prefix.
+type Blocklist struct {
+	buckets map[string]map[string]struct{}
+}
+
+// LoadBlocklist reads a file of SHA-1 password hashes, one per line.
+// Lines may carry a trailing ":count" as in published breach corpora;
+// empty lines and lines starting with '#' are ignored.
+func LoadBlocklist(path string) (*Blocklist, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	blocklist := &Blocklist{buckets: make(map[string]map[string]struct{})}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(line, ":")
+		if len(hash) != sha1.Size*2 {
+			continue
+		}
+		blocklist.add(strings.ToUpper(hash))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return blocklist, nil
+}
+
+// Contains reports whether the password appears in the blocklist.
+func (b *Blocklist) Contains(password string) bool {
+	if b == nil {
+		return false
+	}
+
+	prefix, suffix := splitHash(password)
+	bucket, ok := b.buckets[prefix]
+	if !ok {
+		return false
+	}
+
+	_, found := bucket[suffix]
+	return found
+}
+
+// Len returns the number of hashes in the blocklist.
+func (b *Blocklist) Len() int {
+	if b == nil {
+		return 0
+	}
+
+	total := 0
+	for _, bucket := range b.buckets {
+		total += len(bucket)
+	}
+	return total
+}
+
+func (b *Blocklist) add(hash string) {
+	prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+
+	bucket, ok := b.buckets[prefix]
+	if !ok {
+		bucket = make(map[string]struct{})
+		b.buckets[prefix] = bucket
+	}
+	bucket[suffix] = struct{}{}
+}
+
+func splitHash(password string) (string, string) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	return hash[:prefixLength], hash[prefixLength:]
+}
@@ -0,0 +1,174 @@
+package password
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Alfian57/belajar-golang/internal/config"
+	"github.com/Alfian57/belajar-golang/internal/constants"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+)
+
+const field = "password"
+
+// minIdentityLength is the shortest username or email local part that is
+// checked as a password substring; shorter values would reject too much.
+const minIdentityLength = 3
+
+type Policy struct {
+	MinLength        int
+	RequireUpper     bool
+	RequireLower     bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	DisallowIdentity bool
+	HistorySize      int
+	Blocklist        *Blocklist
+}
+
+// Current is the policy applied by the services. It falls back to the
+// minimum length rule until Init is called.
+var Current = &Policy{
+	MinLength:        constants.MinPasswordLength,
+	DisallowIdentity: true,
+}
+
+func Init(cfg config.PasswordConfig) error {
+	policy := &Policy{
+		MinLength:        cfg.MinLength,
+		RequireUpper:     cfg.RequireUpper,
+		RequireLower:     cfg.RequireLower,
+		RequireDigit:     cfg.RequireDigit,
+		RequireSymbol:    cfg.RequireSymbol,
+		DisallowIdentity: cfg.DisallowIdentity,
+		HistorySize:      cfg.HistorySize,
+	}
+	if policy.MinLength < constants.MinPasswordLength {
+		policy.MinLength = constants.MinPasswordLength
+	}
+
+	if cfg.BreachedListFile != "" {
+		blocklist, err := LoadBlocklist(cfg.BreachedListFile)
+		if err != nil {
+			return fmt.Errorf("failed to load breached password list: %w", err)
+		}
+		policy.Blocklist = blocklist
+	}
+
+	Current = policy
+	return nil
+}
+
+// Validate checks the password against the policy and returns one field error
+// per violated rule. The username and email are used for the identity rule.
+func (p *Policy) Validate(password, username, email string) []errs.FieldError {
+	var fieldErrors []errs.FieldError
+
+	if len([]rune(password)) < p.MinLength {
+		fieldErrors = append(fieldErrors, errs.NewFieldError(field, errs.FieldTooShort, fmt.Sprintf("password must be at least %d characters", p.MinLength)).WithParam(strconv.Itoa(p.MinLength)))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	if p.RequireUpper && !hasUpper {
+		fieldErrors = append(fieldErrors, errs.NewFieldError(field, errs.FieldMissingUppercase, "password must contain an uppercase letter"))
+	}
+	if p.RequireLower && !hasLower {
+		fieldErrors = append(fieldErrors, errs.NewFieldError(field, errs.FieldMissingLowercase, "password must contain a lowercase letter"))
+	}
+	if p.RequireDigit && !hasDigit {
+		fieldErrors = append(fieldErrors, errs.NewFieldError(field, errs.FieldMissingDigit, "password must contain a digit"))
+	}
+	if p.RequireSymbol && !hasSymbol {
+		fieldErrors = append(fieldErrors, errs.NewFieldError(field, errs.FieldMissingSymbol, "password must contain a symbol"))
+	}
+
+	if p.DisallowIdentity && containsIdentity(password, username, email) {
+		fieldErrors = append(fieldErrors, errs.NewFieldError(field, errs.FieldContainsIdentity, "password must not contain the username or email"))
+	}
+
+	if p.Blocklist.Contains(password) {
+		fieldErrors = append(fieldErrors, errs.NewFieldError(field, errs.FieldBreached, "password has appeared in a data breach, choose a different one"))
+	}
+
+	return fieldErrors
+}
+
+// generatedLength is the shortest generated password, longer than the
+// policy requires when its minimum is low.
+const generatedLength = 20
+
+// Character classes of generated passwords, without look-alikes such as 0,
+// O, 1 and l.
+const (
+	upperChars  = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	lowerChars  = "abcdefghijkmnopqrstuvwxyz"
+	digitChars  = "23456789"
+	symbolChars = "!#$%&*+-=?@^_"
+)
+
+// Generate returns a random password that has a character of every class,
+// so it passes the composition rules whatever the policy requires.
+func (p *Policy) Generate() (string, error) {
+	classes := []string{upperChars, lowerChars, digitChars, symbolChars}
+	all := strings.Join(classes, "")
+
+	chars := make([]byte, max(p.MinLength, generatedLength))
+	for i := range chars {
+		set := all
+		if i < len(classes) {
+			set = classes[i]
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(set))))
+		if err != nil {
+			return "", err
+		}
+		chars[i] = set[n.Int64()]
+	}
+
+	// Move the character of each class to a random position
+	for i := len(chars) - 1; i > 0; i-- {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		j := n.Int64()
+		chars[i], chars[j] = chars[j], chars[i]
+	}
+
+	return string(chars), nil
+}
+
+func containsIdentity(password, username, email string) bool {
+	lowered := strings.ToLower(password)
+
+	candidates := []string{username}
+	if local, _, ok := strings.Cut(email, "@"); ok {
+		candidates = append(candidates, local)
+	}
+
+	for _, candidate := range candidates {
+		candidate = strings.ToLower(strings.TrimSpace(candidate))
+		if len(candidate) >= minIdentityLength && strings.Contains(lowered, candidate) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,140 @@
+// Package search turns user input into Postgres full-text queries and finds
+// the fragments of a value a search matched, for highlighting.
+package search
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// MaxTerms limits the words of a search.
+const MaxTerms = 10
+
+// Fragment is a matched part of a value, in character offsets with End
+// excluded.
+type Fragment struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Terms splits input into distinct lower case words of letters and digits,
+// which is all a search query is made of.
+func Terms(input string) []string {
+	words := strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var terms []string
+	for _, word := range words {
+		if !slices.Contains(terms, word) && len(terms) < MaxTerms {
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// PrefixQuery returns a to_tsquery expression matching documents with a
+// word starting with each term, e.g. john:* & acme:*. Terms only hold
+// letters and digits, so the expression is always valid.
+func PrefixQuery(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = term + ":*"
+	}
+	return strings.Join(parts, " & ")
+}
+
+// Highlight returns the fragments of value matching terms, in order. Terms
+// match case-insensitively anywhere in value. With fuzzy, a term that does
+// not occur matches the word of value most similar to it instead.
+func Highlight(value string, terms []string, fuzzy bool) []Fragment {
+	text := []rune(value)
+	for i, r := range text {
+		text[i] = unicode.ToLower(r)
+	}
+
+	var fragments []Fragment
+	for _, term := range terms {
+		found := occurrences(text, []rune(term))
+		if len(found) == 0 && fuzzy {
+			if word, ok := closestWord(text, term); ok {
+				found = append(found, word)
+			}
+		}
+		fragments = append(fragments, found...)
+	}
+
+	return merge(fragments)
+}
+
+func occurrences(text, term []rune) []Fragment {
+	var found []Fragment
+	for start := 0; len(term) > 0 && start+len(term) <= len(text); start++ {
+		if slices.Equal(text[start:start+len(term)], term) {
+			found = append(found, Fragment{Start: start, End: start + len(term)})
+			start += len(term) - 1
+		}
+	}
+	return found
+}
+
+// closestWord finds the word of text with the highest trigram similarity to
+// term, the measure pg_trgm matched it with.
+func closestWord(text []rune, term string) (Fragment, bool) {
+	best, bestScore := Fragment{}, 0.0
+	start := -1
+	for i := 0; i <= len(text); i++ {
+		inWord := i < len(text) && (unicode.IsLetter(text[i]) || unicode.IsDigit(text[i]))
+		if inWord && start < 0 {
+			start = i
+		}
+		if !inWord && start >= 0 {
+			if score := similarity(string(text[start:i]), term); score > bestScore {
+				best, bestScore = Fragment{Start: start, End: i}, score
+			}
+			start = -1
+		}
+	}
+	return best, bestScore > 0
+}
+
+// similarity is the share of trigrams two words have in common, with words
+// padded like pg_trgm does.
+func similarity(a, b string) float64 {
+	left, right := trigrams(a), trigrams(b)
+
+	shared := 0
+	for trigram := range left {
+		if right[trigram] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(left)+len(right)-shared)
+}
+
+func trigrams(word string) map[string]bool {
+	padded := []rune("  " + word + " ")
+	set := make(map[string]bool, len(padded))
+	for i := 0; i+3 <= len(padded); i++ {
+		set[string(padded[i:i+3])] = true
+	}
+	return set
+}
+
+// merge sorts fragments and joins overlapping ones.
+func merge(fragments []Fragment) []Fragment {
+	slices.SortFunc(fragments, func(a, b Fragment) int {
+		return a.Start - b.Start
+	})
+
+	var merged []Fragment
+	for _, fragment := range fragments {
+		if last := len(merged) - 1; last >= 0 && fragment.Start <= merged[last].End {
+			merged[last].End = max(merged[last].End, fragment.End)
+			continue
+		}
+		merged = append(merged, fragment)
+	}
+	return merged
+}
@@ -0,0 +1,219 @@
+// Package spreadsheet reads and writes the CSV and XLSX files of imports and
+// exports as rows of text cells.
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Formats.
+const (
+	CSV  = "csv"
+	XLSX = "xlsx"
+)
+
+// ErrTooManyRows is returned by Read for files with more rows than allowed.
+var ErrTooManyRows = errors.New("spreadsheet: too many rows")
+
+// sheet is the worksheet XLSX files are written to.
+const sheet = "Sheet1"
+
+// unzipSizeLimit bounds the uncompressed size of XLSX files, which are zip
+// archives that may expand far beyond their upload size.
+const unzipSizeLimit = 256 << 20
+
+// ContentType returns the media type of a format.
+func ContentType(format string) string {
+	if format == XLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// FormatOf returns the format of a file by its extension, or "" when it is
+// neither CSV nor XLSX.
+func FormatOf(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return CSV
+	case ".xlsx":
+		return XLSX
+	default:
+		return ""
+	}
+}
+
+// Writer writes rows one at a time.
+type Writer interface {
+	Write(cells []string) error
+	// Close finishes the file. It does not close the underlying writer.
+	Close() error
+	// Discard releases a file that will not be finished instead.
+	Discard()
+}
+
+// NewWriter returns a writer of format to w. CSV rows are written through
+// as they come, XLSX rows are buffered in a temporary file past a few
+// megabytes and the workbook is written on Close.
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	if format != XLSX {
+		return &csvWriter{csv: csv.NewWriter(w)}, nil
+	}
+
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &xlsxWriter{file: file, stream: stream, out: w}, nil
+}
+
+type csvWriter struct {
+	csv *csv.Writer
+}
+
+func (w *csvWriter) Write(cells []string) error {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = escapeFormula(cell)
+	}
+	return w.csv.Write(escaped)
+}
+
+func (w *csvWriter) Close() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+func (w *csvWriter) Discard() {}
+
+// escapeFormula keeps spreadsheet applications from evaluating cells that
+// start like a formula, e.g. =HYPERLINK(...) in a username.
+func escapeFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+type xlsxWriter struct {
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	out    io.Writer
+	rows   int
+}
+
+// Write stores cells as strings, which spreadsheet applications never
+// evaluate.
+func (w *xlsxWriter) Write(cells []string) error {
+	w.rows++
+	start, err := excelize.CoordinatesToCellName(1, w.rows)
+	if err != nil {
+		return err
+	}
+
+	values := make([]any, len(cells))
+	for i, cell := range cells {
+		values[i] = cell
+	}
+	return w.stream.SetRow(start, values)
+}
+
+func (w *xlsxWriter) Close() error {
+	defer w.file.Close()
+
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+	_, err := w.file.WriteTo(w.out)
+	return err
+}
+
+func (w *xlsxWriter) Discard() {
+	w.file.Close()
+}
+
+// Row is a row of a file. Number counts from 1 in the file, like
+// spreadsheet applications do.
+type Row struct {
+	Number int
+	Cells  []string
+}
+
+// Read returns the rows of a file in format, without the rows that have no
+// value. It fails with ErrTooManyRows past maxRows rows.
+func Read(r io.Reader, format string, maxRows int) ([]Row, error) {
+	var rows []Row
+	add := func(number int, cells []string) error {
+		if isBlank(cells) {
+			return nil
+		}
+		if len(rows) == maxRows {
+			return ErrTooManyRows
+		}
+		rows = append(rows, Row{Number: number, Cells: cells})
+		return nil
+	}
+
+	if format != XLSX {
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		for {
+			cells, err := reader.Read()
+			if err == io.EOF {
+				return rows, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			// Excel starts UTF-8 files with a byte order mark
+			if len(rows) == 0 && len(cells) > 0 {
+				cells[0] = strings.TrimPrefix(cells[0], "\ufeff")
+			}
+			line, _ := reader.FieldPos(0)
+			if err := add(line, cells); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	file, err := excelize.OpenReader(r, excelize.Options{UnzipSizeLimit: unzipSizeLimit})
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	iterator, err := file.Rows(file.GetSheetName(0))
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+
+	for number := 1; iterator.Next(); number++ {
+		cells, err := iterator.Columns()
+		if err != nil {
+			return nil, err
+		}
+		if err := add(number, cells); err != nil {
+			return nil, err
+		}
+	}
+	return rows, iterator.Error()
+}
+
+func isBlank(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/Alfian57/belajar-golang/internal/utils/password"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// UsernamePattern is the charset of usernames, checked by the username tag.
+const UsernamePattern = `^[a-zA-Z0-9._-]+$`
+
+var usernamePattern = regexp.MustCompile(UsernamePattern)
+
+// Rule is a custom tag. Func checks values without I/O, Check runs with the
+// request context and may query the database, see Struct.
+type Rule struct {
+	Tag   string
+	Func  validator.Func
+	Check func(ctx context.Context, fl validator.FieldLevel) (bool, error)
+	// Code is the field error code of failures, the tag by default.
+	Code string
+	// Explain optionally replaces the field error of a failure with more
+	// specific ones, e.g. one per violated password rule.
+	Explain    func(fe validator.FieldError) []errs.FieldError
+	CallIfNull bool
+}
+
+// StructRule checks rules across the fields of Types, reporting failures
+// with StructLevel.ReportError.
+type StructRule struct {
+	Types []any
+	Func  validator.StructLevelFuncCtx
+}
+
+// Rules returns the custom tags. The unique_* tags take the name of the
+// field holding the ID of the record being updated, which may hold its own
+// value, e.g. unique_email=ID.
+func Rules() []Rule {
+	users := repository.NewUserRepository()
+
+	return []Rule{
+		{Tag: "username", Func: isUsername},
+		{Tag: "password_strength", Func: isStrongPassword, Explain: explainPassword},
+		{Tag: "uuid_param", Func: isUUID},
+		{Tag: "unique_email", Check: unique(users.GetByEmail), Code: errs.FieldTaken},
+		{Tag: "unique_username", Check: unique(users.GetByUsername), Code: errs.FieldTaken},
+	}
+}
+
+// StructRules returns the cross-field rules.
+func StructRules() []StructRule {
+	return []StructRule{
+		{Types: []any{dto.ChangePasswordRequest{}}, Func: passwordChanged},
+	}
+}
+
+// registered holds the rules installed by Init by tag.
+var registered = map[string]Rule{}
+
+// Code returns the field error code of a failed tag.
+func Code(fe validator.FieldError) string {
+	if rule, ok := registered[fe.Tag()]; ok && rule.Code != "" {
+		return rule.Code
+	}
+	return fe.Tag()
+}
+
+// Explain returns the specific field errors of a failed tag, or nil when
+// the tag has none.
+func Explain(fe validator.FieldError) []errs.FieldError {
+	if rule, ok := registered[fe.Tag()]; ok && rule.Explain != nil {
+		return rule.Explain(fe)
+	}
+	return nil
+}
+
+// validate adapts the rule to the validator. Checks pass outside of Struct
+// and record their errors in its state.
+func (r Rule) validate() validator.FuncCtx {
+	if r.Check == nil {
+		return func(_ context.Context, fl validator.FieldLevel) bool {
+			return r.Func(fl)
+		}
+	}
+
+	return func(ctx context.Context, fl validator.FieldLevel) bool {
+		s, ok := ctx.Value(stateKey{}).(*state)
+		if !ok {
+			return true
+		}
+
+		valid, err := r.Check(ctx, fl)
+		if err != nil {
+			if s.err == nil {
+				s.err = err
+			}
+			return true
+		}
+		return valid
+	}
+}
+
+func isUsername(fl validator.FieldLevel) bool {
+	return usernamePattern.MatchString(fl.Field().String())
+}
+
+// isStrongPassword checks the password policy except the identity rule,
+// which needs the user and is checked by PasswordService.Validate.
+func isStrongPassword(fl validator.FieldLevel) bool {
+	return len(password.Current.Validate(fl.Field().String(), "", "")) == 0
+}
+
+func explainPassword(fe validator.FieldError) []errs.FieldError {
+	value, _ := fe.Value().(string)
+
+	fieldErrors := password.Current.Validate(value, "", "")
+	for i := range fieldErrors {
+		fieldErrors[i].Field = fe.Field()
+	}
+	return fieldErrors
+}
+
+func isUUID(fl validator.FieldLevel) bool {
+	_, err := uuid.Parse(fl.Field().String())
+	return err == nil
+}
+
+// unique passes when no user has the value, or only the one whose ID is in
+// the field named by the tag parameter.
+func unique(lookup func(ctx context.Context, value string) (model.User, error)) func(context.Context, validator.FieldLevel) (bool, error) {
+	return func(ctx context.Context, fl validator.FieldLevel) (bool, error) {
+		user, err := lookup(ctx, fl.Field().String())
+		if errors.Is(err, errs.ErrUserNotFound) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if fl.Param() == "" {
+			return false, nil
+		}
+		field := reflect.Indirect(fl.Parent()).FieldByName(fl.Param())
+		if !field.IsValid() {
+			return false, nil
+		}
+		ownID, ok := field.Interface().(uuid.UUID)
+		return ok && ownID != uuid.Nil && user.ID == ownID, nil
+	}
+}
+
+// passwordChanged rejects a new password equal to the current one.
+func passwordChanged(_ context.Context, sl validator.StructLevel) {
+	request := sl.Current().Interface().(dto.ChangePasswordRequest)
+	if request.Password != "" && request.Password == request.CurrentPassword {
+		sl.ReportError(request.Password, "password", "Password", errs.FieldUnchanged, "")
+	}
+}
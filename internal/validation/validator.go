@@ -1,9 +1,130 @@
 package validation
 
-import "github.com/go-playground/validator/v10"
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
 
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Validator is the engine behind binding:"..." tags. Init installs it as the
+// binding validator of gin, so handlers and services share its rules.
 var Validator *validator.Validate
 
-func Init() {
-	Validator = validator.New(validator.WithRequiredStructEnabled())
+// Init builds the validator with the custom tags of Rules and the struct
+// rules of StructRules, and installs it in gin.
+func Init() error {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.SetTagName("binding")
+	v.RegisterTagNameFunc(jsonName)
+
+	rules := make(map[string]Rule)
+	for _, rule := range Rules() {
+		if err := v.RegisterValidationCtx(rule.Tag, rule.validate(), rule.CallIfNull); err != nil {
+			return err
+		}
+		rules[rule.Tag] = rule
+	}
+	for _, rule := range StructRules() {
+		v.RegisterStructValidationCtx(rule.Func, rule.Types...)
+	}
+
+	Validator = v
+	registered = rules
+	binding.Validator = &ginValidator{validate: v}
+	return nil
+}
+
+// jsonName names fields after their json tag in errors, so field errors
+// match the request body.
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" || name == "" {
+		return field.Name
+	}
+	return name
+}
+
+type stateKey struct{}
+
+// state collects the first error of rules that query the database, which
+// cannot be reported through the boolean result of a validator.
+type state struct {
+	err error
+}
+
+// Struct validates obj with the context of a request and runs the rules
+// that need one, such as unique_email. Binding has no request context and
+// skips those rules, so services call Struct once the request is complete.
+func Struct(ctx context.Context, obj any) error {
+	s := &state{}
+	err := Validator.StructCtx(context.WithValue(ctx, stateKey{}, s), obj)
+	if s.err != nil {
+		logger.FromContext(ctx).Errorw("failed to validate request", "error", s.err)
+		return errs.NewAppError(500, "failed to validate request", s.err)
+	}
+
+	return err
+}
+
+// Value validates a single value, such as a path parameter, against tag and
+// reports failures under field.
+func Value(field string, value any, tag string) error {
+	err := Validator.Var(value, tag)
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	fieldErrors := make([]errs.FieldError, len(validationErrs))
+	for i, fe := range validationErrs {
+		fieldErrors[i] = errs.FieldError{Field: field, Code: Code(fe), Param: fe.Param()}
+	}
+	return errs.NewValidationError(fieldErrors)
+}
+
+// ginValidator validates bound structs, and slices of them, like the default
+// validator of gin does.
+type ginValidator struct {
+	validate *validator.Validate
+}
+
+func (g *ginValidator) ValidateStruct(obj any) error {
+	if obj == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(obj)
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return nil
+		}
+		return g.ValidateStruct(value.Elem().Interface())
+	case reflect.Struct:
+		return g.validate.Struct(obj)
+	case reflect.Slice, reflect.Array:
+		var sliceErr binding.SliceValidationError
+		for i := 0; i < value.Len(); i++ {
+			if err := g.ValidateStruct(value.Index(i).Interface()); err != nil {
+				sliceErr = append(sliceErr, err)
+			}
+		}
+		if len(sliceErr) == 0 {
+			return nil
+		}
+		return sliceErr
+	default:
+		return nil
+	}
+}
+
+func (g *ginValidator) Engine() any {
+	return g.validate
 }
@@ -0,0 +1,14 @@
+CREATE TABLE "password_histories" (
+    "id" UUID NOT NULL,
+    "user_id" UUID NOT NULL,
+    "password_hash" VARCHAR(255) NOT NULL,
+    "created_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE
+    "password_histories" ADD PRIMARY KEY("id");
+
+ALTER TABLE
+    "password_histories" ADD CONSTRAINT "password_histories_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+
+CREATE INDEX "password_histories_user_id_created_at_index" ON "password_histories"("user_id", "created_at");
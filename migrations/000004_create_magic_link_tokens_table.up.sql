@@ -0,0 +1,21 @@
+CREATE TABLE "magic_link_tokens" (
+    "id" UUID NOT NULL,
+    "user_id" UUID NOT NULL,
+    "email" VARCHAR(100) NOT NULL,
+    "token_hash" VARCHAR(255) NOT NULL,
+    "fingerprint" VARCHAR(255) NOT NULL DEFAULT '',
+    "created_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    "expires_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL,
+    "used_at" TIMESTAMP(0) WITHOUT TIME ZONE NULL
+);
+
+ALTER TABLE
+    "magic_link_tokens" ADD PRIMARY KEY("id");
+
+ALTER TABLE
+    "magic_link_tokens" ADD CONSTRAINT "magic_link_tokens_token_hash_unique" UNIQUE("token_hash");
+
+ALTER TABLE
+    "magic_link_tokens" ADD CONSTRAINT "magic_link_tokens_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+
+CREATE INDEX "magic_link_tokens_email_created_at_index" ON "magic_link_tokens"("email", "created_at");
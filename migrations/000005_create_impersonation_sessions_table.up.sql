@@ -0,0 +1,20 @@
+CREATE TABLE "impersonation_sessions" (
+    "id" UUID NOT NULL,
+    "actor_id" UUID NOT NULL,
+    "target_id" UUID NOT NULL,
+    "reason" VARCHAR(255) NOT NULL,
+    "ip_address" VARCHAR(45) NOT NULL DEFAULT '',
+    "user_agent" VARCHAR(255) NOT NULL DEFAULT '',
+    "created_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    "expires_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL,
+    "ended_at" TIMESTAMP(0) WITHOUT TIME ZONE NULL
+);
+
+ALTER TABLE
+    "impersonation_sessions" ADD PRIMARY KEY("id");
+
+ALTER TABLE
+    "impersonation_sessions" ADD CONSTRAINT "impersonation_sessions_actor_id_foreign" FOREIGN KEY("actor_id") REFERENCES "users"("id") ON DELETE RESTRICT;
+
+ALTER TABLE
+    "impersonation_sessions" ADD CONSTRAINT "impersonation_sessions_target_id_foreign" FOREIGN KEY("target_id") REFERENCES "users"("id") ON DELETE RESTRICT;
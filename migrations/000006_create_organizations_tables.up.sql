@@ -0,0 +1,62 @@
+CREATE TABLE "organizations" (
+    "id" UUID NOT NULL,
+    "name" VARCHAR(100) NOT NULL,
+    "slug" VARCHAR(63) NOT NULL,
+    "created_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    "updated_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE
+    "organizations" ADD PRIMARY KEY("id");
+
+ALTER TABLE
+    "organizations" ADD CONSTRAINT "organizations_slug_unique" UNIQUE("slug");
+
+CREATE TABLE "organization_members" (
+    "id" UUID NOT NULL,
+    "organization_id" UUID NOT NULL,
+    "user_id" UUID NOT NULL,
+    "role" VARCHAR(255) CHECK ("role" IN('owner', 'admin', 'member')) NOT NULL DEFAULT 'member',
+    "created_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE
+    "organization_members" ADD PRIMARY KEY("id");
+
+ALTER TABLE
+    "organization_members" ADD CONSTRAINT "organization_members_organization_id_user_id_unique" UNIQUE("organization_id", "user_id");
+
+ALTER TABLE
+    "organization_members" ADD CONSTRAINT "organization_members_organization_id_foreign" FOREIGN KEY("organization_id") REFERENCES "organizations"("id") ON DELETE CASCADE;
+
+ALTER TABLE
+    "organization_members" ADD CONSTRAINT "organization_members_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+
+CREATE INDEX "organization_members_user_id_index" ON "organization_members"("user_id");
+
+CREATE TABLE "organization_invitations" (
+    "id" UUID NOT NULL,
+    "organization_id" UUID NOT NULL,
+    "email" VARCHAR(100) NOT NULL,
+    "role" VARCHAR(255) CHECK ("role" IN('admin', 'member')) NOT NULL DEFAULT 'member',
+    "token_hash" VARCHAR(255) NOT NULL,
+    "status" VARCHAR(255) CHECK ("status" IN('pending', 'accepted', 'declined', 'revoked')) NOT NULL DEFAULT 'pending',
+    "invited_by" UUID NULL,
+    "created_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    "expires_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL,
+    "responded_at" TIMESTAMP(0) WITHOUT TIME ZONE NULL
+);
+
+ALTER TABLE
+    "organization_invitations" ADD PRIMARY KEY("id");
+
+ALTER TABLE
+    "organization_invitations" ADD CONSTRAINT "organization_invitations_token_hash_unique" UNIQUE("token_hash");
+
+ALTER TABLE
+    "organization_invitations" ADD CONSTRAINT "organization_invitations_organization_id_foreign" FOREIGN KEY("organization_id") REFERENCES "organizations"("id") ON DELETE CASCADE;
+
+ALTER TABLE
+    "organization_invitations" ADD CONSTRAINT "organization_invitations_invited_by_foreign" FOREIGN KEY("invited_by") REFERENCES "users"("id") ON DELETE SET NULL;
+
+CREATE INDEX "organization_invitations_organization_id_email_index" ON "organization_invitations"("organization_id", "email");
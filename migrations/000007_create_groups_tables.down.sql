@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS user_permissions;
+DROP TABLE IF EXISTS group_permissions;
+DROP TABLE IF EXISTS group_members;
+DROP TABLE IF EXISTS groups;
\ No newline at end of file
@@ -0,0 +1,61 @@
+CREATE TABLE "groups" (
+    "id" UUID NOT NULL,
+    "organization_id" UUID NULL,
+    "parent_id" UUID NULL,
+    "name" VARCHAR(100) NOT NULL,
+    "role" VARCHAR(255) CHECK ("role" IN('member', 'admin')) NULL,
+    "created_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    "updated_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE
+    "groups" ADD PRIMARY KEY("id");
+
+ALTER TABLE
+    "groups" ADD CONSTRAINT "groups_organization_id_foreign" FOREIGN KEY("organization_id") REFERENCES "organizations"("id") ON DELETE CASCADE;
+
+ALTER TABLE
+    "groups" ADD CONSTRAINT "groups_parent_id_foreign" FOREIGN KEY("parent_id") REFERENCES "groups"("id") ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX "groups_organization_id_name_unique" ON "groups"(COALESCE("organization_id", '00000000-0000-0000-0000-000000000000'), "name");
+
+CREATE INDEX "groups_parent_id_index" ON "groups"("parent_id");
+
+CREATE TABLE "group_members" (
+    "group_id" UUID NOT NULL,
+    "user_id" UUID NOT NULL,
+    "created_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE
+    "group_members" ADD PRIMARY KEY("group_id", "user_id");
+
+ALTER TABLE
+    "group_members" ADD CONSTRAINT "group_members_group_id_foreign" FOREIGN KEY("group_id") REFERENCES "groups"("id") ON DELETE CASCADE;
+
+ALTER TABLE
+    "group_members" ADD CONSTRAINT "group_members_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+
+CREATE INDEX "group_members_user_id_index" ON "group_members"("user_id");
+
+CREATE TABLE "group_permissions" (
+    "group_id" UUID NOT NULL,
+    "permission" VARCHAR(100) NOT NULL
+);
+
+ALTER TABLE
+    "group_permissions" ADD PRIMARY KEY("group_id", "permission");
+
+ALTER TABLE
+    "group_permissions" ADD CONSTRAINT "group_permissions_group_id_foreign" FOREIGN KEY("group_id") REFERENCES "groups"("id") ON DELETE CASCADE;
+
+CREATE TABLE "user_permissions" (
+    "user_id" UUID NOT NULL,
+    "permission" VARCHAR(100) NOT NULL
+);
+
+ALTER TABLE
+    "user_permissions" ADD PRIMARY KEY("user_id", "permission");
+
+ALTER TABLE
+    "user_permissions" ADD CONSTRAINT "user_permissions_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
@@ -0,0 +1,2 @@
+ALTER TABLE
+    "refresh_tokens" ALTER COLUMN "expires_at" TYPE BIGINT USING EXTRACT(EPOCH FROM "expires_at" AT TIME ZONE 'UTC')::BIGINT;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE
+    "refresh_tokens" ALTER COLUMN "expires_at" TYPE TIMESTAMP(0) WITHOUT TIME ZONE USING TO_TIMESTAMP("expires_at") AT TIME ZONE 'UTC';
\ No newline at end of file
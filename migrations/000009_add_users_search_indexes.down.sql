@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "users_email_trgm_index";
+DROP INDEX IF EXISTS "users_username_trgm_index";
+DROP INDEX IF EXISTS "users_search_vector_index";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "search_vector";
@@ -0,0 +1,19 @@
+-- The migration runs in one transaction. Adding the stored column rewrites
+-- the users table and building the indexes blocks writes to it, both for
+-- as long as the table takes to scan. On large tables create the indexes
+-- beforehand with CREATE INDEX CONCURRENTLY, see the README; they are then
+-- skipped here.
+CREATE EXTENSION IF NOT EXISTS "pg_trgm";
+
+-- Emails are split on @ and dots, so searching for a domain matches
+ALTER TABLE
+    "users" ADD COLUMN "search_vector" TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', "username"), 'A') ||
+        setweight(to_tsvector('simple', translate("email", '@.', '  ')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS "users_search_vector_index" ON "users" USING GIN("search_vector");
+
+CREATE INDEX IF NOT EXISTS "users_username_trgm_index" ON "users" USING GIN("username" gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS "users_email_trgm_index" ON "users" USING GIN("email" gin_trgm_ops);
@@ -0,0 +1,23 @@
+CREATE TABLE "user_import_jobs" (
+    "id" UUID NOT NULL,
+    "created_by" UUID NULL,
+    "filename" VARCHAR(255) NOT NULL,
+    "dry_run" BOOLEAN NOT NULL DEFAULT FALSE,
+    "status" VARCHAR(255) CHECK ("status" IN('pending', 'running', 'completed', 'failed')) NOT NULL DEFAULT 'pending',
+    "total_rows" INTEGER NOT NULL DEFAULT 0,
+    "processed_rows" INTEGER NOT NULL DEFAULT 0,
+    "succeeded_rows" INTEGER NOT NULL DEFAULT 0,
+    "failed_rows" INTEGER NOT NULL DEFAULT 0,
+    "preview" JSONB NOT NULL DEFAULT '[]',
+    "failures" JSONB NOT NULL DEFAULT '[]',
+    "error" TEXT NOT NULL DEFAULT '',
+    "created_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    "updated_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    "finished_at" TIMESTAMP(0) WITHOUT TIME ZONE NULL
+);
+
+ALTER TABLE
+    "user_import_jobs" ADD PRIMARY KEY("id");
+
+ALTER TABLE
+    "user_import_jobs" ADD CONSTRAINT "user_import_jobs_created_by_foreign" FOREIGN KEY("created_by") REFERENCES "users"("id") ON DELETE SET NULL;
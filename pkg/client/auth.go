@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func (c *Client) canRefresh() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.autoRefresh && c.accessToken != "" && c.refreshToken != ""
+}
+
+// accessTokenExpiring reports whether the access token expires within
+// refreshBefore and can be refreshed.
+func (c *Client) accessTokenExpiring() bool {
+	if !c.canRefresh() {
+		return false
+	}
+
+	accessToken, _ := c.Tokens()
+	expiresAt, ok := tokenExpiry(accessToken)
+
+	return ok && time.Until(expiresAt) < refreshBefore
+}
+
+// refresh rotates the tokens. Concurrent callers wait for the refresh in
+// flight instead of each rotating the refresh token, which the API would
+// treat as reuse.
+func (c *Client) refresh(ctx context.Context) error {
+	c.mu.Lock()
+	if c.refreshing != nil {
+		done := c.refreshing
+		c.mu.Unlock()
+
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	done := make(chan struct{})
+	c.refreshing = done
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		c.refreshing = nil
+		c.mu.Unlock()
+		close(done)
+	}()
+
+	err := c.do(ctx, call{method: http.MethodPost, path: "/api/v1/refresh", refreshCookie: true, noRefresh: true})
+	if IsStatus(err, http.StatusUnauthorized) {
+		// The session is over, stop sending tokens the API rejects
+		c.SetTokens("", "")
+	}
+
+	return err
+}
+
+// tokenExpiry reads the exp claim of a JWT without verifying it, the API
+// does that.
+func tokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		ExpiresAt int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ExpiresAt == 0 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(claims.ExpiresAt, 0), true
+}
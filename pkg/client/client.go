@@ -0,0 +1,335 @@
+// Package client is a typed Go client for the API. The operations in
+// client_gen.go are generated from the route descriptions of the router:
+//
+//	go generate ./pkg/client
+//
+// The client keeps the access and refresh tokens the API sets as cookies,
+// sends the access token as a bearer token and refreshes it before it
+// expires, or once after a 401.
+package client
+
+//go:generate go run ../../cmd/clientgen -o client_gen.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	accessTokenCookie  = "access_token"
+	refreshTokenCookie = "refresh_token"
+	// refreshBefore is how long before expiry the access token is refreshed
+	refreshBefore = 30 * time.Second
+)
+
+// Client calls the API. It is safe for concurrent use.
+type Client struct {
+	baseURL     *url.URL
+	httpClient  *http.Client
+	retry       RetryPolicy
+	autoRefresh bool
+
+	mu           sync.Mutex
+	accessToken  string
+	refreshToken string
+	// refreshing is closed when the refresh in flight ends
+	refreshing chan struct{}
+}
+
+type Option func(*Client)
+
+// WithHTTPClient sends requests through httpClient, e.g. one with a
+// tracing.Transport.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTokens starts with tokens kept from an earlier session.
+func WithTokens(accessToken, refreshToken string) Option {
+	return func(c *Client) {
+		c.accessToken = accessToken
+		c.refreshToken = refreshToken
+	}
+}
+
+// WithRetry replaces DefaultRetryPolicy.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithoutAutoRefresh leaves refreshing the access token to the caller.
+func WithoutAutoRefresh() Option {
+	return func(c *Client) {
+		c.autoRefresh = false
+	}
+}
+
+// New returns a client for the API at baseURL, e.g. "http://localhost:8000".
+func New(baseURL string, options ...Option) (*Client, error) {
+	parsed, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("client: invalid base URL: %w", err)
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("client: base URL %q needs a scheme and host", baseURL)
+	}
+
+	c := &Client{
+		baseURL:     parsed,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		retry:       DefaultRetryPolicy,
+		autoRefresh: true,
+	}
+	for _, option := range options {
+		option(c)
+	}
+
+	return c, nil
+}
+
+// Tokens returns the current access and refresh tokens, to keep them
+// between sessions.
+func (c *Client) Tokens() (accessToken, refreshToken string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.accessToken, c.refreshToken
+}
+
+// SetTokens replaces the tokens, e.g. with the access token of an
+// impersonation session.
+func (c *Client) SetTokens(accessToken, refreshToken string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.accessToken = accessToken
+	c.refreshToken = refreshToken
+}
+
+// call is one operation.
+type call struct {
+	method string
+	path   string
+	query  any
+	body   any
+	// form is sent instead of body with formType, e.g. a multipart upload
+	form     []byte
+	formType string
+	// refreshCookie sends the refresh token cookie, for /refresh and /logout
+	refreshCookie bool
+	// noRefresh is set on the refresh call itself
+	noRefresh bool
+
+	data       any
+	pagination any
+	// download receives the body of successful responses, which are not
+	// decoded
+	download io.Writer
+}
+
+// envelope is response.Response with the data left for decoding into the
+// type of the operation.
+type envelope struct {
+	Success    bool            `json:"success"`
+	Message    string          `json:"message"`
+	Code       Code            `json:"code"`
+	Data       json.RawMessage `json:"data"`
+	Error      json.RawMessage `json:"error"`
+	Pagination json.RawMessage `json:"pagination"`
+}
+
+func (c *Client) do(ctx context.Context, call call) error {
+	if !call.noRefresh && c.accessTokenExpiring() {
+		if err := c.refresh(ctx); err != nil {
+			return err
+		}
+	}
+
+	response, err := c.send(ctx, call)
+	if err != nil {
+		return err
+	}
+
+	// The access token may have expired or been revoked since it was
+	// checked, refresh once and try again
+	if response.StatusCode == http.StatusUnauthorized && !call.noRefresh && c.canRefresh() {
+		response.Body.Close()
+		if err := c.refresh(ctx); err != nil {
+			return err
+		}
+		if response, err = c.send(ctx, call); err != nil {
+			return err
+		}
+	}
+	defer response.Body.Close()
+
+	if call.download != nil && response.StatusCode < http.StatusBadRequest {
+		if _, err := io.Copy(call.download, response.Body); err != nil {
+			return fmt.Errorf("client: downloading %s %s: %w", call.method, call.path, err)
+		}
+		return nil
+	}
+
+	return decode(response, call)
+}
+
+// send makes the request, retrying as the retry policy allows.
+func (c *Client) send(ctx context.Context, call call) (*http.Response, error) {
+	body, contentType := call.form, call.formType
+	if call.body != nil {
+		encoded, err := json.Marshal(call.body)
+		if err != nil {
+			return nil, fmt.Errorf("client: encoding request: %w", err)
+		}
+		body, contentType = encoded, "application/json"
+	}
+
+	for attempt := 1; ; attempt++ {
+		request, err := c.newRequest(ctx, call, body, contentType)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := c.httpClient.Do(request)
+		if err == nil {
+			c.keepTokens(response)
+		}
+
+		wait, retry := c.retry.next(attempt, call.method, response, err)
+		if !retry {
+			return response, err
+		}
+		if response != nil {
+			io.Copy(io.Discard, response.Body)
+			response.Body.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (c *Client) newRequest(ctx context.Context, call call, body []byte, contentType string) (*http.Request, error) {
+	target := *c.baseURL
+	target.Path += call.path
+	if call.query != nil {
+		target.RawQuery = encodeQuery(call.query).Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, call.method, target.String(), reader)
+	if err != nil {
+		return nil, fmt.Errorf("client: %w", err)
+	}
+	request.Header.Set("Accept", "application/json")
+	if call.download != nil {
+		request.Header.Set("Accept", "*/*")
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	accessToken, refreshToken := c.Tokens()
+	if accessToken != "" {
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	// The cookies are set Secure, so a cookie jar would not send them over
+	// plain HTTP; they are added by hand
+	if accessToken != "" && call.refreshCookie {
+		request.AddCookie(&http.Cookie{Name: accessTokenCookie, Value: accessToken})
+	}
+	if refreshToken != "" && call.refreshCookie {
+		request.AddCookie(&http.Cookie{Name: refreshTokenCookie, Value: refreshToken})
+	}
+
+	return request, nil
+}
+
+// keepTokens stores the tokens the API sets, and forgets the ones it clears.
+func (c *Client) keepTokens(response *http.Response) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, cookie := range response.Cookies() {
+		value := cookie.Value
+		if cookie.MaxAge < 0 {
+			value = ""
+		}
+
+		switch cookie.Name {
+		case accessTokenCookie:
+			c.accessToken = value
+		case refreshTokenCookie:
+			c.refreshToken = value
+		}
+	}
+}
+
+func decode(response *http.Response, call call) error {
+	var result envelope
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		if response.StatusCode >= http.StatusBadRequest {
+			return &AppError{Code: response.StatusCode, ErrorCode: codeForStatus(response.StatusCode), Message: http.StatusText(response.StatusCode)}
+		}
+		return fmt.Errorf("client: decoding %s %s: %w", call.method, call.path, err)
+	}
+
+	// Some errors come with data, such as the item results of a failed
+	// atomic bulk operation, which is decoded as well when it can be
+	failed := response.StatusCode >= http.StatusBadRequest || !result.Success
+	if call.data != nil && len(result.Data) > 0 {
+		if err := json.Unmarshal(result.Data, call.data); err != nil && !failed {
+			return fmt.Errorf("client: decoding data of %s %s: %w", call.method, call.path, err)
+		}
+	}
+
+	if failed {
+		return responseError(response.StatusCode, result.Code, result.Error)
+	}
+	if call.pagination != nil && len(result.Pagination) > 0 {
+		if err := json.Unmarshal(result.Pagination, call.pagination); err != nil {
+			return fmt.Errorf("client: decoding pagination of %s %s: %w", call.method, call.path, err)
+		}
+	}
+
+	return nil
+}
+
+// responseError returns a *ValidationError for field errors and an
+// *AppError with the status and error code otherwise.
+func responseError(status int, code Code, raw json.RawMessage) error {
+	var fieldErrors []FieldError
+	if err := json.Unmarshal(raw, &fieldErrors); err == nil && fieldErrors != nil {
+		return &ValidationError{Errors: fieldErrors}
+	}
+
+	var message string
+	if err := json.Unmarshal(raw, &message); err != nil || message == "" {
+		message = http.StatusText(status)
+	}
+
+	if code == "" {
+		code = codeForStatus(status)
+	}
+
+	return &AppError{Code: status, ErrorCode: code, Message: message}
+}
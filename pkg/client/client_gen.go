@@ -0,0 +1,720 @@
+// Code generated by clientgen from router.Routes. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Error codes of the API, see IsCode.
+const (
+	CodeBadRequest                   Code = "bad_request"
+	CodeUnauthorized                 Code = "unauthorized"
+	CodeForbidden                    Code = "forbidden"
+	CodeNotFound                     Code = "not_found"
+	CodeConflict                     Code = "conflict"
+	CodeValidationFailed             Code = "validation_failed"
+	CodeTooManyRequests              Code = "too_many_requests"
+	CodeInternalError                Code = "internal_error"
+	CodeInvalidCredentials           Code = "invalid_credentials"
+	CodeTokenNotFound                Code = "token_not_found"
+	CodeRefreshTokenNotFound         Code = "refresh_token_not_found"
+	CodeInvalidTokenClaims           Code = "invalid_token_claims"
+	CodeMagicLinkInvalid             Code = "magic_link_invalid"
+	CodeUserNotFound                 Code = "user_not_found"
+	CodeUsernameTaken                Code = "username_taken"
+	CodeUserReferenced               Code = "user_referenced"
+	CodeBulkFailed                   Code = "bulk_failed"
+	CodeImportJobNotFound            Code = "import_job_not_found"
+	CodeOrganizationNotFound         Code = "organization_not_found"
+	CodeOrganizationMemberNotFound   Code = "organization_member_not_found"
+	CodeInvitationNotFound           Code = "invitation_not_found"
+	CodeInvitationInvalid            Code = "invitation_invalid"
+	CodeLastOrganizationOwner        Code = "last_organization_owner"
+	CodeGroupNotFound                Code = "group_not_found"
+	CodeGroupMemberNotFound          Code = "group_member_not_found"
+	CodeImpersonationSessionNotFound Code = "impersonation_session_not_found"
+	CodeImpersonationNotAllowed      Code = "impersonation_not_allowed"
+	CodeImpersonationTargetInvalid   Code = "impersonation_target_invalid"
+)
+
+// AddGroupMemberRequest mirrors dto.AddGroupMemberRequest.
+type AddGroupMemberRequest struct {
+	UserID uuid.UUID `json:"user_id" form:"user_id"`
+}
+
+// BulkCreateUsersRequest mirrors dto.BulkCreateUsersRequest.
+type BulkCreateUsersRequest struct {
+	Mode  string              `json:"mode" form:"mode"`
+	Users []CreateUserRequest `json:"users" form:"users"`
+}
+
+// BulkDeleteUsersRequest mirrors dto.BulkDeleteUsersRequest.
+type BulkDeleteUsersRequest struct {
+	Mode string      `json:"mode" form:"mode"`
+	IDs  []uuid.UUID `json:"ids" form:"ids"`
+}
+
+// BulkItemResult mirrors dto.BulkItemResult.
+type BulkItemResult struct {
+	Index   int          `json:"index"`
+	ID      uuid.UUID    `json:"id,omitzero"`
+	Status  string       `json:"status"`
+	Code    Code         `json:"code,omitempty"`
+	Message string       `json:"message,omitempty"`
+	Errors  []FieldError `json:"errors,omitempty"`
+}
+
+// BulkResult mirrors dto.BulkResult.
+type BulkResult struct {
+	Mode      string           `json:"mode"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Items     []BulkItemResult `json:"items"`
+}
+
+// BulkUpdateUsersRequest mirrors dto.BulkUpdateUsersRequest.
+type BulkUpdateUsersRequest struct {
+	Mode  string              `json:"mode" form:"mode"`
+	Users []UpdateUserRequest `json:"users" form:"users"`
+}
+
+// ChangePasswordRequest mirrors dto.ChangePasswordRequest.
+type ChangePasswordRequest struct {
+	CurrentPassword      string `json:"current_password" form:"current_password"`
+	Password             string `json:"password" form:"password"`
+	PasswordConfirmation string `json:"password_confirmation" form:"password_confirmation"`
+}
+
+// CreateGroupRequest mirrors dto.CreateGroupRequest.
+type CreateGroupRequest struct {
+	Name     string     `json:"name" form:"name"`
+	ParentID *uuid.UUID `json:"parent_id" form:"parent_id"`
+	Role     string     `json:"role" form:"role"`
+}
+
+// CreateInvitationRequest mirrors dto.CreateInvitationRequest.
+type CreateInvitationRequest struct {
+	Email string `json:"email" form:"email"`
+	Role  string `json:"role" form:"role"`
+}
+
+// CreateOrganizationRequest mirrors dto.CreateOrganizationRequest.
+type CreateOrganizationRequest struct {
+	Name string `json:"name" form:"name"`
+	Slug string `json:"slug" form:"slug"`
+}
+
+// CreateUserRequest mirrors dto.CreateUserRequest.
+type CreateUserRequest struct {
+	Email                string `json:"email" form:"email"`
+	Username             string `json:"username" form:"username"`
+	Password             string `json:"password" form:"password"`
+	PasswordConfirmation string `json:"password_confirmation" form:"password_confirmation"`
+}
+
+// EffectiveGroup mirrors repository.EffectiveGroup.
+type EffectiveGroup struct {
+	Group
+	Depth int `json:"depth"`
+}
+
+// EffectivePermissions mirrors dto.EffectivePermissions.
+type EffectivePermissions struct {
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
+
+// ExportUsersRequest mirrors dto.ExportUsersRequest.
+type ExportUsersRequest struct {
+	GetUsersFilter
+	Format string `json:"format" form:"format"`
+}
+
+// Fragment mirrors search.Fragment.
+type Fragment struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// GetUsersFilter mirrors dto.GetUsersFilter.
+type GetUsersFilter struct {
+	PaginationRequest
+	Search    string `json:"search" form:"search"`
+	OrderBy   string `json:"order_by" form:"order_by"`
+	OrderType string `json:"order_type" form:"order_type"`
+	Filter    Filter `json:"filter" form:"filter"`
+	Sort      string `json:"sort" form:"sort"`
+	Fields    string `json:"fields" form:"fields"`
+}
+
+// Group mirrors model.Group.
+type Group struct {
+	ID             uuid.UUID  `json:"id"`
+	OrganizationID *uuid.UUID `json:"organization_id"`
+	ParentID       *uuid.UUID `json:"parent_id"`
+	Name           string     `json:"name"`
+	Role           *string    `json:"role"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// GroupMember mirrors model.GroupMember.
+type GroupMember struct {
+	GroupID   uuid.UUID `json:"group_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	User      *User     `json:"user,omitempty"`
+}
+
+// ImpersonateRequest mirrors dto.ImpersonateRequest.
+type ImpersonateRequest struct {
+	Reason          string `json:"reason" form:"reason"`
+	DurationMinutes int    `json:"duration_minutes" form:"duration_minutes"`
+}
+
+// ImpersonationResponse mirrors dto.ImpersonationResponse.
+type ImpersonationResponse struct {
+	SessionID   uuid.UUID `json:"session_id"`
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	User        User      `json:"user"`
+	Actor       User      `json:"actor"`
+}
+
+// ImportRow mirrors model.ImportRow.
+type ImportRow struct {
+	Number   int          `json:"row"`
+	Email    string       `json:"email"`
+	Username string       `json:"username"`
+	Status   string       `json:"status"`
+	ID       uuid.UUID    `json:"id,omitzero"`
+	Code     Code         `json:"code,omitempty"`
+	Message  string       `json:"message,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// Levels mirrors logger.Levels.
+type Levels struct {
+	Level      string            `json:"level"`
+	Components map[string]string `json:"components"`
+}
+
+// LoginRequest mirrors dto.LoginRequest.
+type LoginRequest struct {
+	Username string `json:"username" form:"username"`
+	Password string `json:"password" form:"password"`
+}
+
+// MagicLinkRequest mirrors dto.MagicLinkRequest.
+type MagicLinkRequest struct {
+	Email string `json:"email" form:"email"`
+}
+
+// MagicLinkVerifyRequest mirrors dto.MagicLinkVerifyRequest.
+type MagicLinkVerifyRequest struct {
+	Token string `json:"token" form:"token"`
+}
+
+// Organization mirrors model.Organization.
+type Organization struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OrganizationInvitation mirrors model.OrganizationInvitation.
+type OrganizationInvitation struct {
+	ID             uuid.UUID     `json:"id"`
+	OrganizationID uuid.UUID     `json:"organization_id"`
+	Email          string        `json:"email"`
+	Role           string        `json:"role"`
+	Status         string        `json:"status"`
+	InvitedBy      *uuid.UUID    `json:"invited_by"`
+	CreatedAt      time.Time     `json:"created_at"`
+	ExpiresAt      time.Time     `json:"expires_at"`
+	RespondedAt    *time.Time    `json:"responded_at"`
+	Organization   *Organization `json:"organization,omitempty"`
+}
+
+// OrganizationMember mirrors model.OrganizationMember.
+type OrganizationMember struct {
+	ID             uuid.UUID `json:"id"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	Role           string    `json:"role"`
+	CreatedAt      time.Time `json:"created_at"`
+	User           *User     `json:"user,omitempty"`
+}
+
+// PaginationRequest mirrors dto.PaginationRequest.
+type PaginationRequest struct {
+	Page         int    `json:"page" form:"page"`
+	Limit        int    `json:"limit" form:"limit"`
+	Cursor       string `json:"cursor" form:"cursor"`
+	IncludeTotal *bool  `json:"include_total" form:"include_total"`
+}
+
+// RegisterRequest mirrors dto.RegisterRequest.
+type RegisterRequest struct {
+	Email                string `json:"email" form:"email"`
+	Username             string `json:"username" form:"username"`
+	Password             string `json:"password" form:"password"`
+	PasswordConfirmation string `json:"password_confirmation" form:"password_confirmation"`
+}
+
+// SearchUsersRequest mirrors dto.SearchUsersRequest.
+type SearchUsersRequest struct {
+	Query string `json:"q" form:"q"`
+	Mode  string `json:"mode" form:"mode"`
+	Limit int    `json:"limit" form:"limit"`
+}
+
+// SetPermissionsRequest mirrors dto.SetPermissionsRequest.
+type SetPermissionsRequest struct {
+	Permissions []string `json:"permissions" form:"permissions"`
+}
+
+// Setting mirrors config.Setting.
+type Setting struct {
+	Key        string `json:"key"`
+	Value      any    `json:"value"`
+	Source     string `json:"source"`
+	Reloadable bool   `json:"reloadable"`
+}
+
+// UpdateGroupRequest mirrors dto.UpdateGroupRequest.
+type UpdateGroupRequest struct {
+	ID       uuid.UUID  `json:"id" form:"id"`
+	Name     string     `json:"name" form:"name"`
+	ParentID *uuid.UUID `json:"parent_id" form:"parent_id"`
+	Role     string     `json:"role" form:"role"`
+}
+
+// UpdateLogLevelsRequest mirrors dto.UpdateLogLevelsRequest.
+type UpdateLogLevelsRequest struct {
+	Level      string            `json:"level"`
+	Components map[string]string `json:"components"`
+}
+
+// UpdateOrganizationMemberRequest mirrors dto.UpdateOrganizationMemberRequest.
+type UpdateOrganizationMemberRequest struct {
+	Role string `json:"role" form:"role"`
+}
+
+// UpdateOrganizationRequest mirrors dto.UpdateOrganizationRequest.
+type UpdateOrganizationRequest struct {
+	Name string `json:"name" form:"name"`
+	Slug string `json:"slug" form:"slug"`
+}
+
+// UpdateUserRequest mirrors dto.UpdateUserRequest.
+type UpdateUserRequest struct {
+	ID       uuid.UUID `json:"id" form:"id"`
+	Email    string    `json:"email" form:"email"`
+	Username string    `json:"username" form:"username"`
+}
+
+// User mirrors model.User.
+type User struct {
+	ID        uuid.UUID `json:"id"`
+	Email     string    `json:"email"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	IsBanned  bool      `json:"is_banned"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UserImportJob mirrors model.UserImportJob.
+type UserImportJob struct {
+	ID            uuid.UUID   `json:"id"`
+	CreatedBy     *uuid.UUID  `json:"created_by"`
+	Filename      string      `json:"filename"`
+	DryRun        bool        `json:"dry_run"`
+	Status        string      `json:"status"`
+	TotalRows     int         `json:"total_rows"`
+	ProcessedRows int         `json:"processed_rows"`
+	SucceededRows int         `json:"succeeded_rows"`
+	FailedRows    int         `json:"failed_rows"`
+	Preview       []ImportRow `json:"preview"`
+	Failures      []ImportRow `json:"failures"`
+	Error         string      `json:"error,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	FinishedAt    *time.Time  `json:"finished_at"`
+}
+
+// UserSearchResult mirrors dto.UserSearchResult.
+type UserSearchResult struct {
+	User
+	Rank       float64               `json:"rank"`
+	Highlights map[string][]Fragment `json:"highlights"`
+}
+
+// Login sends POST /api/v1/login.
+// Log in, setting the access_token and refresh_token cookies.
+func (c *Client) Login(ctx context.Context, request LoginRequest) error {
+	return c.do(ctx, call{method: http.MethodPost, path: "/api/v1/login", body: request})
+}
+
+// Register sends POST /api/v1/register.
+// Register a user.
+func (c *Client) Register(ctx context.Context, request RegisterRequest) error {
+	return c.do(ctx, call{method: http.MethodPost, path: "/api/v1/register", body: request})
+}
+
+// RequestMagicLink sends POST /api/v1/magic-link.
+// Email a sign-in link.
+func (c *Client) RequestMagicLink(ctx context.Context, request MagicLinkRequest) error {
+	return c.do(ctx, call{method: http.MethodPost, path: "/api/v1/magic-link", body: request})
+}
+
+// VerifyMagicLink sends GET /api/v1/magic-link/verify.
+// Log in with a sign-in link.
+func (c *Client) VerifyMagicLink(ctx context.Context, query MagicLinkVerifyRequest) error {
+	return c.do(ctx, call{method: http.MethodGet, path: "/api/v1/magic-link/verify", query: query})
+}
+
+// Refresh sends POST /api/v1/refresh.
+// Rotate the access and refresh tokens.
+func (c *Client) Refresh(ctx context.Context) error {
+	return c.do(ctx, call{method: http.MethodPost, path: "/api/v1/refresh", refreshCookie: true, noRefresh: true})
+}
+
+// Logout sends POST /api/v1/logout.
+// Log out and revoke the refresh token.
+func (c *Client) Logout(ctx context.Context) error {
+	return c.do(ctx, call{method: http.MethodPost, path: "/api/v1/logout", refreshCookie: true, noRefresh: true})
+}
+
+// ChangePassword sends PUT /api/v1/password.
+// Change the password, not allowed while impersonating.
+func (c *Client) ChangePassword(ctx context.Context, request ChangePasswordRequest) error {
+	return c.do(ctx, call{method: http.MethodPut, path: "/api/v1/password", body: request})
+}
+
+// StopImpersonation sends DELETE /api/v1/impersonation.
+// End the current impersonation session.
+func (c *Client) StopImpersonation(ctx context.Context) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: "/api/v1/impersonation"})
+}
+
+// ListMyOrganizations sends GET /api/v1/organizations/.
+// List the organizations of the current user.
+func (c *Client) ListMyOrganizations(ctx context.Context) ([]Organization, error) {
+	var data []Organization
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/v1/organizations/", data: &data})
+	return data, err
+}
+
+// CreateOrganization sends POST /api/v1/organizations/.
+// Create an organization owned by the current user.
+func (c *Client) CreateOrganization(ctx context.Context, request CreateOrganizationRequest) (Organization, error) {
+	var data Organization
+	err := c.do(ctx, call{method: http.MethodPost, path: "/api/v1/organizations/", body: request, data: &data})
+	return data, err
+}
+
+// GetOrganization sends GET /api/v1/organizations/:organization.
+// Get an organization by ID or slug.
+func (c *Client) GetOrganization(ctx context.Context, organization string) (Organization, error) {
+	var data Organization
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/v1/organizations/" + url.PathEscape(organization), data: &data})
+	return data, err
+}
+
+// UpdateOrganization sends PUT /api/v1/organizations/:organization.
+// Update an organization, for owners and admins.
+func (c *Client) UpdateOrganization(ctx context.Context, organization string, request UpdateOrganizationRequest) (Organization, error) {
+	var data Organization
+	err := c.do(ctx, call{method: http.MethodPut, path: "/api/v1/organizations/" + url.PathEscape(organization), body: request, data: &data})
+	return data, err
+}
+
+// DeleteOrganization sends DELETE /api/v1/organizations/:organization.
+// Delete an organization, for owners.
+func (c *Client) DeleteOrganization(ctx context.Context, organization string) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: "/api/v1/organizations/" + url.PathEscape(organization)})
+}
+
+// ListOrganizationMembers sends GET /api/v1/organizations/:organization/members.
+// List the members of an organization.
+func (c *Client) ListOrganizationMembers(ctx context.Context, organization string) ([]OrganizationMember, error) {
+	var data []OrganizationMember
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/v1/organizations/" + url.PathEscape(organization) + "/members", data: &data})
+	return data, err
+}
+
+// LeaveOrganization sends POST /api/v1/organizations/:organization/leave.
+// Leave an organization.
+func (c *Client) LeaveOrganization(ctx context.Context, organization string) error {
+	return c.do(ctx, call{method: http.MethodPost, path: "/api/v1/organizations/" + url.PathEscape(organization) + "/leave"})
+}
+
+// UpdateOrganizationMember sends PUT /api/v1/organizations/:organization/members/:user_id.
+// Change the role of a member.
+func (c *Client) UpdateOrganizationMember(ctx context.Context, organization string, userID uuid.UUID, request UpdateOrganizationMemberRequest) error {
+	return c.do(ctx, call{method: http.MethodPut, path: "/api/v1/organizations/" + url.PathEscape(organization) + "/members/" + url.PathEscape(userID.String()), body: request})
+}
+
+// RemoveOrganizationMember sends DELETE /api/v1/organizations/:organization/members/:user_id.
+// Remove a member.
+func (c *Client) RemoveOrganizationMember(ctx context.Context, organization string, userID uuid.UUID) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: "/api/v1/organizations/" + url.PathEscape(organization) + "/members/" + url.PathEscape(userID.String())})
+}
+
+// ListInvitations sends GET /api/v1/organizations/:organization/invitations.
+// List pending invitations.
+func (c *Client) ListInvitations(ctx context.Context, organization string) ([]OrganizationInvitation, error) {
+	var data []OrganizationInvitation
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/v1/organizations/" + url.PathEscape(organization) + "/invitations", data: &data})
+	return data, err
+}
+
+// CreateInvitation sends POST /api/v1/organizations/:organization/invitations.
+// Invite someone by email.
+func (c *Client) CreateInvitation(ctx context.Context, organization string, request CreateInvitationRequest) (OrganizationInvitation, error) {
+	var data OrganizationInvitation
+	err := c.do(ctx, call{method: http.MethodPost, path: "/api/v1/organizations/" + url.PathEscape(organization) + "/invitations", body: request, data: &data})
+	return data, err
+}
+
+// RevokeInvitation sends DELETE /api/v1/organizations/:organization/invitations/:invitation_id.
+// Revoke an invitation.
+func (c *Client) RevokeInvitation(ctx context.Context, organization string, invitationID uuid.UUID) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: "/api/v1/organizations/" + url.PathEscape(organization) + "/invitations/" + url.PathEscape(invitationID.String())})
+}
+
+// GetInvitation sends GET /api/v1/invitations/:token.
+// Get an invitation by its token.
+func (c *Client) GetInvitation(ctx context.Context, token string) (OrganizationInvitation, error) {
+	var data OrganizationInvitation
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/v1/invitations/" + url.PathEscape(token), data: &data})
+	return data, err
+}
+
+// AcceptInvitation sends POST /api/v1/invitations/:token/accept.
+// Accept an invitation as the current user.
+func (c *Client) AcceptInvitation(ctx context.Context, token string) (Organization, error) {
+	var data Organization
+	err := c.do(ctx, call{method: http.MethodPost, path: "/api/v1/invitations/" + url.PathEscape(token) + "/accept", data: &data})
+	return data, err
+}
+
+// DeclineInvitation sends POST /api/v1/invitations/:token/decline.
+// Decline an invitation.
+func (c *Client) DeclineInvitation(ctx context.Context, token string) error {
+	return c.do(ctx, call{method: http.MethodPost, path: "/api/v1/invitations/" + url.PathEscape(token) + "/decline"})
+}
+
+// ListUsers sends GET /api/v1/admin/users/.
+// List users, limited to the members of the organization for organization admins.
+func (c *Client) ListUsers(ctx context.Context, query GetUsersFilter) (Page[User], error) {
+	var page Page[User]
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/v1/admin/users/", query: query, data: &page.Data, pagination: &page.Pagination})
+	return page, err
+}
+
+// ListUsersAll iterates over the items of every page of ListUsers, from the page
+// selected by query on.
+func (c *Client) ListUsersAll(ctx context.Context, query GetUsersFilter) iter.Seq2[User, error] {
+	return paginate(func(cursor string) (Page[User], error) {
+		if cursor != "" {
+			query.Cursor = cursor
+		}
+		return c.ListUsers(ctx, query)
+	})
+}
+
+// SearchUsers sends GET /api/v1/admin/users/search.
+// Search users by username and email, best matches first, tolerating typos.
+func (c *Client) SearchUsers(ctx context.Context, query SearchUsersRequest) ([]UserSearchResult, error) {
+	var data []UserSearchResult
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/v1/admin/users/search", query: query, data: &data})
+	return data, err
+}
+
+// CreateUser sends POST /api/v1/admin/users/.
+// Create a user.
+func (c *Client) CreateUser(ctx context.Context, request CreateUserRequest) error {
+	return c.do(ctx, call{method: http.MethodPost, path: "/api/v1/admin/users/", body: request})
+}
+
+// BulkCreateUsers sends POST /api/v1/admin/users/bulk.
+// Create many users, all or none in atomic mode, with a result per user.
+func (c *Client) BulkCreateUsers(ctx context.Context, request BulkCreateUsersRequest) (BulkResult, error) {
+	var data BulkResult
+	err := c.do(ctx, call{method: http.MethodPost, path: "/api/v1/admin/users/bulk", body: request, data: &data})
+	return data, err
+}
+
+// BulkUpdateUsers sends PUT /api/v1/admin/users/bulk.
+// Update many users by ID, all or none in atomic mode, with a result per user.
+func (c *Client) BulkUpdateUsers(ctx context.Context, request BulkUpdateUsersRequest) (BulkResult, error) {
+	var data BulkResult
+	err := c.do(ctx, call{method: http.MethodPut, path: "/api/v1/admin/users/bulk", body: request, data: &data})
+	return data, err
+}
+
+// BulkDeleteUsers sends DELETE /api/v1/admin/users/bulk.
+// Delete many users by ID, all or none in atomic mode, with a result per user.
+func (c *Client) BulkDeleteUsers(ctx context.Context, request BulkDeleteUsersRequest) (BulkResult, error) {
+	var data BulkResult
+	err := c.do(ctx, call{method: http.MethodDelete, path: "/api/v1/admin/users/bulk", body: request, data: &data})
+	return data, err
+}
+
+// GetUserImportJob sends GET /api/v1/admin/users/import/:id.
+// Get an import job with its progress and failed rows.
+func (c *Client) GetUserImportJob(ctx context.Context, id uuid.UUID) (UserImportJob, error) {
+	var data UserImportJob
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/v1/admin/users/import/" + url.PathEscape(id.String()), data: &data})
+	return data, err
+}
+
+// GetUser sends GET /api/v1/admin/users/:id.
+// Get a user.
+func (c *Client) GetUser(ctx context.Context, id uuid.UUID) (User, error) {
+	var data User
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/v1/admin/users/" + url.PathEscape(id.String()), data: &data})
+	return data, err
+}
+
+// UpdateUser sends PUT /api/v1/admin/users/:id.
+// Update a user.
+func (c *Client) UpdateUser(ctx context.Context, id uuid.UUID, request UpdateUserRequest) error {
+	return c.do(ctx, call{method: http.MethodPut, path: "/api/v1/admin/users/" + url.PathEscape(id.String()), body: request})
+}
+
+// DeleteUser sends DELETE /api/v1/admin/users/:id.
+// Delete a user.
+func (c *Client) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: "/api/v1/admin/users/" + url.PathEscape(id.String())})
+}
+
+// ListUserGroups sends GET /api/v1/admin/users/:id/groups.
+// List the groups a user belongs to, with inherited ones.
+func (c *Client) ListUserGroups(ctx context.Context, id uuid.UUID) ([]EffectiveGroup, error) {
+	var data []EffectiveGroup
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/v1/admin/users/" + url.PathEscape(id.String()) + "/groups", data: &data})
+	return data, err
+}
+
+// GetUserPermissions sends GET /api/v1/admin/users/:id/permissions.
+// Get the effective roles and permissions of a user.
+func (c *Client) GetUserPermissions(ctx context.Context, id uuid.UUID) (EffectivePermissions, error) {
+	var data EffectivePermissions
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/v1/admin/users/" + url.PathEscape(id.String()) + "/permissions", data: &data})
+	return data, err
+}
+
+// SetUserPermissions sends PUT /api/v1/admin/users/:id/permissions.
+// Replace the direct permissions of a user, which are global.
+func (c *Client) SetUserPermissions(ctx context.Context, id uuid.UUID, request SetPermissionsRequest) error {
+	return c.do(ctx, call{method: http.MethodPut, path: "/api/v1/admin/users/" + url.PathEscape(id.String()) + "/permissions", body: request})
+}
+
+// StartImpersonation sends POST /api/v1/admin/users/:id/impersonate.
+// Start acting as a user.
+func (c *Client) StartImpersonation(ctx context.Context, id uuid.UUID, request ImpersonateRequest) (ImpersonationResponse, error) {
+	var data ImpersonationResponse
+	err := c.do(ctx, call{method: http.MethodPost, path: "/api/v1/admin/users/" + url.PathEscape(id.String()) + "/impersonate", body: request, data: &data})
+	return data, err
+}
+
+// GetConfig sends GET /api/v1/admin/config.
+// Show the effective configuration with secrets redacted.
+func (c *Client) GetConfig(ctx context.Context) ([]Setting, error) {
+	var data []Setting
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/v1/admin/config", data: &data})
+	return data, err
+}
+
+// GetLogLevels sends GET /api/v1/admin/log-levels.
+// Show the log levels.
+func (c *Client) GetLogLevels(ctx context.Context) (Levels, error) {
+	var data Levels
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/v1/admin/log-levels", data: &data})
+	return data, err
+}
+
+// UpdateLogLevels sends PUT /api/v1/admin/log-levels.
+// Change log levels until the next restart or config reload.
+func (c *Client) UpdateLogLevels(ctx context.Context, request UpdateLogLevelsRequest) (Levels, error) {
+	var data Levels
+	err := c.do(ctx, call{method: http.MethodPut, path: "/api/v1/admin/log-levels", body: request, data: &data})
+	return data, err
+}
+
+// ListGroups sends GET /api/v1/admin/groups/.
+// List groups.
+func (c *Client) ListGroups(ctx context.Context) ([]Group, error) {
+	var data []Group
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/v1/admin/groups/", data: &data})
+	return data, err
+}
+
+// CreateGroup sends POST /api/v1/admin/groups/.
+// Create a group.
+func (c *Client) CreateGroup(ctx context.Context, request CreateGroupRequest) (Group, error) {
+	var data Group
+	err := c.do(ctx, call{method: http.MethodPost, path: "/api/v1/admin/groups/", body: request, data: &data})
+	return data, err
+}
+
+// GetGroup sends GET /api/v1/admin/groups/:id.
+// Get a group.
+func (c *Client) GetGroup(ctx context.Context, id uuid.UUID) (Group, error) {
+	var data Group
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/v1/admin/groups/" + url.PathEscape(id.String()), data: &data})
+	return data, err
+}
+
+// UpdateGroup sends PUT /api/v1/admin/groups/:id.
+// Update a group.
+func (c *Client) UpdateGroup(ctx context.Context, id uuid.UUID, request UpdateGroupRequest) (Group, error) {
+	var data Group
+	err := c.do(ctx, call{method: http.MethodPut, path: "/api/v1/admin/groups/" + url.PathEscape(id.String()), body: request, data: &data})
+	return data, err
+}
+
+// DeleteGroup sends DELETE /api/v1/admin/groups/:id.
+// Delete a group.
+func (c *Client) DeleteGroup(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: "/api/v1/admin/groups/" + url.PathEscape(id.String())})
+}
+
+// ListGroupMembers sends GET /api/v1/admin/groups/:id/members.
+// List the members of a group.
+func (c *Client) ListGroupMembers(ctx context.Context, id uuid.UUID) ([]GroupMember, error) {
+	var data []GroupMember
+	err := c.do(ctx, call{method: http.MethodGet, path: "/api/v1/admin/groups/" + url.PathEscape(id.String()) + "/members", data: &data})
+	return data, err
+}
+
+// AddGroupMember sends POST /api/v1/admin/groups/:id/members.
+// Add a member to a group.
+func (c *Client) AddGroupMember(ctx context.Context, id uuid.UUID, request AddGroupMemberRequest) error {
+	return c.do(ctx, call{method: http.MethodPost, path: "/api/v1/admin/groups/" + url.PathEscape(id.String()) + "/members", body: request})
+}
+
+// RemoveGroupMember sends DELETE /api/v1/admin/groups/:id/members/:user_id.
+// Remove a member from a group.
+func (c *Client) RemoveGroupMember(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: "/api/v1/admin/groups/" + url.PathEscape(id.String()) + "/members/" + url.PathEscape(userID.String())})
+}
+
+// SetGroupPermissions sends PUT /api/v1/admin/groups/:id/permissions.
+// Replace the permissions of a group.
+func (c *Client) SetGroupPermissions(ctx context.Context, id uuid.UUID, request SetPermissionsRequest) error {
+	return c.do(ctx, call{method: http.MethodPut, path: "/api/v1/admin/groups/" + url.PathEscape(id.String()) + "/permissions", body: request})
+}
@@ -0,0 +1,229 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/response"
+	"github.com/Alfian57/belajar-golang/pkg/client"
+	"github.com/google/uuid"
+)
+
+var noRetry = client.RetryPolicy{MaxAttempts: 1}
+
+func newClient(t *testing.T, handler http.HandlerFunc, options ...client.Option) *client.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	api, err := client.New(server.URL, options...)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	return api
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func TestDecodesEnvelope(t *testing.T) {
+	id := uuid.New()
+	api := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/admin/users/"+id.String() {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer access" {
+			t.Errorf("Authorization = %q", got)
+		}
+		writeJSON(w, http.StatusOK, response.Response{Success: true, Message: "ok", Data: map[string]any{"id": id, "username": "john", "is_banned": true}})
+	}, client.WithTokens("access", "refresh"))
+
+	user, err := api.GetUser(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if user.ID != id || user.Username != "john" || !user.IsBanned {
+		t.Errorf("user = %+v", user)
+	}
+}
+
+func TestMapsErrors(t *testing.T) {
+	api := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/register":
+			writeJSON(w, http.StatusUnprocessableEntity, response.Response{Code: errs.CodeValidationFailed, Error: []errs.FieldError{
+				errs.NewFieldError("email", errs.FieldTaken, "email is already taken"),
+			}})
+		default:
+			writeJSON(w, http.StatusNotFound, response.Response{Code: errs.CodeUserNotFound, Error: "user not found"})
+		}
+	}, client.WithRetry(noRetry))
+
+	err := api.Register(context.Background(), client.RegisterRequest{Email: "john@example.com"})
+	var validationErr *client.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Register error = %#v, want *ValidationError", err)
+	}
+	want := []client.FieldError{{Field: "email", Code: errs.FieldTaken, Error: "email is already taken"}}
+	if !reflect.DeepEqual(validationErr.Errors, want) {
+		t.Errorf("field errors = %+v, want %+v", validationErr.Errors, want)
+	}
+	if !client.IsCode(err, client.CodeValidationFailed) {
+		t.Error("IsCode(validation_failed) = false")
+	}
+
+	_, err = api.GetUser(context.Background(), uuid.New())
+	if !client.IsStatus(err, http.StatusNotFound) || !client.IsCode(err, client.CodeUserNotFound) {
+		t.Fatalf("GetUser error = %#v, want 404 user_not_found", err)
+	}
+	if !errors.Is(err, &client.AppError{ErrorCode: client.CodeUserNotFound}) {
+		t.Error("errors.Is(user_not_found) = false")
+	}
+	if err.Error() != "user not found" {
+		t.Errorf("message = %q", err.Error())
+	}
+}
+
+func TestRefreshesOnUnauthorized(t *testing.T) {
+	var refreshes atomic.Int32
+	api := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/refresh":
+			refreshes.Add(1)
+			if cookie, err := r.Cookie("refresh_token"); err != nil || cookie.Value != "refresh" {
+				t.Errorf("refresh_token cookie = %v, %v", cookie, err)
+			}
+			http.SetCookie(w, &http.Cookie{Name: "access_token", Value: "new-access"})
+			http.SetCookie(w, &http.Cookie{Name: "refresh_token", Value: "new-refresh"})
+			writeJSON(w, http.StatusOK, response.Response{Success: true})
+		default:
+			if r.Header.Get("Authorization") != "Bearer new-access" {
+				writeJSON(w, http.StatusUnauthorized, response.Response{Code: errs.CodeUnauthorized, Error: "unauthorized"})
+				return
+			}
+			writeJSON(w, http.StatusOK, response.Response{Success: true, Data: dto.EffectivePermissions{Roles: []string{"admin"}}})
+		}
+	}, client.WithTokens("expired", "refresh"))
+
+	permissions, err := api.GetUserPermissions(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("GetUserPermissions: %v", err)
+	}
+	if !reflect.DeepEqual(permissions.Roles, []string{"admin"}) {
+		t.Errorf("roles = %v", permissions.Roles)
+	}
+	if refreshes.Load() != 1 {
+		t.Errorf("refreshes = %d, want 1", refreshes.Load())
+	}
+	if accessToken, refreshToken := api.Tokens(); accessToken != "new-access" || refreshToken != "new-refresh" {
+		t.Errorf("tokens = %q, %q", accessToken, refreshToken)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	var attempts atomic.Int32
+	api := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			writeJSON(w, http.StatusServiceUnavailable, response.Response{Code: errs.CodeInternal, Error: "unavailable"})
+			return
+		}
+		writeJSON(w, http.StatusOK, response.Response{Success: true, Data: map[string]any{"name": "Acme"}})
+	}, client.WithRetry(client.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}))
+
+	organization, err := api.GetOrganization(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("GetOrganization: %v", err)
+	}
+	if organization.Name != "Acme" || attempts.Load() != 3 {
+		t.Errorf("organization = %+v after %d attempts", organization, attempts.Load())
+	}
+
+	// A POST may have been applied, it is not retried
+	attempts.Store(0)
+	_, err = api.CreateOrganization(context.Background(), client.CreateOrganizationRequest{Name: "Acme"})
+	if !client.IsStatus(err, http.StatusServiceUnavailable) || attempts.Load() != 1 {
+		t.Errorf("CreateOrganization error = %v after %d attempts", err, attempts.Load())
+	}
+}
+
+func TestPaginates(t *testing.T) {
+	pages := map[string]struct {
+		usernames  []string
+		nextCursor string
+	}{
+		"":   {[]string{"a", "b"}, "c2"},
+		"c2": {[]string{"c"}, ""},
+	}
+
+	api := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if got := query.Get("filter[email][suffix]"); got != "@acme.com" {
+			t.Errorf("filter = %q", got)
+		}
+		page := pages[query.Get("cursor")]
+
+		users := make([]map[string]any, 0, len(page.usernames))
+		for _, username := range page.usernames {
+			users = append(users, map[string]any{"username": username})
+		}
+		writeJSON(w, http.StatusOK, response.Response{Success: true, Data: users, Pagination: &dto.PaginationResponse{
+			Limit: 2, HasNext: page.nextCursor != "", NextCursor: page.nextCursor,
+		}})
+	})
+
+	var usernames []string
+	filter := client.GetUsersFilter{Filter: client.Filter{"email": {"suffix": "@acme.com"}}}
+	for user, err := range api.ListUsersAll(context.Background(), filter) {
+		if err != nil {
+			t.Fatalf("ListUsersAll: %v", err)
+		}
+		usernames = append(usernames, user.Username)
+	}
+
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(usernames, want) {
+		t.Errorf("usernames = %v, want %v", usernames, want)
+	}
+}
+
+// TestHandWrittenTypesMatch fails when a type the client declares by hand
+// no longer decodes what the API sends.
+func TestHandWrittenTypesMatch(t *testing.T) {
+	total := int64(10)
+	assertRoundTrip(t, dto.PaginationResponse{Page: 2, Limit: 5, TotalItems: &total, TotalPages: 2, HasNext: true, HasPrev: true, NextCursor: "n", PrevCursor: "p"}, &client.Pagination{})
+	assertRoundTrip(t, errs.NewFieldError("email", errs.FieldTaken, "taken"), &client.FieldError{})
+	assertRoundTrip(t, errs.NewValidationError([]errs.FieldError{errs.NewFieldError("email", errs.FieldTaken, "taken")}), &client.ValidationError{})
+}
+
+func assertRoundTrip(t *testing.T, sent any, decoded any) {
+	t.Helper()
+
+	want, err := json.Marshal(sent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(want, decoded); err != nil {
+		t.Fatalf("decoding %T into %T: %v", sent, decoded, err)
+	}
+	got, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != string(want) {
+		t.Errorf("%T encodes as %s, %T as %s", decoded, got, sent, want)
+	}
+}
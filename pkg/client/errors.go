@@ -0,0 +1,94 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Code is a stable, machine readable error code of the API.
+type Code string
+
+// FieldError is the error of one field of a request.
+type FieldError struct {
+	Field string `json:"field"`
+	Code  string `json:"code"`
+	Error string `json:"error"`
+}
+
+// ValidationError is returned for requests with invalid fields.
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+// Error implements the error interface for ValidationError.
+func (e *ValidationError) Error() string {
+	if len(e.Errors) == 0 {
+		return "validation errors"
+	}
+	return e.Errors[0].Error
+}
+
+// AppError is returned for other failed calls, with the HTTP status in Code
+// and the error code of the response in ErrorCode.
+type AppError struct {
+	Code      int
+	ErrorCode Code
+	Message   string
+}
+
+// Error implements the error interface for AppError.
+func (e *AppError) Error() string {
+	return e.Message
+}
+
+// Is matches errors with the same error code, so errors.Is(err,
+// &AppError{ErrorCode: CodeUserNotFound}) holds for any user_not_found
+// response.
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	return ok && t.ErrorCode != "" && t.ErrorCode == e.ErrorCode
+}
+
+// IsStatus reports whether err is an *AppError with the status code.
+func IsStatus(err error, status int) bool {
+	var appErr *AppError
+	return errors.As(err, &appErr) && appErr.Code == status
+}
+
+// IsCode reports whether err carries the error code, validation errors have
+// CodeValidationFailed.
+func IsCode(err error, code Code) bool {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return code == CodeValidationFailed
+	}
+
+	var appErr *AppError
+	return errors.As(err, &appErr) && appErr.ErrorCode == code
+}
+
+// codeForStatus returns the generic code of an HTTP status, for responses
+// without a code.
+func codeForStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusUnprocessableEntity:
+		return CodeValidationFailed
+	case http.StatusTooManyRequests:
+		return CodeTooManyRequests
+	}
+
+	if status >= http.StatusInternalServerError {
+		return CodeInternalError
+	}
+	return CodeBadRequest
+}
@@ -0,0 +1,56 @@
+package client
+
+import "iter"
+
+// Pagination describes a page. Page and the totals are left out when the
+// page was selected by cursor or counting was skipped.
+type Pagination struct {
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	TotalItems *int64 `json:"total_items,omitempty"`
+	TotalPages int    `json:"total_pages,omitempty"`
+	HasNext    bool   `json:"has_next"`
+	HasPrev    bool   `json:"has_prev"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// Filter filters listings by field and operator, e.g.
+// Filter{"email": {"suffix": "@acme.com"}}.
+type Filter map[string]map[string]string
+
+// Page is one page of a paginated operation.
+type Page[T any] struct {
+	Data       []T
+	Pagination Pagination
+}
+
+// paginate yields the items of every page, starting with the one selected
+// by the query and following next cursors from there, so items are neither
+// skipped nor repeated while rows are inserted. It stops at the first error,
+// which is yielded with the zero value.
+func paginate[T any](fetch func(cursor string) (Page[T], error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		cursor := ""
+
+		for {
+			page, err := fetch(cursor)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range page.Data {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if !page.Pagination.HasNext || page.Pagination.NextCursor == "" || len(page.Data) == 0 {
+				return
+			}
+			cursor = page.Pagination.NextCursor
+		}
+	}
+}
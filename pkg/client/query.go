@@ -0,0 +1,69 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// encodeQuery encodes the non-zero fields of a query struct by their form
+// tags, the way the API binds them.
+func encodeQuery(query any) url.Values {
+	values := url.Values{}
+	addFields(values, reflect.Indirect(reflect.ValueOf(query)))
+	return values
+}
+
+func addFields(values url.Values, value reflect.Value) {
+	if value.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("form"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			addFields(values, reflect.Indirect(value.Field(i)))
+			continue
+		}
+		if !field.IsExported() || value.Field(i).IsZero() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldValue := reflect.Indirect(value.Field(i))
+		if fieldValue.Kind() == reflect.Map {
+			addMap(values, name, fieldValue)
+			continue
+		}
+		if fieldValue.Kind() == reflect.Slice {
+			for j := 0; j < fieldValue.Len(); j++ {
+				values.Add(name, fmt.Sprint(fieldValue.Index(j).Interface()))
+			}
+			continue
+		}
+
+		values.Set(name, fmt.Sprint(fieldValue.Interface()))
+	}
+}
+
+// addMap encodes maps as deep objects, e.g. filter[created_at][gte]=value.
+func addMap(values url.Values, name string, value reflect.Value) {
+	entries := value.MapRange()
+	for entries.Next() {
+		key := fmt.Sprintf("%s[%v]", name, entries.Key().Interface())
+		element := reflect.Indirect(entries.Value())
+		if element.Kind() == reflect.Map {
+			addMap(values, key, element)
+			continue
+		}
+		values.Set(key, fmt.Sprint(element.Interface()))
+	}
+}
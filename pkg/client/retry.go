@@ -0,0 +1,73 @@
+package client
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy retries requests that failed on the network or with 429, 502,
+// 503 or 504. Except for 429, only idempotent methods are retried, a POST
+// may have been applied before the failure.
+type RetryPolicy struct {
+	// MaxAttempts counts the first attempt, 1 disables retries
+	MaxAttempts int
+	// InitialBackoff doubles after every attempt up to MaxBackoff, with
+	// jitter. A Retry-After header takes precedence.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 200 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+}
+
+// next returns how long to wait before the next attempt, and whether to
+// make one.
+func (p RetryPolicy) next(attempt int, method string, response *http.Response, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts {
+		return 0, false
+	}
+
+	switch {
+	case err != nil:
+		if !idempotent(method) {
+			return 0, false
+		}
+	case response.StatusCode == http.StatusTooManyRequests:
+	case response.StatusCode == http.StatusServiceUnavailable,
+		response.StatusCode == http.StatusBadGateway,
+		response.StatusCode == http.StatusGatewayTimeout:
+		if !idempotent(method) {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	if response != nil {
+		if seconds, err := strconv.Atoi(response.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, p.MaxBackoff), true
+		}
+	}
+
+	backoff := p.InitialBackoff << (attempt - 1)
+	if backoff <= 0 || backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+
+	// Full jitter keeps clients that failed together from retrying together
+	return time.Duration(rand.Int64N(int64(backoff) + 1)), true
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+)
+
+// ImportUsersRequest holds the options of an import, the file is passed to
+// ImportUsers on its own. Format is csv or xlsx, Passwords file or
+// generate.
+type ImportUsersRequest struct {
+	Format    string
+	Mapping   map[string]string
+	DryRun    bool
+	Passwords string
+	Invite    bool
+}
+
+// ExportUsers sends GET /api/v1/admin/users/export and writes the file to w,
+// CSV unless query.Format is xlsx. Large exports may outlast the timeout of
+// the default HTTP client, see WithHTTPClient.
+func (c *Client) ExportUsers(ctx context.Context, query ExportUsersRequest, w io.Writer) error {
+	return c.do(ctx, call{method: http.MethodGet, path: "/api/v1/admin/users/export", query: query, download: w})
+}
+
+// ImportUsers sends POST /api/v1/admin/users/import with the CSV or XLSX
+// file read from file, named filename, and the options of request. Small files come back as a finished job, larger ones as
+// a pending job to follow with GetUserImportJob.
+func (c *Client) ImportUsers(ctx context.Context, request ImportUsersRequest, filename string, file io.Reader) (UserImportJob, error) {
+	// The form is kept in memory so retries can send it again
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+
+	fields := [][2]string{
+		{"format", request.Format},
+		{"passwords", request.Passwords},
+		{"dry_run", strconv.FormatBool(request.DryRun)},
+		{"invite", strconv.FormatBool(request.Invite)},
+	}
+	if len(request.Mapping) > 0 {
+		mapping, err := json.Marshal(request.Mapping)
+		if err != nil {
+			return UserImportJob{}, fmt.Errorf("client: encoding mapping: %w", err)
+		}
+		fields = append(fields, [2]string{"mapping", string(mapping)})
+	}
+	for _, field := range fields {
+		if field[1] == "" {
+			continue
+		}
+		if err := form.WriteField(field[0], field[1]); err != nil {
+			return UserImportJob{}, fmt.Errorf("client: encoding request: %w", err)
+		}
+	}
+
+	part, err := form.CreateFormFile("file", filename)
+	if err != nil {
+		return UserImportJob{}, fmt.Errorf("client: encoding request: %w", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return UserImportJob{}, fmt.Errorf("client: reading %s: %w", filename, err)
+	}
+	if err := form.Close(); err != nil {
+		return UserImportJob{}, fmt.Errorf("client: encoding request: %w", err)
+	}
+
+	var data UserImportJob
+	err = c.do(ctx, call{method: http.MethodPost, path: "/api/v1/admin/users/import", form: body.Bytes(), formType: form.FormDataContentType(), data: &data})
+	return data, err
+}